@@ -2,34 +2,105 @@ package main
 
 import (
 	"context"
-	"github.com/caarlos0/env/v8"
+	"flag"
 	"go.uber.org/zap"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"telegramBot/internal/config"
+	"telegramBot/internal/logger"
+	"telegramBot/pkg/adapter/api/caldav"
+	"telegramBot/pkg/adapter/api/server"
 	"telegramBot/pkg/adapter/api/telegram"
 	"telegramBot/pkg/adapter/cache/redis"
+	"telegramBot/pkg/adapter/metrics"
 	"telegramBot/pkg/adapter/storage/sqlite"
 	"telegramBot/pkg/adapter/todobot"
+	"telegramBot/pkg/adapter/tracing"
+	"telegramBot/pkg/clock"
+	// User timezones must resolve on hosts and images without a zoneinfo
+	// database.
+	_ "time/tzdata"
 )
 
 func main() {
-	l, err := zap.NewProduction()
+	configPath := flag.String("config", os.Getenv("CONFIG_FILE"), "YAML or TOML config file")
+	printConfig := flag.Bool("print-config", false, "print the effective configuration with secrets redacted and exit")
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath)
+	if *printConfig {
+		if printErr := cfg.Print(os.Stdout); printErr != nil {
+			log.Fatal(printErr)
+		}
+	}
+	if err != nil {
+		log.Fatalf("invalid configuration:\n%v", err)
+	}
+	if *printConfig {
+		return
+	}
+
+	l, err := logger.New(cfg)
 	if err != nil {
 		log.Fatal(err)
 	}
-	logger := l.Sugar()
 	defer l.Sync()
-	cfg := config.Config{}
-	if err := env.Parse(&cfg); err != nil {
-		logger.Fatal(err)
+	// SIGTERM cancels ctx: the poll loop, the HTTP server and the background
+	// jobs stop, and buffered spans are still flushed.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	shutdownTracing, err := tracing.Setup(ctx, cfg)
+	if err != nil {
+		l.Fatal("tracing.Setup()", zap.Error(err))
+	}
+	defer shutdownTracing(context.Background())
+
+	database := sqlite.New(cfg, l.Named("sqlite"))
+	storage := tracing.NewStorage(metrics.NewStorage(database))
+	cache := redis.New(cfg, l.Named("redis"))
+	cache.AddHook(metrics.RedisHook{})
+	cache.AddHook(tracing.RedisHook{})
+	logic := todobot.New(storage, cache, l.Named("todobot"))
+	metrics.ActiveConversations(logic.CountActiveConversations)
+	_, err = logic.PurgeStaleDrafts(ctx, cfg.StaleDraftAge)
+	if err != nil {
+		l.Fatal("logic.PurgeStaleDrafts()", zap.Error(err))
 	}
-	storage := sqlite.New()
-	logic := todobot.New(storage)
-	cache := redis.New(cfg)
-	bot := telegram.New(logic, cfg.Token, cache)
-	ctx := context.Background()
+	bot := telegram.New(logic, cache, cfg, l.Named("telegram"))
+
+	httpServer := server.New(cfg.HTTPAddr, l.Named("http"))
+	httpServer.Handle("/metrics", metrics.Handler())
+	httpServer.Handle("/healthz", server.HealthHandler(map[string]server.Check{
+		"telegram": bot.Alive,
+	}))
+	httpServer.Handle("/readyz", server.HealthHandler(map[string]server.Check{
+		"sqlite":   database.Ping,
+		"redis":    cache.Ping,
+		"telegram": bot.Ready,
+	}))
+	httpServer.Handle(server.CalendarPath, server.CalendarHandler(logic, l.Named("calendar")))
+	httpServer.Handle(caldav.Path, caldav.NewHandler(logic, l.Named("caldav")))
+	httpServer.Handle(caldav.WellKnownPath, http.RedirectHandler(caldav.Path, http.StatusMovedPermanently))
+	go todobot.NewDigestScheduler(logic, clock.Real{}, bot.SendDigest).Run(ctx)
+	go todobot.NewTrashPurger(logic, clock.Real{}, cfg.TrashRetention).Run(ctx)
+	go todobot.NewArchiver(logic, clock.Real{}).Run(ctx)
+	serverDone := make(chan struct{})
+	go func() {
+		defer close(serverDone)
+		err := httpServer.Run(ctx)
+		if err != nil {
+			l.Fatal("httpServer.Run()", zap.Error(err))
+		}
+	}()
+
 	err = bot.Run(ctx)
 	if err != nil {
-		logger.Fatal(err)
+		l.Fatal("bot.Run()", zap.Error(err))
 	}
+	stop()
+	<-serverDone
+	l.Info("shut down")
 }
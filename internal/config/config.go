@@ -1,7 +1,48 @@
 package config
 
+import "time"
+
+// Config holds every tunable of the bot. Each field is read from the
+// environment variable in its env tag, or from the lowercased name of that
+// variable in the config file; the environment wins. Secrets may instead be
+// read from a file named by the variable with a _FILE suffix.
 type Config struct {
-	Token         string `env:"TOKEN" `
+	// Token is the Bot API token from @BotFather. Required.
+	Token string `env:"TOKEN" secret:"true"`
+	// DatabasePath is the SQLite database file.
+	DatabasePath string `env:"DB_PATH" envDefault:"./database.db"`
+	// AddrRedis is the host:port of the Redis server.
 	AddrRedis     string `env:"ADDR_REDIS" envDefault:"localhost:6379"`
-	PasswordRedis string `env:"PASSWORD_REDIS" envDefault:""`
+	PasswordRedis string `env:"PASSWORD_REDIS" envDefault:"" secret:"true"`
+	DBRedis       int    `env:"DB_REDIS" envDefault:"0"`
+	// StaleDraftAge is how old a legacy half-created task must be to be
+	// purged at startup.
+	StaleDraftAge time.Duration `env:"STALE_DRAFT_AGE" envDefault:"24h"`
+	// TrashRetention is how long deleted tasks stay in the trash before they
+	// are purged for good.
+	TrashRetention time.Duration `env:"TRASH_RETENTION" envDefault:"720h"`
+	// PollTimeout is how long a getUpdates long poll waits for updates.
+	PollTimeout time.Duration `env:"POLL_TIMEOUT" envDefault:"8s"`
+	// PollRetryTimeout is the pause after a failed getUpdates.
+	PollRetryTimeout time.Duration `env:"POLL_RETRY_TIMEOUT" envDefault:"8s"`
+	// LogLevel is one of debug, info, warn or error. At debug the Bot API
+	// requests are logged too.
+	LogLevel string `env:"LOG_LEVEL" envDefault:"info"`
+	// LogFormat is json or console.
+	LogFormat string `env:"LOG_FORMAT" envDefault:"json"`
+	// HTTPAddr is where /metrics, /healthz, /readyz, the calendar feeds and
+	// the CalDAV server are served.
+	HTTPAddr string `env:"HTTP_ADDR" envDefault:":8080"`
+	// PublicURL is where HTTP_ADDR is reachable from the outside, such as
+	// https://bot.example.com. Calendar feed links are built on it; without
+	// it /ics only sends the file.
+	PublicURL string `env:"PUBLIC_URL" envDefault:""`
+	// PollStaleAfter is how long the long-poll loop may go without a
+	// successful getUpdates before /healthz fails.
+	PollStaleAfter time.Duration `env:"POLL_STALE_AFTER" envDefault:"1m"`
+	// TraceExporter is none, stdout or otlp.
+	TraceExporter string `env:"TRACE_EXPORTER" envDefault:"none"`
+	// OTLPEndpoint is the host:port of an OTLP/HTTP collector.
+	OTLPEndpoint string `env:"OTLP_ENDPOINT" envDefault:"localhost:4318"`
+	OTLPInsecure bool   `env:"OTLP_INSECURE" envDefault:"false"`
 }
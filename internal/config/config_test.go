@@ -0,0 +1,234 @@
+package config
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, name, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	err := os.WriteFile(path, []byte(data), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	return path
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		data string
+		env  map[string]string
+		want func(Config) bool
+	}{
+		{
+			name: "defaults",
+			env:  map[string]string{"TOKEN": "env-token"},
+			want: func(c Config) bool {
+				return c.Token == "env-token" && c.AddrRedis == "localhost:6379" && c.PollTimeout == 8*time.Second
+			},
+		},
+		{
+			name: "yaml over defaults",
+			file: "config.yaml",
+			data: "token: file-token\naddr_redis: redis:6379\npoll_timeout: 20s\ndb_redis: 2\n",
+			want: func(c Config) bool {
+				return c.Token == "file-token" && c.AddrRedis == "redis:6379" && c.PollTimeout == 20*time.Second &&
+					c.DBRedis == 2
+			},
+		},
+		{
+			name: "toml over defaults",
+			file: "config.toml",
+			data: "token = \"file-token\"\nlog_level = \"debug\"\notlp_insecure = true\n",
+			want: func(c Config) bool {
+				return c.Token == "file-token" && c.LogLevel == "debug" && c.OTLPInsecure
+			},
+		},
+		{
+			name: "environment over the file",
+			file: "config.yml",
+			data: "token: file-token\nlog_format: console\n",
+			env:  map[string]string{"TOKEN": "env-token"},
+			want: func(c Config) bool {
+				return c.Token == "env-token" && c.LogFormat == "console"
+			},
+		},
+		{
+			name: "secret from a file",
+			env:  map[string]string{"TOKEN_FILE": "token", "PASSWORD_REDIS_FILE": "password"},
+			want: func(c Config) bool {
+				return c.Token == "file-secret" && c.PasswordRedis == "file-secret"
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var path string
+			if tt.file != "" {
+				path = writeFile(t, tt.file, tt.data)
+			}
+			for key, value := range tt.env {
+				if strings.HasSuffix(key, fileSuffix) {
+					value = writeFile(t, value, "file-secret\n")
+				}
+				t.Setenv(key, value)
+			}
+			cfg, err := Load(path)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if !tt.want(cfg) {
+				t.Errorf("Load() = %+v", cfg)
+			}
+		})
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		data string
+		env  map[string]string
+		want string
+	}{
+		{"unknown format", "config.json", `{"token": "x"}`, nil, "unknown format"},
+		{"unknown setting", "config.yaml", "token: x\ntokn: y\n", nil, `unknown setting "tokn"`},
+		{"nested setting", "config.yaml", "token: x\nredis:\n  addr: y\n", nil, `unknown setting "redis"`},
+		{"list setting", "config.yaml", "token: [x, y]\n", nil, "token must be a single value"},
+		{"broken yaml", "config.yaml", "token: [x\n", nil, "config file"},
+		{"secret twice", "", "", map[string]string{"TOKEN": "x", "TOKEN_FILE": "/nonexistent"}, "both TOKEN and"},
+		{"unreadable secret file", "", "", map[string]string{"TOKEN_FILE": "/nonexistent"}, "TOKEN_FILE"},
+		{"bad duration", "", "", map[string]string{"TOKEN": "x", "POLL_TIMEOUT": "soon"}, "PollTimeout"},
+		{"invalid value", "", "", map[string]string{"TOKEN": "x", "LOG_FORMAT": "xml"}, "LOG_FORMAT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var path string
+			if tt.file != "" {
+				path = writeFile(t, tt.file, tt.data)
+			}
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			_, err := Load(path)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load() error = %v, want one mentioning %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	_, err := Load(filepath.Join(t.TempDir(), "config.yaml"))
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Load() error = %v, want fs.ErrNotExist", err)
+	}
+}
+
+// valid is a configuration that passes Validate.
+func valid() Config {
+	return Config{Token: "x", DatabasePath: "tasks.db", AddrRedis: "localhost:6379", StaleDraftAge: time.Hour,
+		TrashRetention: time.Hour, PollTimeout: 8 * time.Second, PollRetryTimeout: 8 * time.Second,
+		LogLevel: "info", LogFormat: "json", HTTPAddr: ":8080", PollStaleAfter: time.Minute, TraceExporter: "none",
+		OTLPEndpoint: "localhost:4318"}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(*Config)
+		want   []string
+	}{
+		{"valid", func(*Config) {}, nil},
+		{"public url", func(c *Config) { c.PublicURL = "https://bot.example.com" }, nil},
+		{"no token", func(c *Config) { c.Token = "" }, []string{"TOKEN is required"}},
+		{"no database", func(c *Config) { c.DatabasePath = "" }, []string{"DB_PATH"}},
+		{"redis without port", func(c *Config) { c.AddrRedis = "localhost" }, []string{"ADDR_REDIS"}},
+		{"http addr with empty port", func(c *Config) { c.HTTPAddr = "localhost:" }, []string{"HTTP_ADDR"}},
+		{"relative public url", func(c *Config) { c.PublicURL = "bot.example.com" }, []string{"PUBLIC_URL"}},
+		{"negative redis db", func(c *Config) { c.DBRedis = -1 }, []string{"DB_REDIS"}},
+		{"zero retention", func(c *Config) { c.TrashRetention = 0 }, []string{"TRASH_RETENTION"}},
+		{"fractional poll timeout", func(c *Config) { c.PollTimeout = 1500 * time.Millisecond },
+			[]string{"POLL_TIMEOUT"}},
+		{"long poll timeout", func(c *Config) { c.PollTimeout = time.Minute }, []string{"POLL_TIMEOUT"}},
+		{"stale before a poll ends", func(c *Config) { c.PollStaleAfter = 16 * time.Second },
+			[]string{"POLL_STALE_AFTER must exceed"}},
+		{"log level", func(c *Config) { c.LogLevel = "loud" }, []string{"LOG_LEVEL"}},
+		{"otlp endpoint", func(c *Config) { c.TraceExporter, c.OTLPEndpoint = "otlp", "collector" },
+			[]string{"OTLP_ENDPOINT"}},
+		{"exporter", func(c *Config) { c.TraceExporter = "jaeger" }, []string{"TRACE_EXPORTER"}},
+		{"all at once", func(c *Config) { c.Token, c.LogFormat = "", "xml" }, []string{"TOKEN", "LOG_FORMAT"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid()
+			tt.change(&cfg)
+			err := cfg.Validate()
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Validate() error = nil, want %q", tt.want)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Validate() error = %v, want one mentioning %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPrintRedactsSecrets(t *testing.T) {
+	cfg := valid()
+	cfg.Token = "123:secret-token"
+	cfg.PasswordRedis = ""
+	var out strings.Builder
+	err := cfg.Print(&out)
+	if err != nil {
+		t.Fatalf("Print() error = %v", err)
+	}
+	printed := out.String()
+	for _, want := range []string{`token: "<redacted>"`, `password_redis: ""`, `poll_timeout: "8s"`,
+		`db_redis: 0`, `addr_redis: "localhost:6379"`} {
+		if !strings.Contains(printed, want+"\n") {
+			t.Errorf("Print() lacks %s:\n%s", want, printed)
+		}
+	}
+	if strings.Contains(printed, "secret-token") {
+		t.Errorf("Print() leaks the token:\n%s", printed)
+	}
+
+	// The output reads back as a config file.
+	path := writeFile(t, "config.yaml", strings.Replace(printed, `"<redacted>"`, `"123:secret-token"`, 1))
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load(Print()) error = %v", err)
+	}
+	if loaded != cfg {
+		t.Errorf("Load(Print()) = %+v, want %+v", loaded, cfg)
+	}
+
+	cfg.PasswordRedis = "secret-password"
+	out.Reset()
+	err = cfg.Print(&out)
+	if err != nil {
+		t.Fatalf("Print() error = %v", err)
+	}
+	if printed := out.String(); !strings.Contains(printed, `password_redis: "<redacted>"`+"\n") ||
+		strings.Contains(printed, "secret-password") {
+		t.Errorf("Print() does not redact the Redis password:\n%s", printed)
+	}
+}
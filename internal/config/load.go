@@ -0,0 +1,130 @@
+package config
+
+import (
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"github.com/caarlos0/env/v8"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+const fileSuffix = "_FILE"
+
+// Load builds the configuration from the defaults, the optional YAML or TOML
+// file at path, and the environment, in increasing order of precedence, and
+// validates the result.
+func Load(path string) (Config, error) {
+	environment := make(map[string]string)
+	if path != "" {
+		values, err := readFile(path)
+		if err != nil {
+			return Config{}, err
+		}
+		for key, value := range values {
+			environment[key] = value
+		}
+	}
+	variables := make(map[string]string)
+	for _, entry := range os.Environ() {
+		key, value, _ := strings.Cut(entry, "=")
+		variables[key] = value
+	}
+	err := resolveFiles(variables)
+	if err != nil {
+		return Config{}, err
+	}
+	for key, value := range variables {
+		environment[key] = value
+	}
+
+	var cfg Config
+	err = env.ParseWithOptions(&cfg, env.Options{Environment: environment})
+	if err != nil {
+		return Config{}, err
+	}
+	return cfg, cfg.Validate()
+}
+
+// keys lists the variable names of all fields in declaration order.
+func keys() []string {
+	configType := reflect.TypeOf(Config{})
+	keys := make([]string, 0, configType.NumField())
+	for i := 0; i < configType.NumField(); i++ {
+		keys = append(keys, envKey(configType.Field(i)))
+	}
+	return keys
+}
+
+func envKey(field reflect.StructField) string {
+	key, _, _ := strings.Cut(field.Tag.Get("env"), ",")
+	return key
+}
+
+// readFile returns the settings of a config file keyed by variable name.
+// Keys in the file are the variable names in lower case.
+func readFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("config file: %w", err)
+	}
+	var values map[string]any
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &values)
+	case ".toml":
+		err = toml.Unmarshal(data, &values)
+	default:
+		return nil, fmt.Errorf("config file %s: unknown format, use .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	known := make(map[string]bool)
+	for _, key := range keys() {
+		known[key] = true
+	}
+	result := make(map[string]string, len(values))
+	for name, value := range values {
+		key := strings.ToUpper(name)
+		if !known[key] {
+			return nil, fmt.Errorf("config file %s: unknown setting %q", path, name)
+		}
+		switch value.(type) {
+		case map[string]any, []any:
+			return nil, fmt.Errorf("config file %s: %s must be a single value", path, name)
+		}
+		result[key] = fmt.Sprint(value)
+	}
+	return result, nil
+}
+
+// resolveFiles replaces every secret that has a NAME_FILE variable with the
+// content of that file, so secrets can come from mounted files rather than
+// the environment.
+func resolveFiles(variables map[string]string) error {
+	configType := reflect.TypeOf(Config{})
+	for i := 0; i < configType.NumField(); i++ {
+		field := configType.Field(i)
+		if field.Tag.Get("secret") != "true" {
+			continue
+		}
+		key := envKey(field)
+		path, ok := variables[key+fileSuffix]
+		if !ok {
+			continue
+		}
+		if _, ok := variables[key]; ok {
+			return fmt.Errorf("both %s and %s%s are set", key, key, fileSuffix)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("%s%s: %w", key, fileSuffix, err)
+		}
+		variables[key] = strings.TrimSpace(string(data))
+	}
+	return nil
+}
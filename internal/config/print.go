@@ -0,0 +1,37 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const redacted = "<redacted>"
+
+// Print writes cfg in the config file's YAML format, with secrets redacted.
+func (c Config) Print(w io.Writer) error {
+	value := reflect.ValueOf(c)
+	configType := value.Type()
+	for i := 0; i < configType.NumField(); i++ {
+		field := configType.Field(i)
+		var text string
+		switch {
+		case field.Tag.Get("secret") == "true" && !value.Field(i).IsZero():
+			text = strconv.Quote(redacted)
+		case field.Type == reflect.TypeOf(time.Duration(0)):
+			text = strconv.Quote(value.Field(i).Interface().(time.Duration).String())
+		case field.Type.Kind() == reflect.String:
+			text = strconv.Quote(value.Field(i).String())
+		default:
+			text = fmt.Sprint(value.Field(i).Interface())
+		}
+		_, err := fmt.Fprintf(w, "%s: %s\n", strings.ToLower(envKey(field)), text)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"go.uber.org/zap/zapcore"
+	"net"
+	"net/url"
+	"time"
+)
+
+// maxPollTimeout keeps a long poll shorter than the Bot API request timeout.
+const maxPollTimeout = 50 * time.Second
+
+// Validate reports every invalid setting at once.
+func (c Config) Validate() error {
+	var errs []error
+	if c.Token == "" {
+		errs = append(errs, errors.New("TOKEN is required"))
+	}
+	if c.DatabasePath == "" {
+		errs = append(errs, errors.New("DB_PATH must not be empty"))
+	}
+	errs = append(errs, validateAddr("ADDR_REDIS", c.AddrRedis), validateAddr("HTTP_ADDR", c.HTTPAddr))
+	if c.PublicURL != "" {
+		u, err := url.Parse(c.PublicURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("PUBLIC_URL: %q is not an http or https URL", c.PublicURL))
+		}
+	}
+	if c.DBRedis < 0 {
+		errs = append(errs, fmt.Errorf("DB_REDIS: %d is not a database number", c.DBRedis))
+	}
+	for key, duration := range map[string]time.Duration{
+		"STALE_DRAFT_AGE":    c.StaleDraftAge,
+		"TRASH_RETENTION":    c.TrashRetention,
+		"POLL_RETRY_TIMEOUT": c.PollRetryTimeout,
+		"POLL_STALE_AFTER":   c.PollStaleAfter,
+	} {
+		if duration <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive, got %s", key, duration))
+		}
+	}
+	if c.PollTimeout < 0 || c.PollTimeout > maxPollTimeout || c.PollTimeout%time.Second != 0 {
+		errs = append(errs, fmt.Errorf("POLL_TIMEOUT must be whole seconds between 0s and %s, got %s",
+			maxPollTimeout, c.PollTimeout))
+	}
+	if c.PollStaleAfter <= c.PollTimeout+c.PollRetryTimeout {
+		errs = append(errs, fmt.Errorf("POLL_STALE_AFTER must exceed POLL_TIMEOUT plus POLL_RETRY_TIMEOUT (%s)",
+			c.PollTimeout+c.PollRetryTimeout))
+	}
+	if _, err := zapcore.ParseLevel(c.LogLevel); err != nil {
+		errs = append(errs, fmt.Errorf("LOG_LEVEL: %w", err))
+	}
+	if c.LogFormat != "json" && c.LogFormat != "console" {
+		errs = append(errs, fmt.Errorf("LOG_FORMAT must be json or console, got %q", c.LogFormat))
+	}
+	switch c.TraceExporter {
+	case "none", "stdout":
+	case "otlp":
+		errs = append(errs, validateAddr("OTLP_ENDPOINT", c.OTLPEndpoint))
+	default:
+		errs = append(errs, fmt.Errorf("TRACE_EXPORTER must be none, stdout or otlp, got %q", c.TraceExporter))
+	}
+	return errors.Join(errs...)
+}
+
+func validateAddr(key, addr string) error {
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("%s: %q is not host:port: %w", key, addr, err)
+	}
+	if port == "" {
+		return fmt.Errorf("%s: %q has no port", key, addr)
+	}
+	return nil
+}
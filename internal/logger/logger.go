@@ -0,0 +1,26 @@
+package logger
+
+import (
+	"fmt"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"telegramBot/internal/config"
+)
+
+func New(cfg config.Config) (*zap.Logger, error) {
+	level, err := zapcore.ParseLevel(cfg.LogLevel)
+	if err != nil {
+		return nil, fmt.Errorf("LOG_LEVEL: %w", err)
+	}
+	var zapConfig zap.Config
+	switch cfg.LogFormat {
+	case "json":
+		zapConfig = zap.NewProductionConfig()
+	case "console":
+		zapConfig = zap.NewDevelopmentConfig()
+	default:
+		return nil, fmt.Errorf("LOG_FORMAT: unknown format %q", cfg.LogFormat)
+	}
+	zapConfig.Level = zap.NewAtomicLevelAt(level)
+	return zapConfig.Build()
+}
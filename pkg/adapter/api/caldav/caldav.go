@@ -0,0 +1,257 @@
+// Package caldav lets CalDAV clients (RFC 4791) sync tasks as VTODOs. It
+// implements what task sync needs and no more: discovery with PROPFIND,
+// REPORT queries, and GET, PUT and DELETE of single tasks guarded by ETags.
+// Each list is a calendar collection; tasks without one are in the inbox.
+// Clients sign in with the user's Telegram ID and an app password.
+package caldav
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"telegramBot/pkg/adapter/todobot"
+	"telegramBot/pkg/model/task"
+	"telegramBot/pkg/model/task/status"
+)
+
+const (
+	// Path is where the server is mounted.
+	Path = "/caldav/"
+	// WellKnownPath is where clients look for the server, RFC 6764.
+	WellKnownPath = "/.well-known/caldav"
+	// Inbox is the collection of the tasks without a list.
+	Inbox = "inbox"
+
+	realm   = "Tasks"
+	maxBody = 1 << 20
+)
+
+// Backend is what the server needs from the application.
+type Backend interface {
+	CheckAppPassword(ctx context.Context, userID int64, password string) error
+	GetListOfTasks(ctx context.Context, userID int64) ([]task.Task, error)
+	SyncTask(ctx context.Context, userID int64, t task.Task) (task.Task, error)
+	DeleteTaskByID(ctx context.Context, userID, taskID int64) error
+}
+
+type Handler struct {
+	backend Backend
+	log     *zap.Logger
+}
+
+func NewHandler(backend Backend, log *zap.Logger) *Handler {
+	return &Handler{backend: backend, log: log}
+}
+
+// target is what a request path names. A zero userID is the server root, an
+// empty collection the user's principal, which is also their calendar home,
+// and an empty resource the collection itself.
+type target struct {
+	userID     int64
+	collection string
+	resource   string
+}
+
+func (t target) href() string {
+	switch {
+	case t.userID == 0:
+		return Path
+	case t.collection == "":
+		return fmt.Sprintf("%s%d/", Path, t.userID)
+	case t.resource == "":
+		return fmt.Sprintf("%s%d/%s/", Path, t.userID, url.PathEscape(t.collection))
+	}
+	return fmt.Sprintf("%s%d/%s/%s", Path, t.userID, url.PathEscape(t.collection), url.PathEscape(t.resource))
+}
+
+// parseTarget reads a request path, or an href a client sent in a REPORT.
+// It works on the escaped path, as list names may hold a slash.
+func parseTarget(escapedPath string) (target, bool) {
+	rest, ok := strings.CutPrefix(escapedPath, Path)
+	if !ok {
+		return target{}, escapedPath+"/" == Path
+	}
+	var segments []string
+	for _, segment := range strings.Split(strings.TrimSuffix(rest, "/"), "/") {
+		segment, err := url.PathUnescape(segment)
+		if err != nil {
+			return target{}, false
+		}
+		segments = append(segments, segment)
+	}
+	var t target
+	if segments[0] == "" {
+		return t, len(segments) == 1
+	}
+	userID, err := strconv.ParseInt(segments[0], 10, 64)
+	if err != nil || userID == 0 || len(segments) > 3 {
+		return target{}, false
+	}
+	for _, segment := range segments[1:] {
+		if segment == "" {
+			return target{}, false
+		}
+	}
+	t.userID = userID
+	if len(segments) > 1 {
+		t.collection = segments[1]
+	}
+	if len(segments) > 2 {
+		t.resource = segments[2]
+	}
+	return t, true
+}
+
+// collectionOf names the collection a list's tasks are in.
+func collectionOf(list string) string {
+	if list == "" {
+		return Inbox
+	}
+	return list
+}
+
+// resourceOf names the file a task is served as.
+func resourceOf(t task.Task) string {
+	if t.Resource != "" {
+		return t.Resource
+	}
+	return fmt.Sprintf("task-%d.ics", t.ID)
+}
+
+func etag(data []byte) string {
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	t, ok := parseTarget(r.URL.EscapedPath())
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method == http.MethodOptions {
+		w.Header().Set("DAV", "1, 3, calendar-access")
+		w.Header().Set("Allow", "OPTIONS, PROPFIND, REPORT, GET, HEAD, PUT, DELETE")
+		return
+	}
+	userID, ok := h.authenticate(r)
+	if !ok {
+		w.Header().Set("WWW-Authenticate", `Basic realm="`+realm+`", charset="UTF-8"`)
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+	// Another user's tasks are as missing as tasks that do not exist.
+	if t.userID != 0 && t.userID != userID {
+		http.NotFound(w, r)
+		return
+	}
+
+	var err error
+	switch r.Method {
+	case "PROPFIND":
+		err = h.propfind(w, r, t, userID)
+	case "REPORT":
+		err = h.report(w, r, t, userID)
+	case http.MethodGet, http.MethodHead:
+		err = h.get(w, r, t)
+	case http.MethodPut:
+		err = h.put(w, r, t)
+	case http.MethodDelete:
+		err = h.delete(w, r, t)
+	default:
+		w.Header().Set("Allow", "OPTIONS, PROPFIND, REPORT, GET, HEAD, PUT, DELETE")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+	var httpErr *httpError
+	switch {
+	case errors.As(err, &httpErr):
+		http.Error(w, httpErr.message, httpErr.code)
+	case errors.Is(err, todobot.ErrTaskNotFound), errors.Is(err, todobot.ErrNotOwner):
+		http.NotFound(w, r)
+	case err != nil:
+		h.log.Error("ServeHTTP()", zap.String("method", r.Method), zap.Int64("userID", userID), zap.Error(err))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+}
+
+type httpError struct {
+	code    int
+	message string
+}
+
+func (e *httpError) Error() string {
+	return fmt.Sprintf("%d %s", e.code, e.message)
+}
+
+func statusError(code int) error {
+	return &httpError{code: code, message: http.StatusText(code)}
+}
+
+// authenticate checks the Basic credentials: the Telegram user ID and one of
+// its app passwords.
+func (h *Handler) authenticate(r *http.Request) (int64, bool) {
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		return 0, false
+	}
+	userID, err := strconv.ParseInt(username, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	err = h.backend.CheckAppPassword(r.Context(), userID, password)
+	if err != nil {
+		if !errors.Is(err, todobot.ErrInvalidAppPassword) {
+			h.log.Error("authenticate()", zap.Int64("userID", userID), zap.Error(err))
+		}
+		return 0, false
+	}
+	return userID, true
+}
+
+// collections returns the user's tasks by collection, the inbox always
+// among them.
+func (h *Handler) collections(ctx context.Context, userID int64) (map[string][]task.Task, error) {
+	tasks, err := h.backend.GetListOfTasks(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	collections := map[string][]task.Task{Inbox: nil}
+	for _, t := range tasks {
+		if t.Status == status.Creating {
+			continue
+		}
+		collections[collectionOf(t.List)] = append(collections[collectionOf(t.List)], t)
+	}
+	return collections, nil
+}
+
+func sortedNames(collections map[string][]task.Task) []string {
+	names := make([]string, 0, len(collections))
+	for name := range collections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// find returns the task t names, or ErrTaskNotFound.
+func (h *Handler) find(ctx context.Context, t target) (task.Task, error) {
+	collections, err := h.collections(ctx, t.userID)
+	if err != nil {
+		return task.Task{}, err
+	}
+	for _, candidate := range collections[t.collection] {
+		if resourceOf(candidate) == t.resource {
+			return candidate, nil
+		}
+	}
+	return task.Task{}, todobot.ErrTaskNotFound
+}
@@ -0,0 +1,271 @@
+package caldav
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"telegramBot/internal/config"
+	"telegramBot/pkg/adapter/storage/sqlite"
+	"telegramBot/pkg/adapter/todobot"
+	"telegramBot/pkg/model/task"
+	"testing"
+)
+
+const testUser = 42
+
+type testServer struct {
+	*httptest.Server
+	bot      *todobot.TodoBot
+	password string
+}
+
+// newTestServer serves a fresh store holding one task in the inbox, named
+// "Buy milk".
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	db := sqlite.New(config.Config{DatabasePath: filepath.Join(t.TempDir(), "tasks.db")}, zap.NewNop())
+	bot := todobot.New(db, nil, zap.NewNop())
+	ctx := context.Background()
+	password, err := bot.NewAppPassword(ctx, testUser)
+	if err != nil {
+		t.Fatalf("NewAppPassword() error = %v", err)
+	}
+	_, err = bot.SyncTask(ctx, testUser, task.Task{TaskName: "Buy milk", Resource: "milk.ics"})
+	if err != nil {
+		t.Fatalf("SyncTask() error = %v", err)
+	}
+	server := httptest.NewServer(NewHandler(bot, zap.NewNop()))
+	t.Cleanup(server.Close)
+	return &testServer{Server: server, bot: bot, password: password}
+}
+
+func (s *testServer) do(t *testing.T, method, path, body string, header map[string]string) *http.Response {
+	t.Helper()
+	r, err := http.NewRequest(method, s.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("http.NewRequest() error = %v", err)
+	}
+	r.SetBasicAuth(fmt.Sprint(testUser), s.password)
+	for name, value := range header {
+		r.Header.Set(name, value)
+	}
+	resp, err := http.DefaultClient.Do(r)
+	if err != nil {
+		t.Fatalf("%s %s error = %v", method, path, err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func readBody(t *testing.T, resp *http.Response) string {
+	t.Helper()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("io.ReadAll() error = %v", err)
+	}
+	return string(body)
+}
+
+func wantStatus(t *testing.T, resp *http.Response, code int) {
+	t.Helper()
+	if resp.StatusCode != code {
+		t.Fatalf("%s %s status = %d, want %d", resp.Request.Method, resp.Request.URL.Path, resp.StatusCode, code)
+	}
+}
+
+type multistatus struct {
+	Responses []struct {
+		Href      string `xml:"DAV: href"`
+		Propstats []struct {
+			Status string `xml:"DAV: status"`
+			Prop   struct {
+				DisplayName  string `xml:"DAV: displayname"`
+				ETag         string `xml:"DAV: getetag"`
+				CalendarData string `xml:"urn:ietf:params:xml:ns:caldav calendar-data"`
+			} `xml:"DAV: prop"`
+		} `xml:"DAV: propstat"`
+	} `xml:"DAV: response"`
+}
+
+func readMultistatus(t *testing.T, resp *http.Response) multistatus {
+	t.Helper()
+	wantStatus(t, resp, http.StatusMultiStatus)
+	var m multistatus
+	err := xml.Unmarshal([]byte(readBody(t, resp)), &m)
+	if err != nil {
+		t.Fatalf("xml.Unmarshal() error = %v", err)
+	}
+	return m
+}
+
+func todo(lines ...string) string {
+	return "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//Test//EN\r\nBEGIN:VTODO\r\n" +
+		strings.Join(lines, "\r\n") + "\r\nEND:VTODO\r\nEND:VCALENDAR\r\n"
+}
+
+var (
+	userPath  = fmt.Sprintf("%s%d/", Path, testUser)
+	inboxPath = userPath + Inbox + "/"
+	milkPath  = inboxPath + "milk.ics"
+)
+
+func TestAuthentication(t *testing.T) {
+	s := newTestServer(t)
+	tests := []struct {
+		name     string
+		username string
+		password string
+	}{
+		{"no credentials", "", ""},
+		{"wrong password", fmt.Sprint(testUser), "not-the-password"},
+		{"other user", "43", s.password},
+		{"username not an ID", "alice", s.password},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := http.NewRequest("PROPFIND", s.URL+userPath, nil)
+			if err != nil {
+				t.Fatalf("http.NewRequest() error = %v", err)
+			}
+			if tt.username != "" {
+				r.SetBasicAuth(tt.username, tt.password)
+			}
+			resp, err := http.DefaultClient.Do(r)
+			if err != nil {
+				t.Fatalf("PROPFIND error = %v", err)
+			}
+			defer resp.Body.Close()
+			wantStatus(t, resp, http.StatusUnauthorized)
+			if resp.Header.Get("WWW-Authenticate") == "" {
+				t.Error("WWW-Authenticate is missing")
+			}
+		})
+	}
+}
+
+func TestPropfind(t *testing.T) {
+	s := newTestServer(t)
+	m := readMultistatus(t, s.do(t, "PROPFIND", userPath, `<?xml version="1.0"?>
+<d:propfind xmlns:d="DAV:"><d:prop><d:displayname/></d:prop></d:propfind>`, map[string]string{"Depth": "1"}))
+	names := map[string]string{}
+	for _, r := range m.Responses {
+		names[r.Href] = r.Propstats[0].Prop.DisplayName
+	}
+	if names[inboxPath] != "Inbox" {
+		t.Errorf("displayname of %s = %q, want Inbox; responses: %v", inboxPath, names[inboxPath], names)
+	}
+
+	m = readMultistatus(t, s.do(t, "PROPFIND", inboxPath, "", map[string]string{"Depth": "1"}))
+	if len(m.Responses) != 2 || m.Responses[1].Href != milkPath {
+		t.Errorf("inbox PROPFIND responses = %+v, want the inbox and %s", m.Responses, milkPath)
+	}
+}
+
+func TestReport(t *testing.T) {
+	s := newTestServer(t)
+	m := readMultistatus(t, s.do(t, "REPORT", inboxPath, `<?xml version="1.0"?>
+<c:calendar-query xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+  <d:prop><d:getetag/></d:prop>
+  <c:filter><c:comp-filter name="VCALENDAR"><c:comp-filter name="VTODO"/></c:comp-filter></c:filter>
+</c:calendar-query>`, map[string]string{"Depth": "1"}))
+	if len(m.Responses) != 1 || m.Responses[0].Href != milkPath || m.Responses[0].Propstats[0].Prop.ETag == "" {
+		t.Fatalf("calendar-query responses = %+v, want %s with an ETag", m.Responses, milkPath)
+	}
+
+	m = readMultistatus(t, s.do(t, "REPORT", inboxPath, `<?xml version="1.0"?>
+<c:calendar-multiget xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+  <d:prop><d:getetag/><c:calendar-data/></d:prop>
+  <d:href>`+milkPath+`</d:href>
+  <d:href>`+inboxPath+`missing.ics</d:href>
+</c:calendar-multiget>`, nil))
+	if len(m.Responses) != 2 {
+		t.Fatalf("calendar-multiget responses = %+v, want 2", m.Responses)
+	}
+	if data := m.Responses[0].Propstats[0].Prop.CalendarData; !strings.Contains(data, "SUMMARY:Buy milk") {
+		t.Errorf("calendar-data = %q, want the task", data)
+	}
+	if len(m.Responses[1].Propstats) != 0 {
+		t.Errorf("missing resource has properties: %+v", m.Responses[1])
+	}
+}
+
+func TestGet(t *testing.T) {
+	s := newTestServer(t)
+	resp := s.do(t, http.MethodGet, milkPath, "", nil)
+	wantStatus(t, resp, http.StatusOK)
+	if resp.Header.Get("ETag") == "" {
+		t.Error("ETag is missing")
+	}
+	if body := readBody(t, resp); !strings.Contains(body, "SUMMARY:Buy milk") {
+		t.Errorf("body = %q, want the task", body)
+	}
+	wantStatus(t, s.do(t, http.MethodGet, inboxPath+"missing.ics", "", nil), http.StatusNotFound)
+}
+
+func TestPut(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+
+	wantStatus(t, s.do(t, http.MethodPut, inboxPath+"rent.ics",
+		todo("UID:rent@example.com", "SUMMARY:Pay rent", "DUE;VALUE=DATE:20260105"),
+		map[string]string{"If-None-Match": "*"}), http.StatusCreated)
+	wantStatus(t, s.do(t, http.MethodPut, inboxPath+"rent.ics", todo("SUMMARY:Pay rent again"),
+		map[string]string{"If-None-Match": "*"}), http.StatusPreconditionFailed)
+
+	resp := s.do(t, http.MethodGet, milkPath, "", nil)
+	wantStatus(t, resp, http.StatusOK)
+	current := resp.Header.Get("ETag")
+	wantStatus(t, s.do(t, http.MethodPut, milkPath, todo("SUMMARY:Buy oat milk"),
+		map[string]string{"If-Match": `"stale"`}), http.StatusPreconditionFailed)
+	// The alarm's properties belong to the alarm, not to the task.
+	wantStatus(t, s.do(t, http.MethodPut, milkPath, todo(
+		"UID:milk@example.com",
+		"SUMMARY:Buy oat milk",
+		"DESCRIPTION:Two cartons",
+		"BEGIN:VALARM",
+		"ACTION:DISPLAY",
+		"DESCRIPTION:Reminder",
+		"TRIGGER:-PT15M",
+		"END:VALARM",
+	), map[string]string{"If-Match": current}), http.StatusNoContent)
+
+	tasks, err := s.bot.GetListOfTasks(ctx, testUser)
+	if err != nil {
+		t.Fatalf("GetListOfTasks() error = %v", err)
+	}
+	byName := map[string]task.Task{}
+	for _, candidate := range tasks {
+		byName[candidate.TaskName] = candidate
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("tasks = %+v, want 2", tasks)
+	}
+	if rent := byName["Pay rent"]; rent.UID != "rent@example.com" || rent.Due.Format("20060102") != "20260105" {
+		t.Errorf("created task = %+v", rent)
+	}
+	milk, ok := byName["Buy oat milk"]
+	if !ok || milk.TaskDescription != "Two cartons" || milk.UID != "milk@example.com" {
+		t.Errorf("updated task = %+v, want its own description and UID", milk)
+	}
+
+	wantStatus(t, s.do(t, http.MethodPut, inboxPath+"event.ics",
+		"BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nSUMMARY:Party\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n", nil),
+		http.StatusForbidden)
+	wantStatus(t, s.do(t, http.MethodPut, userPath+"nowhere/task.ics", todo("SUMMARY:Lost"), nil),
+		http.StatusConflict)
+}
+
+func TestDelete(t *testing.T) {
+	s := newTestServer(t)
+	wantStatus(t, s.do(t, http.MethodDelete, milkPath, "", map[string]string{"If-Match": `"stale"`}),
+		http.StatusPreconditionFailed)
+	wantStatus(t, s.do(t, http.MethodDelete, milkPath, "", nil), http.StatusNoContent)
+	wantStatus(t, s.do(t, http.MethodGet, milkPath, "", nil), http.StatusNotFound)
+	wantStatus(t, s.do(t, http.MethodDelete, milkPath, "", nil), http.StatusNotFound)
+}
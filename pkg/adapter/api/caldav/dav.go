@@ -0,0 +1,410 @@
+package caldav
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"telegramBot/pkg/ical"
+	"telegramBot/pkg/model/task"
+)
+
+const (
+	nsDAV            = "DAV:"
+	nsCalDAV         = "urn:ietf:params:xml:ns:caldav"
+	nsCalendarServer = "http://calendarserver.org/ns/"
+)
+
+var prefixes = map[string]string{nsDAV: "d", nsCalDAV: "c", nsCalendarServer: "cs"}
+
+var (
+	resourceType         = xml.Name{Space: nsDAV, Local: "resourcetype"}
+	displayName          = xml.Name{Space: nsDAV, Local: "displayname"}
+	currentUserPrincipal = xml.Name{Space: nsDAV, Local: "current-user-principal"}
+	principalURL         = xml.Name{Space: nsDAV, Local: "principal-URL"}
+	privilegeSet         = xml.Name{Space: nsDAV, Local: "current-user-privilege-set"}
+	supportedReportSet   = xml.Name{Space: nsDAV, Local: "supported-report-set"}
+	getETag              = xml.Name{Space: nsDAV, Local: "getetag"}
+	getContentType       = xml.Name{Space: nsDAV, Local: "getcontenttype"}
+	calendarHomeSet      = xml.Name{Space: nsCalDAV, Local: "calendar-home-set"}
+	supportedComponents  = xml.Name{Space: nsCalDAV, Local: "supported-calendar-component-set"}
+	calendarData         = xml.Name{Space: nsCalDAV, Local: "calendar-data"}
+	getCTag              = xml.Name{Space: nsCalendarServer, Local: "getctag"}
+)
+
+// todoContentType is how tasks are served, and PUT may send them.
+const todoContentType = "text/calendar; charset=utf-8; component=vtodo"
+
+type anyElement struct {
+	XMLName xml.Name
+}
+
+type propElement struct {
+	Names []anyElement `xml:",any"`
+}
+
+type propfindBody struct {
+	XMLName  xml.Name     `xml:"DAV: propfind"`
+	AllProp  *struct{}    `xml:"DAV: allprop"`
+	PropName *struct{}    `xml:"DAV: propname"`
+	Prop     *propElement `xml:"DAV: prop"`
+}
+
+type compFilter struct {
+	Name        string       `xml:"name,attr"`
+	CompFilters []compFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+}
+
+type reportBody struct {
+	XMLName xml.Name
+	Prop    *propElement `xml:"DAV: prop"`
+	Hrefs   []string     `xml:"DAV: href"`
+	Filter  struct {
+		CompFilter compFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+	} `xml:"urn:ietf:params:xml:ns:caldav filter"`
+}
+
+// properties maps the properties of a resource to their XML content.
+type properties map[xml.Name]string
+
+// response is one resource of a multistatus: its properties, or just a
+// status when it does not exist.
+type response struct {
+	href    string
+	status  int
+	found   properties
+	missing []xml.Name
+}
+
+// selection is the properties a PROPFIND or REPORT asks for. A nil names
+// means all of them.
+type selection struct {
+	names     []xml.Name
+	namesOnly bool
+}
+
+func (s selection) response(href string, props properties) response {
+	r := response{href: href, found: make(properties)}
+	if s.names == nil {
+		for name, value := range props {
+			if s.namesOnly {
+				value = ""
+			}
+			r.found[name] = value
+		}
+		return r
+	}
+	for _, name := range s.names {
+		if value, ok := props[name]; ok {
+			r.found[name] = value
+		} else {
+			r.missing = append(r.missing, name)
+		}
+	}
+	return r
+}
+
+func parseSelection(prop *propElement) selection {
+	var s selection
+	if prop == nil {
+		return s
+	}
+	s.names = []xml.Name{}
+	for _, element := range prop.Names {
+		s.names = append(s.names, element.XMLName)
+	}
+	return s
+}
+
+func readXML(r *http.Request, v any) (empty bool, err error) {
+	body, err := io.ReadAll(http.MaxBytesReader(nil, r.Body, maxBody))
+	if err != nil {
+		return false, statusError(http.StatusRequestEntityTooLarge)
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return true, nil
+	}
+	err = xml.Unmarshal(body, v)
+	if err != nil {
+		return false, &httpError{code: http.StatusBadRequest, message: "malformed XML: " + err.Error()}
+	}
+	return false, nil
+}
+
+func (h *Handler) propfind(w http.ResponseWriter, r *http.Request, t target, userID int64) error {
+	var body propfindBody
+	empty, err := readXML(r, &body)
+	if err != nil {
+		return err
+	}
+	var s selection
+	switch {
+	case empty || body.AllProp != nil:
+	case body.PropName != nil:
+		s.namesOnly = true
+	default:
+		s = parseSelection(body.Prop)
+	}
+	depth := r.Header.Get("Depth") != "0"
+
+	ctx := r.Context()
+	collections, err := h.collections(ctx, userID)
+	if err != nil {
+		return err
+	}
+	principal := target{userID: userID}
+	var responses []response
+	switch {
+	case t.userID == 0:
+		responses = append(responses, s.response(t.href(), rootProperties(principal)))
+		if depth {
+			responses = append(responses, s.response(principal.href(), principalProperties(principal)))
+		}
+	case t.collection == "":
+		responses = append(responses, s.response(t.href(), principalProperties(principal)))
+		if depth {
+			for _, name := range sortedNames(collections) {
+				collection := target{userID: userID, collection: name}
+				responses = append(responses, s.response(collection.href(),
+					collectionProperties(principal, name, collections[name])))
+			}
+		}
+	case t.resource == "":
+		tasks, ok := collections[t.collection]
+		if !ok {
+			return statusError(http.StatusNotFound)
+		}
+		responses = append(responses, s.response(t.href(), collectionProperties(principal, t.collection, tasks)))
+		if depth {
+			for _, candidate := range tasks {
+				props, err := taskProperties(principal, candidate)
+				if err != nil {
+					return err
+				}
+				item := target{userID: userID, collection: t.collection, resource: resourceOf(candidate)}
+				responses = append(responses, s.response(item.href(), props))
+			}
+		}
+	default:
+		found, err := h.find(ctx, t)
+		if err != nil {
+			return err
+		}
+		props, err := taskProperties(principal, found)
+		if err != nil {
+			return err
+		}
+		responses = append(responses, s.response(t.href(), props))
+	}
+	writeMultistatus(w, responses)
+	return nil
+}
+
+func (h *Handler) report(w http.ResponseWriter, r *http.Request, t target, userID int64) error {
+	var body reportBody
+	_, err := readXML(r, &body)
+	if err != nil {
+		return err
+	}
+	s := parseSelection(body.Prop)
+	if body.Prop == nil {
+		s.names = []xml.Name{getETag}
+	}
+	principal := target{userID: userID}
+
+	var responses []response
+	switch body.XMLName {
+	case xml.Name{Space: nsCalDAV, Local: "calendar-multiget"}:
+		for _, href := range body.Hrefs {
+			responses = append(responses, h.multigetResponse(r.Context(), principal, href, s))
+		}
+	case xml.Name{Space: nsCalDAV, Local: "calendar-query"}:
+		if t.userID == 0 || t.collection == "" || t.resource != "" {
+			return statusError(http.StatusForbidden)
+		}
+		collections, err := h.collections(r.Context(), t.userID)
+		if err != nil {
+			return err
+		}
+		tasks, ok := collections[t.collection]
+		if !ok {
+			return statusError(http.StatusNotFound)
+		}
+		if !wantsTodos(body.Filter.CompFilter) {
+			tasks = nil
+		}
+		for _, candidate := range tasks {
+			props, err := taskProperties(principal, candidate)
+			if err != nil {
+				return err
+			}
+			item := target{userID: t.userID, collection: t.collection, resource: resourceOf(candidate)}
+			responses = append(responses, s.response(item.href(), props))
+		}
+	default:
+		return &httpError{code: http.StatusForbidden, message: "unsupported report " + body.XMLName.Local}
+	}
+	writeMultistatus(w, responses)
+	return nil
+}
+
+func (h *Handler) multigetResponse(ctx context.Context, principal target, href string, s selection) response {
+	missing := response{href: href, status: http.StatusNotFound}
+	u, err := url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return missing
+	}
+	t, ok := parseTarget(u.EscapedPath())
+	if !ok || t.userID != principal.userID || t.resource == "" {
+		return missing
+	}
+	found, err := h.find(ctx, t)
+	if err != nil {
+		return missing
+	}
+	props, err := taskProperties(principal, found)
+	if err != nil {
+		return missing
+	}
+	return s.response(href, props)
+}
+
+// wantsTodos reports whether a calendar-query filter can match a VTODO.
+// Filters below the component, such as time ranges, are not applied: clients
+// get a superset and sort it out themselves.
+func wantsTodos(filter compFilter) bool {
+	if len(filter.CompFilters) == 0 {
+		return true
+	}
+	for _, component := range filter.CompFilters {
+		if strings.EqualFold(component.Name, "VTODO") {
+			return true
+		}
+	}
+	return false
+}
+
+func href(target target) string {
+	return "<d:href>" + escape(target.href()) + "</d:href>"
+}
+
+func rootProperties(principal target) properties {
+	return properties{
+		resourceType:         "<d:collection/>",
+		currentUserPrincipal: href(principal),
+	}
+}
+
+func principalProperties(principal target) properties {
+	return properties{
+		resourceType:         "<d:collection/><d:principal/>",
+		displayName:          escape(fmt.Sprint(principal.userID)),
+		currentUserPrincipal: href(principal),
+		principalURL:         href(principal),
+		calendarHomeSet:      href(principal),
+		privilegeSet:         "<d:privilege><d:read/></d:privilege>",
+	}
+}
+
+func collectionProperties(principal target, name string, tasks []task.Task) properties {
+	display := name
+	if name == Inbox {
+		display = "Inbox"
+	}
+	// The ctag changes whenever a task of the collection does.
+	ctag := sha256.New()
+	for _, t := range tasks {
+		fmt.Fprintf(ctag, "%d:", t.ID)
+		_ = ical.WriteTodo(ctag, t)
+	}
+	return properties{
+		resourceType:         "<d:collection/><c:calendar/>",
+		displayName:          escape(display),
+		currentUserPrincipal: href(principal),
+		supportedComponents:  `<c:comp name="VTODO"/>`,
+		supportedReportSet: "<d:supported-report><d:report><c:calendar-multiget/></d:report></d:supported-report>" +
+			"<d:supported-report><d:report><c:calendar-query/></d:report></d:supported-report>",
+		privilegeSet: "<d:privilege><d:read/></d:privilege><d:privilege><d:write/></d:privilege>" +
+			"<d:privilege><d:write-content/></d:privilege><d:privilege><d:bind/></d:privilege>" +
+			"<d:privilege><d:unbind/></d:privilege>",
+		getCTag: escape(hex.EncodeToString(ctag.Sum(nil)[:16])),
+	}
+}
+
+func taskProperties(principal target, t task.Task) (properties, error) {
+	var data bytes.Buffer
+	err := ical.WriteTodo(&data, t)
+	if err != nil {
+		return nil, err
+	}
+	return properties{
+		resourceType:         "",
+		currentUserPrincipal: href(principal),
+		getETag:              escape(etag(data.Bytes())),
+		getContentType:       todoContentType,
+		calendarData:         escape(data.String()),
+	}, nil
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// element writes a property with its content. Namespaces other than the
+// three declared on the multistatus are declared in place.
+func element(name xml.Name, content string) string {
+	prefix, declare := prefixes[name.Space], ""
+	if prefix == "" {
+		prefix, declare = "x", ` xmlns:x="`+escape(name.Space)+`"`
+	}
+	if content == "" {
+		return "<" + prefix + ":" + name.Local + declare + "/>"
+	}
+	return "<" + prefix + ":" + name.Local + declare + ">" + content + "</" + prefix + ":" + name.Local + ">"
+}
+
+func statusLine(code int) string {
+	return fmt.Sprintf("<d:status>HTTP/1.1 %d %s</d:status>", code, http.StatusText(code))
+}
+
+func writeMultistatus(w http.ResponseWriter, responses []response) {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="utf-8"?>` + "\n")
+	b.WriteString(`<d:multistatus xmlns:d="DAV:" xmlns:c="` + nsCalDAV + `" xmlns:cs="` + nsCalendarServer + `">`)
+	for _, r := range responses {
+		b.WriteString("<d:response><d:href>" + escape(r.href) + "</d:href>")
+		if r.status != 0 {
+			b.WriteString(statusLine(r.status) + "</d:response>")
+			continue
+		}
+		if len(r.found) > 0 {
+			b.WriteString("<d:propstat><d:prop>")
+			for name, content := range r.found {
+				b.WriteString(element(name, content))
+			}
+			b.WriteString("</d:prop>" + statusLine(http.StatusOK) + "</d:propstat>")
+		}
+		if len(r.missing) > 0 {
+			b.WriteString("<d:propstat><d:prop>")
+			for _, name := range r.missing {
+				b.WriteString(element(name, ""))
+			}
+			b.WriteString("</d:prop>" + statusLine(http.StatusNotFound) + "</d:propstat>")
+		}
+		b.WriteString("</d:response>")
+	}
+	b.WriteString("</d:multistatus>\n")
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusMultiStatus)
+	// A client that went away is not worth a log line.
+	_, _ = io.WriteString(w, b.String())
+}
@@ -0,0 +1,165 @@
+package caldav
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"telegramBot/pkg/adapter/todobot"
+	"telegramBot/pkg/ical"
+	"telegramBot/pkg/model/task"
+)
+
+func (h *Handler) get(w http.ResponseWriter, r *http.Request, t target) error {
+	if t.resource == "" {
+		return statusError(http.StatusNotFound)
+	}
+	found, err := h.find(r.Context(), t)
+	if err != nil {
+		return err
+	}
+	var data bytes.Buffer
+	err = ical.WriteTodo(&data, found)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", todoContentType)
+	w.Header().Set("ETag", etag(data.Bytes()))
+	if r.Method == http.MethodHead {
+		return nil
+	}
+	_, _ = w.Write(data.Bytes())
+	return nil
+}
+
+// put creates or replaces a task. The stored task is not byte for byte what
+// the client sent, so no ETag is returned and the client fetches it again.
+func (h *Handler) put(w http.ResponseWriter, r *http.Request, t target) error {
+	if t.resource == "" {
+		return statusError(http.StatusMethodNotAllowed)
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(nil, r.Body, maxBody))
+	if err != nil {
+		return statusError(http.StatusRequestEntityTooLarge)
+	}
+	ctx := r.Context()
+	collections, err := h.collections(ctx, t.userID)
+	if err != nil {
+		return err
+	}
+	if _, ok := collections[t.collection]; !ok {
+		return &httpError{code: http.StatusConflict, message: "no such calendar"}
+	}
+	existing, err := h.find(ctx, t)
+	exists := err == nil
+	if err != nil && !errors.Is(err, todobot.ErrTaskNotFound) {
+		return err
+	}
+	err = checkPreconditions(r, existing, exists)
+	if err != nil {
+		return err
+	}
+	parsed, err := ical.ParseTodo(body)
+	if errors.Is(err, ical.ErrNoTodo) || errors.Is(err, ical.ErrUnsupportedComponent) {
+		return &httpError{code: http.StatusForbidden, message: err.Error()}
+	}
+	if err != nil {
+		return &httpError{code: http.StatusBadRequest, message: "invalid calendar data: " + err.Error()}
+	}
+
+	parsed.List = t.collection
+	if t.collection == Inbox {
+		parsed.List = ""
+	}
+	if exists {
+		parsed.ID = existing.ID
+		parsed.Resource = existing.Resource
+		// A list named like the inbox stays where it was.
+		if collectionOf(existing.List) == t.collection {
+			parsed.List = existing.List
+		}
+		// The UID served for a task created elsewhere is derived, not stored.
+		if existing.UID == "" && parsed.UID == ical.UID(existing) {
+			parsed.UID = ""
+		}
+	} else {
+		parsed.Resource = t.resource
+	}
+	// The list is served as a category too; it is not a tag.
+	tags := parsed.Tags[:0]
+	for _, tag := range parsed.Tags {
+		if tag != parsed.List && tag != strings.ToLower(t.collection) {
+			tags = append(tags, tag)
+		}
+	}
+	parsed.Tags = tags
+
+	_, err = h.backend.SyncTask(ctx, t.userID, parsed)
+	if err != nil {
+		return err
+	}
+	if exists {
+		w.WriteHeader(http.StatusNoContent)
+	} else {
+		w.WriteHeader(http.StatusCreated)
+	}
+	return nil
+}
+
+func (h *Handler) delete(w http.ResponseWriter, r *http.Request, t target) error {
+	if t.resource == "" {
+		return statusError(http.StatusForbidden)
+	}
+	found, err := h.find(r.Context(), t)
+	if err != nil {
+		return err
+	}
+	err = checkPreconditions(r, found, true)
+	if err != nil {
+		return err
+	}
+	err = h.backend.DeleteTaskByID(r.Context(), t.userID, int64(found.ID))
+	if err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// checkPreconditions applies If-Match and If-None-Match, which clients send
+// so as not to overwrite changes they have not seen.
+func checkPreconditions(r *http.Request, current task.Task, exists bool) error {
+	ifMatch, ifNoneMatch := r.Header.Get("If-Match"), r.Header.Get("If-None-Match")
+	if ifMatch == "" && ifNoneMatch == "" {
+		return nil
+	}
+	var currentETag string
+	if exists {
+		var data bytes.Buffer
+		err := ical.WriteTodo(&data, current)
+		if err != nil {
+			return err
+		}
+		currentETag = etag(data.Bytes())
+	}
+	if ifMatch != "" && !(exists && matchesETag(ifMatch, currentETag)) {
+		return statusError(http.StatusPreconditionFailed)
+	}
+	if ifNoneMatch != "" && exists && matchesETag(ifNoneMatch, currentETag) {
+		return statusError(http.StatusPreconditionFailed)
+	}
+	return nil
+}
+
+// matchesETag reports whether an If-Match or If-None-Match header lists
+// current, or is "*".
+func matchesETag(header, current string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == current {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"strings"
+	"telegramBot/pkg/adapter/todobot"
+	"telegramBot/pkg/ical"
+)
+
+// CalendarPath prefixes the feed URLs, which end in the calendar token and
+// .ics.
+const CalendarPath = "/calendar/"
+
+// Calendars is what CalendarHandler needs from the application.
+type Calendars interface {
+	CalendarUser(ctx context.Context, token string) (int64, error)
+	WriteCalendar(ctx context.Context, userID int64, w io.Writer) error
+}
+
+// CalendarHandler serves each user's iCalendar feed to whoever knows its
+// token. Unknown tokens get the same 404 as any other missing page.
+func CalendarHandler(calendars Calendars, log *zap.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		token := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, CalendarPath), ".ics")
+		userID, err := calendars.CalendarUser(r.Context(), token)
+		if errors.Is(err, todobot.ErrCalendarNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		var feed bytes.Buffer
+		if err == nil {
+			err = calendars.WriteCalendar(r.Context(), userID, &feed)
+		}
+		if err != nil {
+			// The token is a secret, so only the user it belongs to is logged.
+			log.Error("CalendarHandler()", zap.Int64("userID", userID), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", ical.ContentType)
+		w.Header().Set("Content-Disposition", `inline; filename="tasks.ics"`)
+		w.Header().Set("Cache-Control", "private, max-age=300")
+		w.Header().Set("Referrer-Policy", "no-referrer")
+		if r.Method == http.MethodHead {
+			return
+		}
+		_, _ = w.Write(feed.Bytes())
+	})
+}
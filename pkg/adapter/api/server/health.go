@@ -0,0 +1,43 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+const checkTimeout = 2 * time.Second
+
+type Check func(ctx context.Context) error
+
+type healthReport struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+// HealthHandler runs every check and answers 200 when all pass, 503 with the
+// failing ones otherwise.
+func HealthHandler(checks map[string]Check) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+		defer cancel()
+
+		report := healthReport{Status: "ok", Checks: make(map[string]string, len(checks))}
+		for name, check := range checks {
+			err := check(ctx)
+			if err != nil {
+				report.Status = "fail"
+				report.Checks[name] = err.Error()
+				continue
+			}
+			report.Checks[name] = "ok"
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if report.Status != "ok" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(report)
+	})
+}
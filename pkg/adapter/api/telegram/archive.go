@@ -0,0 +1,103 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"github.com/mymmrac/telego"
+	tu "github.com/mymmrac/telego/telegoutil"
+	"html"
+	"strconv"
+	"strings"
+	"telegramBot/pkg/i18n"
+	"telegramBot/pkg/model/state/telegram"
+)
+
+// archiveHandler opens the archive, searched for query unless it is empty.
+func (t *Telegram) archiveHandler(ctx context.Context, chatID int64, query string) error {
+	err := t.deleteMessages(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	err = t.cache.SetArchiveQuery(ctx, chatID, strings.TrimSpace(query))
+	if err != nil {
+		return err
+	}
+	return t.archivePageHandler(ctx, chatID, 0, "")
+}
+
+func (t *Telegram) archivePageHandler(ctx context.Context, chatID int64, page int, notice string) error {
+	l := i18n.FromContext(ctx)
+	query, err := t.cache.GetArchiveQuery(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	result, err := t.todoBot.SearchArchive(ctx, chatID, query, searchPageSize, page*searchPageSize)
+	if err != nil {
+		return err
+	}
+	// The last task of the last page may just have left the archive.
+	if len(result.Matches) == 0 && page > 0 {
+		return t.archivePageHandler(ctx, chatID, page-1, notice)
+	}
+
+	var text strings.Builder
+	if notice != "" {
+		fmt.Fprintf(&text, "%s\n\n", html.EscapeString(notice))
+	}
+	switch {
+	case result.Total == 0 && query == "":
+		text.WriteString(html.EscapeString(l.T(i18n.ArchiveEmpty)))
+		return t.render(ctx, chatID, text.String(), withMenu(l, nil))
+	case result.Total == 0:
+		text.WriteString(l.T(i18n.ArchiveNothing, html.EscapeString(query)))
+		return t.render(ctx, chatID, text.String(), withMenu(l, nil))
+	}
+
+	first := page*searchPageSize + 1
+	last := first + len(result.Matches) - 1
+	if query == "" {
+		text.WriteString(l.T(i18n.ArchiveHeader, first, last, result.Total))
+	} else {
+		text.WriteString(l.T(i18n.ArchiveSearchHeader, html.EscapeString(query), first, last, result.Total))
+	}
+	text.WriteString("\n")
+	var row []telego.InlineKeyboardButton
+	for i, match := range result.Matches {
+		fmt.Fprintf(&text, "\n%d. <b>%s</b>", first+i, html.EscapeString(match.Task.TaskName))
+		if query != "" && match.Snippet != "" {
+			fmt.Fprintf(&text, "\n%s", renderSnippet(match.Snippet))
+		}
+		row = append(row, tu.InlineKeyboardButton("📤 "+strconv.Itoa(first+i)).
+			WithCallbackData(fmt.Sprintf("%s%d:%d", telegram.UnarchiveButton, match.Task.ID, page)))
+	}
+	text.WriteString("\n\n" + html.EscapeString(l.T(i18n.ArchiveHint)))
+
+	rows := [][]telego.InlineKeyboardButton{row}
+	var navigation []telego.InlineKeyboardButton
+	if page > 0 {
+		navigation = append(navigation, tu.InlineKeyboardButton("◀").
+			WithCallbackData(telegram.ArchivePageButton+strconv.Itoa(page-1)))
+	}
+	if last < result.Total {
+		navigation = append(navigation, tu.InlineKeyboardButton("▶").
+			WithCallbackData(telegram.ArchivePageButton+strconv.Itoa(page+1)))
+	}
+	if len(navigation) > 0 {
+		rows = append(rows, navigation)
+	}
+	return t.render(ctx, chatID, text.String(), withMenu(l, tu.InlineKeyboard(rows...)))
+}
+
+// unarchiveHandler moves a task back to the list and stays on the archive
+// page it was picked from.
+func (t *Telegram) unarchiveHandler(ctx context.Context, chatID, taskID int64, page int) error {
+	l := i18n.FromContext(ctx)
+	err := t.todoBot.UnarchiveTask(ctx, chatID, taskID)
+	if message, ok := userMessage(l, err); ok {
+		return t.archivePageHandler(ctx, chatID, page, message)
+	}
+	if err != nil {
+		return err
+	}
+	return t.archivePageHandler(ctx, chatID, page, l.T(i18n.TaskUnarchived))
+}
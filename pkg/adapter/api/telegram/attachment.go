@@ -0,0 +1,78 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"github.com/mymmrac/telego"
+	tu "github.com/mymmrac/telego/telegoutil"
+	"html"
+	todoBot "telegramBot/pkg/adapter/todobot"
+	"telegramBot/pkg/i18n"
+	"telegramBot/pkg/model/task"
+)
+
+// attachmentOf reads the photo, document or voice message a message carries.
+func attachmentOf(message *telego.Message) (task.Attachment, bool) {
+	switch {
+	case message == nil:
+	case len(message.Photo) > 0:
+		// Telegram sends every size of a photo, the largest last.
+		photo := message.Photo[len(message.Photo)-1]
+		return task.Attachment{Kind: task.Photo, FileID: photo.FileID, MimeType: "image/jpeg",
+			Size: int64(photo.FileSize)}, true
+	case message.Document != nil:
+		return task.Attachment{Kind: task.Document, FileID: message.Document.FileID,
+			FileName: message.Document.FileName, MimeType: message.Document.MimeType,
+			Size: message.Document.FileSize}, true
+	case message.Voice != nil:
+		return task.Attachment{Kind: task.Voice, FileID: message.Voice.FileID, MimeType: message.Voice.MimeType,
+			Size: message.Voice.FileSize}, true
+	}
+	return task.Attachment{}, false
+}
+
+// attachToDraft adds an attachment to the task being created and returns the
+// notice to show above the prompt, which is also how a rejected one is told.
+func (t *Telegram) attachToDraft(ctx context.Context, chatID int64, attachment task.Attachment) (string, error) {
+	l := i18n.FromContext(ctx)
+	count, err := t.todoBot.AttachToDraft(ctx, chatID, attachment)
+	if errors.Is(err, todoBot.ErrTooManyAttachments) {
+		return html.EscapeString(l.T(i18n.TooManyAttachments)), nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return html.EscapeString(l.T(i18n.Attached, count)), nil
+}
+
+// attachmentsHandler sends a task's attachments again and shows the task
+// below them.
+func (t *Telegram) attachmentsHandler(ctx context.Context, chatID, taskID int64, number int) error {
+	attachments, err := t.todoBot.GetAttachments(ctx, chatID, taskID)
+	// openTaskHandler tells the user what went wrong.
+	if _, ok := userMessage(i18n.FromContext(ctx), err); ok {
+		return t.openTaskHandler(ctx, chatID, taskID, number)
+	}
+	if err != nil {
+		return err
+	}
+	for _, a := range attachments {
+		file := tu.FileFromID(a.FileID)
+		switch a.Kind {
+		case task.Photo:
+			_, err = t.bot.SendPhoto(tu.Photo(tu.ID(chatID), file))
+		case task.Voice:
+			_, err = t.bot.SendVoice(tu.Voice(tu.ID(chatID), file))
+		default:
+			_, err = t.bot.SendDocument(tu.Document(tu.ID(chatID), file))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	err = t.resetDashboard(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	return t.openTaskHandler(ctx, chatID, taskID, number)
+}
@@ -0,0 +1,107 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	tu "github.com/mymmrac/telego/telegoutil"
+	"html"
+	"strings"
+	todoBot "telegramBot/pkg/adapter/todobot"
+	"telegramBot/pkg/i18n"
+	"telegramBot/pkg/model/state/telegram"
+	"telegramBot/pkg/model/state/user"
+)
+
+// bulkMaxPreview caps the tasks listed in a bulk creation preview.
+const bulkMaxPreview = 20
+
+// bulkHandler starts a bulk creation, previewing text right away when the
+// command or a multi-line message came with it.
+func (t *Telegram) bulkHandler(ctx context.Context, chatID int64, text string) error {
+	err := t.deleteMessages(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	err = t.todoBot.SetUserState(ctx, chatID, user.WaitingForBulkTasks)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(text) == "" {
+		return t.bulkPrompt(ctx, chatID, "")
+	}
+	return t.bulkPreview(ctx, chatID, text)
+}
+
+func (t *Telegram) bulkPrompt(ctx context.Context, chatID int64, notice string) error {
+	l := i18n.FromContext(ctx)
+	text := html.EscapeString(l.T(i18n.SendBulkTasks))
+	if notice != "" {
+		text = html.EscapeString(notice) + "\n\n" + text
+	}
+	return t.render(ctx, chatID, text, cancelKeyboard(l.T(i18n.Cancel)))
+}
+
+// bulkTasksHandler previews the tasks the user sent. Like an import, the
+// user stays in the state until they confirm, so another message can replace
+// a wrong list.
+func (t *Telegram) bulkTasksHandler(ctx context.Context, chatID int64, in input) error {
+	if in.command == telegram.CancelLastActionState {
+		err := t.todoBot.CancelPending(ctx, chatID, todoBot.PendingBulk)
+		if err != nil {
+			return err
+		}
+		return t.cancelStateHandler(ctx, chatID)
+	}
+	if isBusyCommand(in.command) {
+		return t.finishLastActionHandler(ctx, chatID)
+	}
+	err := t.deleteMessages(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	return t.bulkPreview(ctx, chatID, in.text)
+}
+
+func (t *Telegram) bulkPreview(ctx context.Context, chatID int64, text string) error {
+	l := i18n.FromContext(ctx)
+	tasks, err := t.todoBot.PrepareBulk(ctx, chatID, text)
+	if message, ok := userMessage(l, err); ok {
+		return t.bulkPrompt(ctx, chatID, message)
+	}
+	if err != nil {
+		return err
+	}
+	p, err := t.todoBot.GetProfile(ctx, chatID)
+	if err != nil {
+		return err
+	}
+
+	var preview strings.Builder
+	preview.WriteString(html.EscapeString(l.T(i18n.BulkPreview, len(tasks))) + "\n")
+	for i, task := range tasks {
+		if i == bulkMaxPreview {
+			preview.WriteString("\n" + html.EscapeString(l.T(i18n.BulkMore, len(tasks)-i)))
+			break
+		}
+		fmt.Fprintf(&preview, "\n%d. %s", i+1, html.EscapeString(task.TaskName))
+		if mark, ok := priorityMarks[task.Priority]; ok {
+			preview.WriteString(" " + mark)
+		}
+		if !task.Due.IsZero() {
+			preview.WriteString(" · 📅 " + html.EscapeString(task.Due.Format(p.DateFormat)))
+			if task.Repeat != "" {
+				preview.WriteString(" 🔁 @" + task.Repeat)
+			}
+		}
+		if task.List != "" {
+			preview.WriteString(" · 📂 " + html.EscapeString(task.List))
+		}
+	}
+	inlineKeyboard := tu.InlineKeyboard(
+		tu.InlineKeyboardRow(
+			tu.InlineKeyboardButton(l.T(i18n.ButtonCreateTasks, len(tasks))).WithCallbackData(telegram.BulkButton),
+			tu.InlineKeyboardButton(l.T(i18n.Cancel)).WithCallbackData(telegram.CancelLastActionState),
+		),
+	)
+	return t.render(ctx, chatID, preview.String(), inlineKeyboard)
+}
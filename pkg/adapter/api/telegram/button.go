@@ -0,0 +1,182 @@
+package telegram
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	todoBot "telegramBot/pkg/adapter/todobot"
+	"telegramBot/pkg/export"
+	"telegramBot/pkg/i18n"
+	"telegramBot/pkg/model/profile"
+	"telegramBot/pkg/model/state/telegram"
+)
+
+// buttonHandler serves the inline buttons that work regardless of the user
+// state. It reports false for callback data it does not own.
+func (t *Telegram) buttonHandler(ctx context.Context, chatID int64, data string) (bool, error) {
+	switch {
+	case strings.HasPrefix(data, telegram.SearchPageButton):
+		page, err := strconv.Atoi(strings.TrimPrefix(data, telegram.SearchPageButton))
+		if err != nil {
+			return true, err
+		}
+		return true, t.searchPageHandler(ctx, chatID, page)
+	case strings.HasPrefix(data, telegram.ListPageButton):
+		page, err := strconv.Atoi(strings.TrimPrefix(data, telegram.ListPageButton))
+		if err != nil {
+			return true, err
+		}
+		return true, t.showListPage(ctx, chatID, page, "")
+	case strings.HasPrefix(data, telegram.OpenTaskButton):
+		taskID, page, err := parseTaskButton(strings.TrimPrefix(data, telegram.OpenTaskButton))
+		if err != nil {
+			return true, err
+		}
+		return true, t.openTaskHandler(ctx, chatID, taskID, page)
+	case strings.HasPrefix(data, telegram.AttachmentsButton):
+		taskID, page, err := parseTaskButton(strings.TrimPrefix(data, telegram.AttachmentsButton))
+		if err != nil {
+			return true, err
+		}
+		return true, t.attachmentsHandler(ctx, chatID, taskID, page)
+	case strings.HasPrefix(data, telegram.CommentsButton):
+		taskID, page, err := parseTaskButton(strings.TrimPrefix(data, telegram.CommentsButton))
+		if err != nil {
+			return true, err
+		}
+		return true, t.commentsHandler(ctx, chatID, taskID, page, "")
+	case strings.HasPrefix(data, telegram.AddCommentButton):
+		taskID, page, err := parseTaskButton(strings.TrimPrefix(data, telegram.AddCommentButton))
+		if err != nil {
+			return true, err
+		}
+		return true, t.addCommentHandler(ctx, chatID, taskID, page)
+	case strings.HasPrefix(data, telegram.CompleteTaskButton):
+		taskID, page, err := parseTaskButton(strings.TrimPrefix(data, telegram.CompleteTaskButton))
+		if err != nil {
+			return true, err
+		}
+		return true, t.completeTaskHandler(ctx, chatID, taskID, page)
+	case strings.HasPrefix(data, telegram.ReopenTaskButton):
+		taskID, page, err := parseTaskButton(strings.TrimPrefix(data, telegram.ReopenTaskButton))
+		if err != nil {
+			return true, err
+		}
+		return true, t.reopenTaskHandler(ctx, chatID, taskID, page)
+	case strings.HasPrefix(data, telegram.DeleteTaskButton):
+		taskID, page, err := parseTaskButton(strings.TrimPrefix(data, telegram.DeleteTaskButton))
+		if err != nil {
+			return true, err
+		}
+		return true, t.deleteTaskButtonHandler(ctx, chatID, taskID, page)
+	case strings.HasPrefix(data, telegram.UndoDeleteButton):
+		taskID, page, err := parseTaskButton(strings.TrimPrefix(data, telegram.UndoDeleteButton))
+		if err != nil {
+			return true, err
+		}
+		return true, t.undoDeleteHandler(ctx, chatID, taskID, page)
+	case strings.HasPrefix(data, telegram.RestoreTaskButton):
+		taskID, err := strconv.ParseInt(strings.TrimPrefix(data, telegram.RestoreTaskButton), 10, 64)
+		if err != nil {
+			return true, err
+		}
+		return true, t.restoreTaskHandler(ctx, chatID, taskID)
+	case strings.HasPrefix(data, telegram.PurgeTaskButton):
+		taskID, err := strconv.ParseInt(strings.TrimPrefix(data, telegram.PurgeTaskButton), 10, 64)
+		if err != nil {
+			return true, err
+		}
+		return true, t.purgeTaskHandler(ctx, chatID, taskID)
+	case data == telegram.EmptyTrashButton:
+		return true, t.emptyTrashHandler(ctx, chatID)
+	case data == telegram.ConfirmEmptyTrashButton:
+		return true, t.confirmEmptyTrashHandler(ctx, chatID)
+	case strings.HasPrefix(data, telegram.ArchivePageButton):
+		page, err := strconv.Atoi(strings.TrimPrefix(data, telegram.ArchivePageButton))
+		if err != nil {
+			return true, err
+		}
+		return true, t.archivePageHandler(ctx, chatID, page, "")
+	case strings.HasPrefix(data, telegram.UnarchiveButton):
+		taskID, page, err := parseTaskButton(strings.TrimPrefix(data, telegram.UnarchiveButton))
+		if err != nil {
+			return true, err
+		}
+		return true, t.unarchiveHandler(ctx, chatID, taskID, page)
+	case strings.HasPrefix(data, telegram.ArchiveAfterButton):
+		days := strings.TrimPrefix(data, telegram.ArchiveAfterButton)
+		return true, t.setSettingHandler(ctx, chatID, func(ctx context.Context, userID int64) error {
+			return t.todoBot.SetArchiveAfter(ctx, userID, days)
+		})
+	case strings.HasPrefix(data, telegram.PageSizeButton):
+		size, err := strconv.Atoi(strings.TrimPrefix(data, telegram.PageSizeButton))
+		if err != nil {
+			return true, err
+		}
+		return true, t.setPageSizeHandler(ctx, chatID, size)
+	case strings.HasPrefix(data, telegram.LanguageButton):
+		return true, t.setLanguageHandler(ctx, chatID, strings.TrimPrefix(data, telegram.LanguageButton))
+	case strings.HasPrefix(data, telegram.SettingButton):
+		return true, t.editSettingHandler(ctx, chatID, strings.TrimPrefix(data, telegram.SettingButton))
+	case strings.HasPrefix(data, telegram.DateFormatButton):
+		var layout string
+		i, err := strconv.Atoi(strings.TrimPrefix(data, telegram.DateFormatButton))
+		if err == nil && i >= 0 && i < len(profile.DateFormats) {
+			layout = profile.DateFormats[i]
+		}
+		return true, t.setSettingHandler(ctx, chatID, func(ctx context.Context, userID int64) error {
+			return t.todoBot.SetDateFormat(ctx, userID, layout)
+		})
+	case strings.HasPrefix(data, telegram.TimezoneButton):
+		return true, t.setSettingHandler(ctx, chatID, func(ctx context.Context, userID int64) error {
+			return t.todoBot.SetTimezone(ctx, userID, strings.TrimPrefix(data, telegram.TimezoneButton))
+		})
+	case data == telegram.NoListButton:
+		return true, t.setSettingHandler(ctx, chatID, func(ctx context.Context, userID int64) error {
+			return t.todoBot.SetDefaultList(ctx, userID, "")
+		})
+	case data == telegram.QuietOffButton:
+		return true, t.setSettingHandler(ctx, chatID, func(ctx context.Context, userID int64) error {
+			return t.todoBot.SetQuietHours(ctx, userID, "off")
+		})
+	case data == telegram.DigestOffButton:
+		return true, t.setSettingHandler(ctx, chatID, func(ctx context.Context, userID int64) error {
+			return t.todoBot.SetDigest(ctx, userID, "off")
+		})
+	case strings.HasPrefix(data, telegram.ExportButton):
+		format := export.Format(strings.TrimPrefix(data, telegram.ExportButton))
+		return true, t.exportFormatHandler(ctx, chatID, format)
+	case data == telegram.ImportButton:
+		return true, t.confirmPendingHandler(ctx, chatID, todoBot.PendingImport, i18n.TasksImported)
+	case data == telegram.ResetCalendarButton:
+		return true, t.resetCalendarHandler(ctx, chatID)
+	case data == telegram.NewAppPasswordButton:
+		return true, t.newAppPasswordHandler(ctx, chatID)
+	case data == telegram.RevokeAppPasswordsButton:
+		return true, t.revokeAppPasswordsHandler(ctx, chatID)
+	case data == telegram.ForwardButton:
+		return true, t.confirmForwardHandler(ctx, chatID)
+	case data == telegram.BulkButton:
+		return true, t.confirmPendingHandler(ctx, chatID, todoBot.PendingBulk, i18n.TasksCreated)
+	case strings.HasPrefix(data, telegram.DoneTaskButton):
+		taskID, err := strconv.ParseInt(strings.TrimPrefix(data, telegram.DoneTaskButton), 10, 64)
+		if err != nil {
+			return true, err
+		}
+		return true, t.doneTaskButtonHandler(ctx, chatID, taskID)
+	}
+	return false, nil
+}
+
+func parseTaskButton(data string) (taskID int64, page int, err error) {
+	id, number, _ := strings.Cut(data, ":")
+	taskID, err = strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	page, err = strconv.Atoi(number)
+	if err != nil {
+		return 0, 0, err
+	}
+	return taskID, page, nil
+}
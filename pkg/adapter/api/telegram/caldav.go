@@ -0,0 +1,58 @@
+package telegram
+
+import (
+	"context"
+	tu "github.com/mymmrac/telego/telegoutil"
+	"html"
+	"strings"
+	"telegramBot/pkg/adapter/api/caldav"
+	"telegramBot/pkg/i18n"
+	"telegramBot/pkg/model/state/telegram"
+)
+
+// caldavHandler shows how to connect a CalDAV app. The notice is not escaped,
+// as it may carry a new app password in markup.
+func (t *Telegram) caldavHandler(ctx context.Context, chatID int64, notice string) error {
+	err := t.deleteMessages(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	l := i18n.FromContext(ctx)
+	count, err := t.todoBot.CountAppPasswords(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	server := caldav.Path
+	if t.cfg.PublicURL != "" {
+		server = strings.TrimRight(t.cfg.PublicURL, "/") + caldav.Path
+	}
+	text := l.T(i18n.CalDAVInfo, html.EscapeString(server), chatID, count)
+	if notice != "" {
+		text = notice + "\n\n" + text
+	}
+	row := tu.InlineKeyboardRow(
+		tu.InlineKeyboardButton(l.T(i18n.ButtonNewAppPassword)).WithCallbackData(telegram.NewAppPasswordButton))
+	if count > 0 {
+		row = append(row, tu.InlineKeyboardButton(l.T(i18n.ButtonRevokeAppPasswords)).
+			WithCallbackData(telegram.RevokeAppPasswordsButton))
+	}
+	return t.render(ctx, chatID, text, withMenu(l, tu.InlineKeyboard(row)))
+}
+
+func (t *Telegram) newAppPasswordHandler(ctx context.Context, chatID int64) error {
+	password, err := t.todoBot.NewAppPassword(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	l := i18n.FromContext(ctx)
+	return t.caldavHandler(ctx, chatID, l.T(i18n.AppPasswordCreated, html.EscapeString(password)))
+}
+
+func (t *Telegram) revokeAppPasswordsHandler(ctx context.Context, chatID int64) error {
+	err := t.todoBot.RevokeAppPasswords(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	l := i18n.FromContext(ctx)
+	return t.caldavHandler(ctx, chatID, html.EscapeString(l.T(i18n.AppPasswordsRevoked)))
+}
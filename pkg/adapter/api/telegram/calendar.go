@@ -0,0 +1,63 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	tu "github.com/mymmrac/telego/telegoutil"
+	"html"
+	"strings"
+	"telegramBot/pkg/adapter/api/server"
+	"telegramBot/pkg/i18n"
+	"telegramBot/pkg/model/state/telegram"
+)
+
+// calendarHandler sends the user's calendar as a file and, when the bot is
+// reachable from the outside, the link calendar apps can subscribe to.
+func (t *Telegram) calendarHandler(ctx context.Context, chatID int64, notice string) error {
+	err := t.deleteMessages(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	l := i18n.FromContext(ctx)
+	var file bytes.Buffer
+	err = t.todoBot.WriteCalendar(ctx, chatID, &file)
+	if err != nil {
+		return err
+	}
+	caption := l.T(i18n.CalendarFile)
+	if t.cfg.PublicURL != "" {
+		token, err := t.todoBot.CalendarToken(ctx, chatID)
+		if err != nil {
+			return err
+		}
+		caption = l.T(i18n.CalendarLink, strings.TrimRight(t.cfg.PublicURL, "/")+server.CalendarPath+token+".ics")
+	}
+	document := tu.Document(tu.ID(chatID), tu.File(tu.NameReader(&file, "tasks.ics")))
+	_, err = t.bot.SendDocument(document.WithCaption(caption))
+	if err != nil {
+		return err
+	}
+	err = t.resetDashboard(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	if t.cfg.PublicURL == "" {
+		return t.menu(ctx, chatID, notice)
+	}
+	text := l.T(i18n.Menu)
+	if notice != "" {
+		text = html.EscapeString(notice) + "\n\n" + text
+	}
+	inlineKeyboard := tu.InlineKeyboard(tu.InlineKeyboardRow(
+		tu.InlineKeyboardButton(l.T(i18n.ButtonResetCalendar)).WithCallbackData(telegram.ResetCalendarButton)))
+	return t.render(ctx, chatID, text, withMenu(l, inlineKeyboard))
+}
+
+// resetCalendarHandler revokes the link the user shared and sends a new one.
+func (t *Telegram) resetCalendarHandler(ctx context.Context, chatID int64) error {
+	_, err := t.todoBot.ResetCalendarToken(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	return t.calendarHandler(ctx, chatID, i18n.FromContext(ctx).T(i18n.CalendarReset))
+}
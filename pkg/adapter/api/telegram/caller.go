@@ -0,0 +1,182 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/mymmrac/telego/telegoapi"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"net/http"
+	neturl "net/url"
+	"path"
+	"strings"
+	"sync/atomic"
+	"telegramBot/pkg/adapter/metrics"
+	"telegramBot/pkg/adapter/tracing"
+	"time"
+)
+
+const (
+	callAttempts = 3
+	callBackoff  = time.Second
+	// maxRetryWait caps how long one call backs off between its attempts in
+	// all. Updates are handled one at a time, so a call that waits holds up
+	// every chat; it stays well below the default long-poll timeout.
+	maxRetryWait = 4 * time.Second
+	// maxFloodWait caps the retry_after of flood control a call waits out.
+	// Telegram asks for tens of seconds as often as not, and until then any
+	// other call to the chat would fail as well. Longer waits fail the call,
+	// and the user can press Retry later.
+	maxFloodWait   = 30 * time.Second
+	requestTimeout = time.Minute
+)
+
+type statusError struct {
+	code int
+}
+
+func (e statusError) Error() string {
+	return fmt.Sprintf("internal server error: %d", e.code)
+}
+
+// retryCaller is the Bot API transport. Flood control replies (429) are
+// retried after the retry_after Telegram asks for, and server errors Telegram
+// reports after a growing backoff: it did not carry the request out. A
+// network failure leaves that unknown, so only the methods that are safe to
+// repeat are retried then; a second sendMessage could send the message twice.
+type retryCaller struct {
+	client *http.Client
+	log    *zap.Logger
+	// polled is the unix time of the last successful getUpdates.
+	polled atomic.Int64
+	// update is the context of the update being handled. Updates are handled
+	// one at a time, so every call except getUpdates, which runs on telego's
+	// polling goroutine, belongs to it.
+	update atomic.Pointer[context.Context]
+}
+
+func newRetryCaller(log *zap.Logger) *retryCaller {
+	return &retryCaller{client: &http.Client{Timeout: requestTimeout}, log: log}
+}
+
+func (c *retryCaller) Call(url string, data *telegoapi.RequestData) (*telegoapi.Response, error) {
+	method := path.Base(url)
+	if method == "getUpdates" {
+		response, err := c.retry(context.Background(), method, url, data)
+		if err == nil && response.Ok {
+			c.polled.Store(time.Now().Unix())
+		}
+		return response, err
+	}
+
+	ctx := context.Background()
+	if update := c.update.Load(); update != nil {
+		ctx = *update
+	}
+	_, span := tracing.Start(ctx, "telegram."+method, trace.WithSpanKind(trace.SpanKindClient))
+	response, err := c.retry(ctx, method, url, data)
+	if err == nil && !response.Ok && response.Error != nil {
+		span.SetAttributes(attribute.Int("telegram.error_code", response.ErrorCode))
+		tracing.End(span, response.Error)
+		return response, err
+	}
+	tracing.End(span, err)
+	return response, err
+}
+
+// retry stops waiting when ctx is done, so a shutdown does not wait for it.
+func (c *retryCaller) retry(ctx context.Context, method, url string,
+	data *telegoapi.RequestData) (*telegoapi.Response, error) {
+	var waited time.Duration
+	for attempt := 1; ; attempt++ {
+		response, err := c.call(url, data)
+		wait, retry := retryDelay(attempt, method, response, err)
+		limit := maxRetryWait
+		if response != nil && response.Error != nil && response.ErrorCode == http.StatusTooManyRequests {
+			limit = maxFloodWait
+		}
+		if retry && waited+wait <= limit {
+			metrics.TelegramRetries.WithLabelValues(method).Inc()
+			c.log.Warn("retryCaller.retry()", zap.String("method", method), zap.Int("attempt", attempt),
+				zap.Duration("wait", wait), zap.Error(err))
+			waited += wait
+			select {
+			case <-time.After(wait):
+				continue
+			case <-ctx.Done():
+			}
+		}
+		if err != nil || !response.Ok {
+			metrics.TelegramErrors.WithLabelValues(method).Inc()
+		}
+		return response, err
+	}
+}
+
+func (c *retryCaller) call(url string, data *telegoapi.RequestData) (*telegoapi.Response, error) {
+	request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data.Buffer.Bytes()))
+	if err != nil {
+		return nil, fmt.Errorf("http create request: %w", err)
+	}
+	request.Header.Set(telegoapi.ContentTypeHeader, data.ContentType)
+
+	response, err := c.client.Do(request)
+	if err != nil {
+		// url.Error quotes the request URL, which holds the token.
+		var urlErr *neturl.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("http do request %s: %w", path.Base(url), err)
+	}
+	defer response.Body.Close()
+
+	apiResponse := &telegoapi.Response{}
+	err = json.NewDecoder(response.Body).Decode(apiResponse)
+	// A server error without a Bot API reply comes from a proxy in front of
+	// Telegram, which may or may not have passed the request on.
+	if response.StatusCode >= http.StatusInternalServerError && (err != nil || apiResponse.Error == nil) {
+		return nil, statusError{code: response.StatusCode}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decode json: %w", err)
+	}
+	return apiResponse, nil
+}
+
+// idempotent reports whether repeating method leaves things as one call
+// would.
+func idempotent(method string) bool {
+	for _, prefix := range []string{"get", "edit", "delete"} {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func retryDelay(attempt int, method string, response *telegoapi.Response, err error) (time.Duration, bool) {
+	if attempt >= callAttempts {
+		return 0, false
+	}
+	if err != nil {
+		return callBackoff * time.Duration(attempt), idempotent(method)
+	}
+	if response.Ok || response.Error == nil {
+		return 0, false
+	}
+	switch {
+	case response.ErrorCode == http.StatusTooManyRequests:
+		if response.Parameters != nil && response.Parameters.RetryAfter > 0 {
+			return time.Duration(response.Parameters.RetryAfter) * time.Second, true
+		}
+		return callBackoff, true
+	case response.ErrorCode >= http.StatusInternalServerError:
+		return callBackoff * time.Duration(attempt), true
+	}
+	return 0, false
+}
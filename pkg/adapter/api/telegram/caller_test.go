@@ -0,0 +1,82 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"github.com/mymmrac/telego/telegoapi"
+	tu "github.com/mymmrac/telego/telegoutil"
+	"testing"
+	"time"
+)
+
+func TestRetryDelay(t *testing.T) {
+	failed := func(code, retryAfter int) *telegoapi.Response {
+		response := &telegoapi.Response{Error: &telegoapi.Error{ErrorCode: code}}
+		if retryAfter > 0 {
+			response.Parameters = &telegoapi.ResponseParameters{RetryAfter: retryAfter}
+		}
+		return response
+	}
+	network := errors.New("connection reset")
+	tests := []struct {
+		name     string
+		attempt  int
+		method   string
+		response *telegoapi.Response
+		err      error
+		wait     time.Duration
+		retry    bool
+	}{
+		{"ok", 1, "sendMessage", &telegoapi.Response{Ok: true}, nil, 0, false},
+		{"bad request", 1, "sendMessage", failed(400, 0), nil, 0, false},
+		{"flood control", 1, "sendMessage", failed(429, 3), nil, 3 * time.Second, true},
+		{"flood control without retry_after", 1, "sendMessage", failed(429, 0), nil, callBackoff, true},
+		{"server error", 2, "sendMessage", failed(502, 0), nil, 2 * callBackoff, true},
+		{"network error on send", 1, "sendMessage", nil, network, callBackoff, false},
+		{"network error on document", 1, "sendDocument", nil, network, callBackoff, false},
+		{"proxy error on send", 1, "sendMessage", nil, statusError{code: 502}, callBackoff, false},
+		{"network error on edit", 1, "editMessageText", nil, network, callBackoff, true},
+		{"network error on delete", 2, "deleteMessage", nil, network, 2 * callBackoff, true},
+		{"network error on get", 1, "getUpdates", nil, network, callBackoff, true},
+		{"last attempt", callAttempts, "editMessageText", nil, network, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wait, retry := retryDelay(tt.attempt, tt.method, tt.response, tt.err)
+			if retry != tt.retry || (retry && wait != tt.wait) {
+				t.Errorf("retryDelay() = %v, %t, want %v, %t", wait, retry, tt.wait, tt.retry)
+			}
+		})
+	}
+}
+
+func TestRetryCallerFloodWait(t *testing.T) {
+	flood := func(retryAfter int) string {
+		return fmt.Sprintf(`{"ok":false,"error_code":429,"description":"Too Many Requests: retry after %d",`+
+			`"parameters":{"retry_after":%d}}`, retryAfter, retryAfter)
+	}
+	tests := []struct {
+		name    string
+		replies []string
+		calls   int
+		ok      bool
+	}{
+		{"waited out", []string{flood(1)}, 2, true},
+		{"longer than the backoff cap", []string{flood(int(maxRetryWait/time.Second) + 1)}, 2, true},
+		{"longer than the flood cap", []string{flood(int(maxFloodWait/time.Second) + 1)}, 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := startBotAPI(t)
+			tg := newTestTelegram(t, api)
+			api.reply("sendMessage", tt.replies...)
+			_, err := tg.bot.SendMessage(tu.Message(tu.ID(testChat), "Menu"))
+			if (err == nil) != tt.ok {
+				t.Errorf("SendMessage() error = %v, want ok %t", err, tt.ok)
+			}
+			if got := len(api.methods()); got != tt.calls {
+				t.Errorf("sendMessage called %d times, want %d", got, tt.calls)
+			}
+		})
+	}
+}
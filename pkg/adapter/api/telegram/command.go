@@ -0,0 +1,97 @@
+package telegram
+
+import (
+	"github.com/mymmrac/telego"
+	"strings"
+	"telegramBot/pkg/i18n"
+	"telegramBot/pkg/model/state/telegram"
+	"unicode"
+)
+
+// input is an incoming message or button press. For commands, command holds
+// the canonical lowercase name and args whatever followed it; foreign marks
+// commands addressed to another bot in a group. message is the message the
+// input came with, nil for buttons.
+type input struct {
+	text    string
+	command string
+	args    string
+	foreign bool
+	message *telego.Message
+}
+
+func (t *Telegram) parseInput(text string) input {
+	in := input{text: text}
+	if !strings.HasPrefix(text, "/") {
+		return in
+	}
+	// A newline also ends the command, so /bulk can take its list of tasks
+	// on the next lines.
+	command, args := text, ""
+	if i := strings.IndexFunc(text, unicode.IsSpace); i >= 0 {
+		command, args = text[:i], text[i:]
+	}
+	command, mention, found := strings.Cut(command, "@")
+	if found && !strings.EqualFold(mention, t.username) {
+		in.foreign = true
+		return in
+	}
+	command = strings.ToLower(command)
+	if alias, ok := telegram.Aliases[command]; ok {
+		command = alias
+	}
+	in.command = command
+	in.args = strings.TrimSpace(args)
+	return in
+}
+
+// splitTask splits one-shot task arguments of the form "name | description".
+func splitTask(args string) (name, description string) {
+	name, description, _ = strings.Cut(args, "|")
+	return strings.TrimSpace(name), strings.TrimSpace(description)
+}
+
+var commandMenu = []string{
+	telegram.NewTaskState,
+	telegram.AddTaskState,
+	telegram.ListOfTasksState,
+	telegram.SearchState,
+	telegram.DeleteTaskState,
+	telegram.PageSizeState,
+	telegram.LanguageState,
+	telegram.SettingsState,
+	telegram.ExportState,
+	telegram.ImportState,
+	telegram.BulkState,
+	telegram.CalendarState,
+	telegram.CalDAVState,
+	telegram.TrashState,
+	telegram.ArchiveState,
+	telegram.CancelLastActionState,
+	telegram.StartState,
+}
+
+// registerCommands publishes the command menu in every supported language,
+// with the default language also used for clients in any other one.
+func (t *Telegram) registerCommands() error {
+	for _, language := range i18n.Languages {
+		l := i18n.For(language.Code)
+		commands := make([]telego.BotCommand, 0, len(commandMenu))
+		for _, command := range commandMenu {
+			name := strings.TrimPrefix(command, "/")
+			commands = append(commands, telego.BotCommand{
+				Command:     name,
+				Description: l.T(i18n.CommandDescription(name)),
+			})
+		}
+		params := &telego.SetMyCommandsParams{Commands: commands, LanguageCode: language.Code}
+		if language.Code == i18n.DefaultLanguage {
+			params.LanguageCode = ""
+		}
+		err := t.bot.SetMyCommands(params)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/mymmrac/telego"
+	tu "github.com/mymmrac/telego/telegoutil"
+	"html"
+	"strings"
+	todoBot "telegramBot/pkg/adapter/todobot"
+	"telegramBot/pkg/i18n"
+	"telegramBot/pkg/model/profile"
+	"telegramBot/pkg/model/state/telegram"
+	"telegramBot/pkg/model/state/user"
+	"telegramBot/pkg/model/task"
+	"time"
+)
+
+// commentsShown and eventsShown cap how much of a long history the comments
+// screen shows, newest kept, so it stays within a message.
+const (
+	commentsShown = 5
+	eventsShown   = 10
+)
+
+// commentsHandler shows a task's history and comments.
+func (t *Telegram) commentsHandler(ctx context.Context, chatID, taskID int64, number int, notice string) error {
+	l := i18n.FromContext(ctx)
+	current, err := t.todoBot.GetTask(ctx, chatID, taskID)
+	if message, ok := userMessage(l, err); ok {
+		return t.showListPage(ctx, chatID, number, message)
+	}
+	if err != nil {
+		return err
+	}
+	events, err := t.todoBot.GetTaskHistory(ctx, chatID, taskID)
+	if err != nil {
+		return err
+	}
+	comments, err := t.todoBot.GetComments(ctx, chatID, taskID)
+	if err != nil {
+		return err
+	}
+	p, err := t.todoBot.GetProfile(ctx, chatID)
+	if err != nil {
+		return err
+	}
+
+	var text strings.Builder
+	if notice != "" {
+		fmt.Fprintf(&text, "%s\n\n", html.EscapeString(notice))
+	}
+	text.WriteString("💬 <b>" + html.EscapeString(current.TaskName) + "</b>\n\n")
+	text.WriteString("<b>" + html.EscapeString(l.T(i18n.HistoryTitle)) + "</b>")
+	if len(events) > eventsShown {
+		events = events[len(events)-eventsShown:]
+	}
+	for _, e := range events {
+		fmt.Fprintf(&text, "\n%s · %s", formatTime(p, e.CreatedAt), html.EscapeString(eventText(l, e)))
+	}
+	text.WriteString("\n\n<b>" + html.EscapeString(l.T(i18n.CommentsTitle, len(comments))) + "</b>")
+	if len(comments) == 0 {
+		text.WriteString("\n" + html.EscapeString(l.T(i18n.NoComments)))
+	}
+	if len(comments) > commentsShown {
+		comments = comments[len(comments)-commentsShown:]
+	}
+	for _, c := range comments {
+		fmt.Fprintf(&text, "\n\n<i>%s</i>\n%s", formatTime(p, c.CreatedAt), html.EscapeString(c.Text))
+	}
+
+	target := fmt.Sprintf("%d:%d", taskID, number)
+	inlineKeyboard := tu.InlineKeyboard(tu.InlineKeyboardRow(
+		tu.InlineKeyboardButton(l.T(i18n.ButtonAddComment)).WithCallbackData(telegram.AddCommentButton+target),
+		tu.InlineKeyboardButton(l.T(i18n.ButtonBack)).WithCallbackData(telegram.OpenTaskButton+target),
+	))
+	return t.render(ctx, chatID, text.String(), inlineKeyboard)
+}
+
+func formatTime(p profile.Profile, at time.Time) string {
+	return html.EscapeString(p.FormatDate(at) + p.Now(at).Format(" 15:04"))
+}
+
+func eventText(l i18n.Localizer, e task.Event) string {
+	switch e.Kind {
+	case task.EventRenamed:
+		return l.T(i18n.EventRenamed, e.Detail)
+	case task.EventCompleted:
+		return l.T(i18n.EventCompleted)
+	case task.EventReopened:
+		return l.T(i18n.EventReopened)
+	case task.EventAssigned:
+		if e.Detail == "" {
+			return l.T(i18n.EventUnassigned)
+		}
+		return l.T(i18n.EventAssigned, e.Detail)
+	case task.EventDeleted:
+		return l.T(i18n.EventDeleted)
+	case task.EventRestored:
+		return l.T(i18n.EventRestored)
+	case task.EventArchived:
+		return l.T(i18n.EventArchived)
+	case task.EventUnarchived:
+		return l.T(i18n.EventUnarchived)
+	}
+	return l.T(i18n.EventCreated)
+}
+
+func (t *Telegram) addCommentHandler(ctx context.Context, chatID, taskID int64, number int) error {
+	err := t.cache.SetCommentTarget(ctx, chatID, fmt.Sprintf("%d:%d", taskID, number))
+	if err != nil {
+		return err
+	}
+	err = t.todoBot.SetUserState(ctx, chatID, user.WaitingForComment)
+	if err != nil {
+		return err
+	}
+	return t.commentPrompt(ctx, chatID, "")
+}
+
+func (t *Telegram) commentPrompt(ctx context.Context, chatID int64, notice string) error {
+	l := i18n.FromContext(ctx)
+	text := html.EscapeString(l.T(i18n.SendComment))
+	if notice != "" {
+		text = html.EscapeString(notice) + "\n\n" + text
+	}
+	return t.render(ctx, chatID, text, cancelKeyboard(l.T(i18n.Cancel)))
+}
+
+func (t *Telegram) commentInputHandler(ctx context.Context, chatID int64, in input) error {
+	if in.command == telegram.CancelLastActionState {
+		return t.cancelStateHandler(ctx, chatID)
+	}
+	if isBusyCommand(in.command) {
+		return t.finishLastActionHandler(ctx, chatID)
+	}
+	err := t.deleteMessages(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	l := i18n.FromContext(ctx)
+	target, err := t.cache.GetCommentTarget(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	if target == "" {
+		err = t.todoBot.SetUserState(ctx, chatID, user.Default)
+		if err != nil {
+			return err
+		}
+		return t.menu(ctx, chatID, l.T(i18n.CommentExpired))
+	}
+	taskID, number, err := parseTaskButton(target)
+	if err != nil {
+		return err
+	}
+	err = t.todoBot.AddComment(ctx, chatID, taskID, in.text)
+	// The user can fix the text; the task going away ends the comment.
+	if errors.Is(err, todoBot.ErrEmptyComment) || errors.Is(err, todoBot.ErrCommentTooLong) {
+		message, _ := userMessage(l, err)
+		return t.commentPrompt(ctx, chatID, message)
+	}
+	message, gone := userMessage(l, err)
+	if err != nil && !gone {
+		return err
+	}
+	err = t.todoBot.SetUserState(ctx, chatID, user.Default)
+	if err != nil {
+		return err
+	}
+	if gone {
+		return t.showListPage(ctx, chatID, number, message)
+	}
+	return t.commentsHandler(ctx, chatID, taskID, number, l.T(i18n.CommentAdded))
+}
+
+func commentsButton(l i18n.Localizer, count int, taskID int64, number int) telego.InlineKeyboardButton {
+	return tu.InlineKeyboardButton(l.T(i18n.ButtonComments, count)).
+		WithCallbackData(fmt.Sprintf("%s%d:%d", telegram.CommentsButton, taskID, number))
+}
@@ -0,0 +1,163 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"github.com/mymmrac/telego"
+	tu "github.com/mymmrac/telego/telegoutil"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"html"
+	"strings"
+	todoBot "telegramBot/pkg/adapter/todobot"
+	"telegramBot/pkg/adapter/tracing"
+	"telegramBot/pkg/i18n"
+	"telegramBot/pkg/model/state/telegram"
+	"telegramBot/pkg/model/task"
+	"time"
+)
+
+// digestMaxTasks caps the tasks listed, and given a Done button, in a digest.
+const digestMaxTasks = 10
+
+type digestRequest struct {
+	digest todoBot.Digest
+	done   chan error
+}
+
+// SendDigest hands a digest to Run, which sends it between two updates so it
+// never races with a handler over the user's dashboard.
+func (t *Telegram) SendDigest(ctx context.Context, digest todoBot.Digest) error {
+	request := digestRequest{digest: digest, done: make(chan error, 1)}
+	select {
+	case t.digests <- request:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case err := <-request.done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (t *Telegram) handleDigest(ctx context.Context, digest todoBot.Digest) (err error) {
+	chatID := digest.Profile.UserID
+	ctx = withCorrelationID(ctx, newCorrelationID())
+	ctx, span := tracing.Start(ctx, "digest", trace.WithNewRoot(),
+		trace.WithAttributes(attribute.Int64("telegram.chat_id", chatID),
+			attribute.String("correlation_id", correlationID(ctx))))
+	defer func() { tracing.End(span, err) }()
+	t.caller.update.Store(&ctx)
+	defer t.caller.update.Store(nil)
+
+	log := t.log.With(zap.Int64("chatID", chatID), zap.String("action", "digest"),
+		zap.String("correlationID", correlationID(ctx)))
+	if traceID := tracing.TraceID(ctx); traceID != "" {
+		log = log.With(zap.String("traceID", traceID))
+	}
+	ctx = withLogger(ctx, log)
+
+	language, err := t.todoBot.GetUserLanguage(ctx, chatID, "")
+	if err != nil {
+		return fmt.Errorf("t.todoBot.GetUserLanguage(): %w", err)
+	}
+	ctx = i18n.WithLanguage(ctx, language)
+	// The digest arrives as a new message, so the user gets notified, and
+	// becomes the dashboard the Done buttons update.
+	err = t.resetDashboard(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	text, inlineKeyboard := digestScreen(i18n.FromContext(ctx), digest, "")
+	return t.render(ctx, chatID, text, inlineKeyboard)
+}
+
+func (t *Telegram) doneTaskButtonHandler(ctx context.Context, chatID, taskID int64) error {
+	l := i18n.FromContext(ctx)
+	notice := l.T(i18n.TaskDone)
+	err := t.todoBot.CompleteTask(ctx, chatID, taskID)
+	if message, ok := userMessage(l, err); ok {
+		notice = message
+	} else if err != nil {
+		return err
+	}
+	digest, err := t.todoBot.GetDigest(ctx, chatID, time.Now())
+	if err != nil {
+		return err
+	}
+	text, inlineKeyboard := digestScreen(l, digest, notice)
+	return t.render(ctx, chatID, text, inlineKeyboard)
+}
+
+func digestScreen(l i18n.Localizer, digest todoBot.Digest, notice string) (string, *telego.InlineKeyboardMarkup) {
+	var text strings.Builder
+	if notice != "" {
+		fmt.Fprintf(&text, "%s\n\n", html.EscapeString(notice))
+	}
+	text.WriteString(html.EscapeString(l.T(i18n.DigestHeader, digest.Day.Format(digest.Profile.DateFormat))))
+	if digest.Empty() {
+		text.WriteString("\n\n" + l.T(i18n.NoTasks))
+		return text.String(), withMenu(l, nil)
+	}
+
+	var rows [][]telego.InlineKeyboardButton
+	var row []telego.InlineKeyboardButton
+	// more counts the tasks past digestMaxTasks, overdue and due today alike.
+	number, more := 0, 0
+	section := func(header string, tasks []task.Task, withDue bool) {
+		if number == digestMaxTasks {
+			more += len(tasks)
+			return
+		}
+		text.WriteString("\n\n" + header)
+		for i, t := range tasks {
+			if number == digestMaxTasks {
+				more += len(tasks) - i
+				return
+			}
+			number++
+			fmt.Fprintf(&text, "\n%d. %s", number, html.EscapeString(t.TaskName))
+			if withDue {
+				text.WriteString(" · " + html.EscapeString(t.Due.Format(digest.Profile.DateFormat)))
+			}
+			row = append(row, tu.InlineKeyboardButton(fmt.Sprintf("✅ %d", number)).
+				WithCallbackData(fmt.Sprintf("%s%d", telegram.DoneTaskButton, t.ID)))
+			if len(row) == listButtonsPerRow {
+				rows = append(rows, row)
+				row = nil
+			}
+		}
+	}
+	if len(digest.Overdue) > 0 {
+		section(l.T(i18n.DigestOverdue, len(digest.Overdue)), digest.Overdue, true)
+	}
+	if len(digest.DueToday) > 0 {
+		section(l.T(i18n.DigestDueToday, len(digest.DueToday)), digest.DueToday, false)
+	}
+	if more > 0 {
+		text.WriteString("\n" + l.T(i18n.DigestMore, more))
+	}
+	if number == 0 {
+		text.WriteString("\n\n" + l.T(i18n.DigestNothingDue))
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+
+	lists := make([]string, 0, len(digest.Lists))
+	for _, list := range digest.Lists {
+		name := list.List
+		if name == "" {
+			name = l.T(i18n.DigestNoList)
+		}
+		lists = append(lists, fmt.Sprintf("%s %d", name, list.Open))
+	}
+	text.WriteString("\n\n" + html.EscapeString(l.T(i18n.DigestLists, strings.Join(lists, " · "))))
+	if len(rows) == 0 {
+		return text.String(), withMenu(l, nil)
+	}
+	return text.String(), withMenu(l, tu.InlineKeyboard(rows...))
+}
@@ -0,0 +1,85 @@
+package telegram
+
+import (
+	"fmt"
+	"strings"
+	todoBot "telegramBot/pkg/adapter/todobot"
+	"telegramBot/pkg/i18n"
+	"telegramBot/pkg/model/profile"
+	"telegramBot/pkg/model/state/telegram"
+	"telegramBot/pkg/model/task"
+	"testing"
+	"time"
+)
+
+func digestTasks(prefix string, n int) []task.Task {
+	tasks := make([]task.Task, n)
+	for i := range tasks {
+		tasks[i] = task.Task{ID: i + 1, TaskName: fmt.Sprintf("%s %d", prefix, i+1),
+			Due: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}
+	}
+	return tasks
+}
+
+func TestDigestScreen(t *testing.T) {
+	l := i18n.For("en")
+	tests := []struct {
+		name     string
+		overdue  int
+		dueToday int
+		headers  []string
+		more     int
+	}{
+		{"nothing due", 0, 0, nil, 0},
+		{"both sections", 2, 3, []string{l.T(i18n.DigestOverdue, 2), l.T(i18n.DigestDueToday, 3)}, 0},
+		{"exactly the cap", 4, digestMaxTasks - 4, []string{l.T(i18n.DigestOverdue, 4),
+			l.T(i18n.DigestDueToday, digestMaxTasks-4)}, 0},
+		{"cap reached in due today", 4, digestMaxTasks, []string{l.T(i18n.DigestOverdue, 4),
+			l.T(i18n.DigestDueToday, digestMaxTasks)}, 4},
+		{"cap reached in overdue", digestMaxTasks + 2, 3, []string{l.T(i18n.DigestOverdue, digestMaxTasks+2)}, 5},
+		{"cap reached with overdue", digestMaxTasks, 3, []string{l.T(i18n.DigestOverdue, digestMaxTasks)}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			digest := todoBot.Digest{
+				Profile:  profile.Profile{DateFormat: profile.DefaultDateFormat},
+				Day:      time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+				Overdue:  digestTasks("Overdue", tt.overdue),
+				DueToday: digestTasks("Today", tt.dueToday),
+				Lists:    []todoBot.ListCount{{Open: tt.overdue + tt.dueToday + 1}},
+			}
+			text, keyboard := digestScreen(l, digest, "")
+
+			for _, header := range []string{l.T(i18n.DigestOverdue, tt.overdue), l.T(i18n.DigestDueToday, tt.dueToday)} {
+				want := false
+				for _, h := range tt.headers {
+					want = want || h == header
+				}
+				if strings.Contains(text, header) != want {
+					t.Errorf("header %q shown = %t, want %t:\n%s", header, !want, want, text)
+				}
+			}
+			moreLines := strings.Count(text, "…and ")
+			if tt.more == 0 && moreLines != 0 || tt.more > 0 && (moreLines != 1 ||
+				!strings.Contains(text, l.T(i18n.DigestMore, tt.more))) {
+				t.Errorf("digest does not end the list with %d more:\n%s", tt.more, text)
+			}
+			if got := strings.Contains(text, l.T(i18n.DigestNothingDue)); got != (tt.overdue+tt.dueToday == 0) {
+				t.Errorf("nothing due shown = %t:\n%s", got, text)
+			}
+
+			var buttons int
+			for _, row := range keyboard.InlineKeyboard {
+				for _, button := range row {
+					if strings.HasPrefix(button.CallbackData, telegram.DoneTaskButton) {
+						buttons++
+					}
+				}
+			}
+			listed := tt.overdue + tt.dueToday - tt.more
+			if buttons != listed || strings.Count(text, "\n"+fmt.Sprint(listed)+". ") != 1 && listed > 0 {
+				t.Errorf("%d Done buttons, want one per listed task, %d:\n%s", buttons, listed, text)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package telegram
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/mymmrac/telego"
+	tu "github.com/mymmrac/telego/telegoutil"
+	"go.uber.org/zap"
+	todoBot "telegramBot/pkg/adapter/todobot"
+	"telegramBot/pkg/i18n"
+	"telegramBot/pkg/model/state/telegram"
+	"telegramBot/pkg/model/state/user"
+)
+
+var userErrors = []struct {
+	err error
+	key string
+}{
+	{todoBot.ErrTaskNotFound, i18n.TaskNotFound},
+	{todoBot.ErrAmbiguousName, i18n.AmbiguousTaskName},
+	{todoBot.ErrNotOwner, i18n.NotYourTask},
+	{todoBot.ErrDraftNotFound, i18n.DraftExpired},
+	{todoBot.ErrEmptyTaskName, i18n.EmptyTaskName},
+	{todoBot.ErrInvalidPageSize, i18n.InvalidPageSize},
+	{todoBot.ErrUnsupportedLanguage, i18n.UnsupportedLanguage},
+	{todoBot.ErrInvalidTimezone, i18n.InvalidTimezone},
+	{todoBot.ErrInvalidDateFormat, i18n.InvalidDateFormat},
+	{todoBot.ErrInvalidListName, i18n.InvalidListName},
+	{todoBot.ErrInvalidQuietHours, i18n.InvalidQuietHours},
+	{todoBot.ErrInvalidDigestTime, i18n.InvalidDigestTime},
+	{todoBot.ErrUnsupportedFormat, i18n.UnsupportedFormat},
+	{todoBot.ErrUnreadableFile, i18n.UnreadableFile},
+	{todoBot.ErrImportTooLarge, i18n.ImportTooLarge},
+	{todoBot.ErrPendingNotFound, i18n.PendingExpired},
+	{todoBot.ErrTooManyAttachments, i18n.TooManyAttachments},
+	{todoBot.ErrEmptyComment, i18n.EmptyComment},
+	{todoBot.ErrCommentTooLong, i18n.CommentTooLong},
+	{todoBot.ErrUndoExpired, i18n.UndoExpired},
+	{todoBot.ErrInvalidArchiveAfter, i18n.InvalidArchiveAfter},
+	{todoBot.ErrNotArchived, i18n.NotArchived},
+}
+
+// userMessage maps domain errors to the reply the user gets for them. It
+// reports false for nil and for errors the user can do nothing about.
+func userMessage(l i18n.Localizer, err error) (string, bool) {
+	if errors.Is(err, todoBot.ErrTooManyTasks) {
+		return l.T(i18n.TooManyTasks, todoBot.MaxBulkTasks), true
+	}
+	for _, userError := range userErrors {
+		if errors.Is(err, userError.err) {
+			return l.T(userError.key), true
+		}
+	}
+	return "", false
+}
+
+type correlationKey struct{}
+
+func newCorrelationID() string {
+	id := make([]byte, 4)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+func withCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+func correlationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationKey{}).(string)
+	return id
+}
+
+// retryAction is what the retry button replays: the text or button data of
+// the failed update and, for a message, the message itself, so that files,
+// forwards and media are handled again too.
+type retryAction struct {
+	Action  string          `json:"action"`
+	Message *telego.Message `json:"message,omitempty"`
+}
+
+// getRetryAction returns the zero retryAction when there is nothing to retry.
+func (t *Telegram) getRetryAction(ctx context.Context, chatID int64) (retryAction, error) {
+	data, err := t.cache.GetRetryAction(ctx, chatID)
+	if err != nil || data == "" {
+		return retryAction{}, err
+	}
+	var retry retryAction
+	// Entries saved before messages were kept hold the bare action.
+	if json.Unmarshal([]byte(data), &retry) != nil {
+		return retryAction{Action: data}, nil
+	}
+	return retry, nil
+}
+
+// handleError is the last stop of a failed update. Domain errors end the
+// conversation, draft and pending tasks included, and are explained to the
+// user. Anything else puts the user
+// back into the state the update started from and offers to replay the
+// update; the reply quotes the correlation ID logged with the error.
+func (t *Telegram) handleError(ctx context.Context, chatID int64, prevState int, retry retryAction, err error) error {
+	l := i18n.FromContext(ctx)
+	if message, ok := userMessage(l, err); ok {
+		err := t.todoBot.ResetConversation(ctx, chatID)
+		if err != nil {
+			t.logger(ctx).Error("handleError() -> t.todoBot.ResetConversation()", zap.Error(err))
+		}
+		return t.menu(ctx, chatID, message)
+	}
+
+	log := t.logger(ctx)
+	log.Error("handleUpdate()", zap.Error(err))
+
+	err = t.todoBot.SetUserState(ctx, chatID, prevState)
+	if err != nil {
+		log.Error("handleError() -> t.todoBot.SetUserState()", zap.Error(err))
+	}
+	data, err := json.Marshal(retry)
+	if err == nil {
+		err = t.cache.SetRetryAction(ctx, chatID, string(data))
+	}
+	if err != nil {
+		log.Error("handleError() -> t.cache.SetRetryAction()", zap.Error(err))
+	}
+
+	rows := [][]telego.InlineKeyboardButton{
+		tu.InlineKeyboardRow(tu.InlineKeyboardButton(l.T(i18n.ButtonRetry)).WithCallbackData(telegram.RetryButton)),
+	}
+	if prevState == user.Default {
+		rows = append(rows, menuRows(l)...)
+	} else {
+		rows = append(rows, cancelKeyboard(l.T(i18n.Cancel)).InlineKeyboard...)
+	}
+	return t.render(ctx, chatID, l.T(i18n.SomethingWentWrong, correlationID(ctx)), tu.InlineKeyboard(rows...))
+}
@@ -0,0 +1,92 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/mymmrac/telego"
+	"strings"
+	todoBot "telegramBot/pkg/adapter/todobot"
+	"telegramBot/pkg/i18n"
+	"telegramBot/pkg/model/state/telegram"
+	"telegramBot/pkg/model/state/user"
+	"testing"
+)
+
+// TestRetryReplaysMessage fails an upload and retries it: the retry has no
+// file of its own, so the upload has to be replayed from what was saved.
+func TestRetryReplaysMessage(t *testing.T) {
+	ctx := context.Background()
+	api := startBotAPI(t)
+	tg := newTestTelegram(t, api)
+	data := `[{"name": "Pay rent"}, {"name": "Call mum"}]`
+	api.file("file-1", data)
+
+	err := tg.handleUpdate(ctx, textUpdate(telegram.ImportState))
+	if err != nil {
+		t.Fatalf("handleUpdate(/import) error = %v", err)
+	}
+	upload := textUpdate("")
+	upload.Message.Document = &telego.Document{FileID: "file-1", FileName: "tasks.json", FileSize: int64(len(data))}
+	api.reply("getFile", apiError(400, "Bad Request: invalid file_id"))
+	err = tg.handleUpdate(ctx, upload)
+	if err != nil {
+		t.Fatalf("handleUpdate(upload) error = %v", err)
+	}
+	if markup := api.last("editMessageText").params["reply_markup"]; !strings.Contains(markup, telegram.RetryButton) {
+		t.Fatalf("failed upload reply markup = %s, want a retry button", markup)
+	}
+
+	err = tg.handleUpdate(ctx, buttonUpdate(telegram.RetryButton))
+	if err != nil {
+		t.Fatalf("handleUpdate(retry) error = %v", err)
+	}
+	if markup := api.last("editMessageText").params["reply_markup"]; !strings.Contains(markup, telegram.ImportButton) {
+		t.Errorf("retried upload reply markup = %s, want the import preview", markup)
+	}
+	pending, err := tg.cache.GetPending(ctx, testChat, todoBot.PendingImport)
+	if err != nil || len(pending) != 2 {
+		t.Errorf("GetPending() = %+v, %v, want the two tasks of the file", pending, err)
+	}
+}
+
+func TestRetryReplaysLegacyAction(t *testing.T) {
+	ctx := context.Background()
+	api := startBotAPI(t)
+	tg := newTestTelegram(t, api)
+	err := tg.cache.SetRetryAction(ctx, testChat, telegram.ImportState)
+	if err != nil {
+		t.Fatalf("SetRetryAction() error = %v", err)
+	}
+	err = tg.handleUpdate(ctx, buttonUpdate(telegram.RetryButton))
+	if err != nil {
+		t.Fatalf("handleUpdate(retry) error = %v", err)
+	}
+	state, err := tg.todoBot.GetUserState(ctx, testChat)
+	if err != nil || state != user.WaitingForImportFile {
+		t.Errorf("GetUserState() = %d, %v, want the import prompt's", state, err)
+	}
+}
+
+func TestUserMessage(t *testing.T) {
+	l := i18n.For("en")
+	tests := []struct {
+		name string
+		err  error
+		want string
+		ok   bool
+	}{
+		{"nil", nil, "", false},
+		{"internal", errors.New("database is locked"), "", false},
+		{"mapped", todoBot.ErrTaskNotFound, l.T(i18n.TaskNotFound), true},
+		{"wrapped", fmt.Errorf("GetTask(): %w", todoBot.ErrNotOwner), l.T(i18n.NotYourTask), true},
+		{"quotes the limit", fmt.Errorf("%w: 80 lines", todoBot.ErrTooManyTasks),
+			fmt.Sprintf("Up to %d tasks at once, please split the list", todoBot.MaxBulkTasks), true},
+	}
+	for _, tt := range tests {
+		got, ok := userMessage(l, tt.err)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("%s: userMessage() = %q, %t, want %q, %t", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
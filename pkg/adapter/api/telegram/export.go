@@ -0,0 +1,56 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"github.com/mymmrac/telego"
+	tu "github.com/mymmrac/telego/telegoutil"
+	"strings"
+	"telegramBot/pkg/export"
+	"telegramBot/pkg/i18n"
+	"telegramBot/pkg/model/state/telegram"
+)
+
+var exportLabels = map[export.Format]string{
+	export.JSON:     "JSON",
+	export.CSV:      "CSV",
+	export.Markdown: "Markdown",
+}
+
+func (t *Telegram) exportHandler(ctx context.Context, chatID int64) error {
+	err := t.deleteMessages(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	l := i18n.FromContext(ctx)
+	var row []telego.InlineKeyboardButton
+	for _, format := range export.Formats {
+		row = append(row, tu.InlineKeyboardButton(exportLabels[format]).
+			WithCallbackData(telegram.ExportButton+string(format)))
+	}
+	return t.render(ctx, chatID, l.T(i18n.ChooseExportFormat), withMenu(l, tu.InlineKeyboard(row)))
+}
+
+// exportFormatHandler sends the file as a document of its own, which stays
+// in the chat; the dashboard goes below it.
+func (t *Telegram) exportFormatHandler(ctx context.Context, chatID int64, format export.Format) error {
+	l := i18n.FromContext(ctx)
+	var file bytes.Buffer
+	err := t.todoBot.Export(ctx, chatID, format, &file)
+	if message, ok := userMessage(l, err); ok {
+		return t.menu(ctx, chatID, message)
+	}
+	if err != nil {
+		return err
+	}
+	document := tu.Document(tu.ID(chatID), tu.File(tu.NameReader(&file, format.FileName())))
+	_, err = t.bot.SendDocument(document.WithCaption(l.T(i18n.ExportCaption, strings.ToUpper(string(format)))))
+	if err != nil {
+		return err
+	}
+	err = t.resetDashboard(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	return t.menu(ctx, chatID, "")
+}
@@ -0,0 +1,41 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/mymmrac/telego"
+	"io"
+	"net/http"
+	neturl "net/url"
+)
+
+// downloadFile fetches a file a user sent, reading at most limit bytes.
+func (t *Telegram) downloadFile(ctx context.Context, fileID string, limit int64) ([]byte, error) {
+	file, err := t.bot.GetFile(&telego.GetFileParams{FileID: fileID})
+	if err != nil {
+		return nil, fmt.Errorf("t.bot.GetFile(): %w", err)
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, t.bot.FileDownloadURL(file.FilePath), nil)
+	if err != nil {
+		return nil, fmt.Errorf("http.NewRequestWithContext(): %w", err)
+	}
+	response, err := t.caller.client.Do(request)
+	if err != nil {
+		// The download URL holds the token, as Bot API URLs do.
+		var urlErr *neturl.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("t.caller.client.Do(): %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download file: %w", statusError{code: response.StatusCode})
+	}
+	data, err := io.ReadAll(io.LimitReader(response.Body, limit))
+	if err != nil {
+		return nil, fmt.Errorf("io.ReadAll(): %w", err)
+	}
+	return data, nil
+}
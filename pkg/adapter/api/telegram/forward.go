@@ -0,0 +1,95 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/mymmrac/telego"
+	tu "github.com/mymmrac/telego/telegoutil"
+	"html"
+	"strconv"
+	"strings"
+	todoBot "telegramBot/pkg/adapter/todobot"
+	"telegramBot/pkg/i18n"
+	"telegramBot/pkg/model/state/telegram"
+	"time"
+)
+
+func isForward(message *telego.Message) bool {
+	return message != nil && message.ForwardDate != 0
+}
+
+// forwardOf reads where a forwarded message came from. Messages from public
+// chats link to the original, those from private channels and supergroups
+// link to it for their members.
+func forwardOf(message *telego.Message) todoBot.Forward {
+	f := todoBot.Forward{Text: message.Text, Date: time.Unix(message.ForwardDate, 0)}
+	if f.Text == "" {
+		f.Text = message.Caption
+	}
+	switch {
+	case message.ForwardFrom != nil:
+		f.Sender = strings.TrimSpace(message.ForwardFrom.FirstName + " " + message.ForwardFrom.LastName)
+		if message.ForwardFrom.Username != "" {
+			f.Sender += " (@" + message.ForwardFrom.Username + ")"
+		}
+	case message.ForwardFromChat != nil:
+		chat := message.ForwardFromChat
+		f.Sender = chat.Title
+		if message.ForwardSignature != "" {
+			f.Sender += " (" + message.ForwardSignature + ")"
+		}
+		if message.ForwardFromMessageID == 0 {
+			break
+		}
+		if chat.Username != "" {
+			f.Link = fmt.Sprintf("https://t.me/%s/%d", chat.Username, message.ForwardFromMessageID)
+		} else if id, ok := strings.CutPrefix(strconv.FormatInt(chat.ID, 10), "-100"); ok {
+			f.Link = fmt.Sprintf("https://t.me/c/%s/%d", id, message.ForwardFromMessageID)
+		}
+	default:
+		f.Sender = message.ForwardSenderName
+	}
+	return f
+}
+
+// forwardHandler offers to make a task of a message forwarded from another
+// chat. Nothing is saved until the user confirms.
+func (t *Telegram) forwardHandler(ctx context.Context, chatID int64, message *telego.Message) error {
+	err := t.deleteMessages(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	l := i18n.FromContext(ctx)
+	f := forwardOf(message)
+	newTask, err := t.todoBot.PrepareForward(ctx, chatID, f)
+	if errors.Is(err, todoBot.ErrEmptyTaskName) {
+		return t.menu(ctx, chatID, l.T(i18n.ForwardNoText))
+	}
+	if err != nil {
+		return err
+	}
+
+	text := html.EscapeString(l.T(i18n.ForwardPreview)) + "\n\n<b>" + html.EscapeString(newTask.TaskName) + "</b>"
+	if f.Sender != "" {
+		text += "\n" + html.EscapeString(l.T(i18n.ForwardFrom, f.Sender))
+	}
+	if f.Link != "" {
+		text += "\n" + html.EscapeString(f.Link)
+	}
+	inlineKeyboard := tu.InlineKeyboard(tu.InlineKeyboardRow(
+		tu.InlineKeyboardButton(l.T(i18n.ButtonCreateFromForward)).WithCallbackData(telegram.ForwardButton)))
+	return t.render(ctx, chatID, text, withMenu(l, inlineKeyboard))
+}
+
+func (t *Telegram) confirmForwardHandler(ctx context.Context, chatID int64) error {
+	l := i18n.FromContext(ctx)
+	notice := l.T(i18n.TaskCreated)
+	_, err := t.todoBot.ConfirmPending(ctx, chatID, todoBot.PendingForward)
+	if message, ok := userMessage(l, err); ok {
+		notice = message
+	} else if err != nil {
+		return err
+	}
+	return t.menu(ctx, chatID, notice)
+}
@@ -2,25 +2,78 @@ package telegram
 
 import (
 	"context"
-	tu "github.com/mymmrac/telego/telegoutil"
+	"errors"
+	"strconv"
+	todoBot "telegramBot/pkg/adapter/todobot"
+	"telegramBot/pkg/i18n"
 	"telegramBot/pkg/model/state/telegram"
 	"telegramBot/pkg/model/state/user"
 )
 
+func (t *Telegram) defaultStateHandler(ctx context.Context, chatID int64, in input, firstName string) error {
+	if isForward(in.message) {
+		return t.forwardHandler(ctx, chatID, in.message)
+	}
+	switch in.command {
+	case telegram.StartState:
+		return t.startHandler(ctx, chatID, firstName)
+	case telegram.NewTaskState, telegram.AddTaskState:
+		if in.args != "" {
+			return t.addTaskHandler(ctx, chatID, in.args)
+		}
+		return t.newTaskHandler(ctx, chatID)
+	case telegram.DeleteTaskState:
+		if in.args != "" {
+			return t.deleteTaskByNameHandler(ctx, chatID, in.args)
+		}
+		return t.deleteTaskHandler(ctx, chatID)
+	case telegram.ListOfTasksState:
+		return t.listOfTasksHandler(ctx, chatID)
+	case telegram.PageSizeState:
+		if size, err := strconv.Atoi(in.args); err == nil {
+			return t.setPageSizeHandler(ctx, chatID, size)
+		}
+		return t.pageSizeHandler(ctx, chatID)
+	case telegram.CancelLastActionState:
+		return t.cancelLastActionHandler(ctx, chatID)
+	case telegram.SearchState:
+		return t.searchHandler(ctx, chatID, in.args)
+	case telegram.LanguageState:
+		return t.languageHandler(ctx, chatID)
+	case telegram.SettingsState:
+		return t.settingsHandler(ctx, chatID, "")
+	case telegram.ExportState:
+		return t.exportHandler(ctx, chatID)
+	case telegram.ImportState:
+		return t.importHandler(ctx, chatID)
+	case telegram.CalendarState:
+		return t.calendarHandler(ctx, chatID, "")
+	case telegram.CalDAVState:
+		return t.caldavHandler(ctx, chatID, "")
+	case telegram.TrashState:
+		return t.trashHandler(ctx, chatID, "")
+	case telegram.ArchiveState:
+		return t.archiveHandler(ctx, chatID, in.args)
+	case telegram.BulkState:
+		return t.bulkHandler(ctx, chatID, in.args)
+	case "":
+		if todoBot.IsBulk(in.text) {
+			return t.bulkHandler(ctx, chatID, in.text)
+		}
+	}
+	return t.defaultHandler(ctx, chatID)
+}
+
 func (t *Telegram) startHandler(ctx context.Context, chatID int64, firstName string) error {
 	err := t.deleteMessages(ctx, chatID)
 	if err != nil {
 		return err
 	}
-	_, err = t.bot.SendMessage(tu.Messagef(tu.ID(chatID), "Hello %s!", firstName))
-	if err != nil {
-		return err
-	}
-	err = t.menu(ctx, chatID)
+	err = t.resetDashboard(ctx, chatID)
 	if err != nil {
 		return err
 	}
-	return nil
+	return t.menu(ctx, chatID, i18n.FromContext(ctx).T(i18n.Hello, firstName))
 }
 
 func (t *Telegram) newTaskHandler(ctx context.Context, chatID int64) error {
@@ -28,35 +81,48 @@ func (t *Telegram) newTaskHandler(ctx context.Context, chatID int64) error {
 	if err != nil {
 		return err
 	}
-	err = t.todoBot.SetUserState(chatID, user.WaitingForNewTaskName)
+	err = t.todoBot.SetUserState(ctx, chatID, user.WaitingForNewTaskName)
+	if err != nil {
+		return err
+	}
+	err = t.todoBot.StartDraft(ctx, chatID)
 	if err != nil {
 		return err
 	}
+	l := i18n.FromContext(ctx)
+	return t.render(ctx, chatID, l.T(i18n.SendTaskName), cancelKeyboard(l.T(i18n.CancelTaskCreation)))
+}
 
-	_, err = t.todoBot.CreateNewTask(chatID)
+func (t *Telegram) addTaskHandler(ctx context.Context, chatID int64, args string) error {
+	err := t.deleteMessages(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	name, description := splitTask(args)
+	if name == "" {
+		return t.newTaskHandler(ctx, chatID)
+	}
+	_, err = t.todoBot.CreateTask(ctx, chatID, name, description)
 	if err != nil {
 		return err
 	}
-	inlineKeyboard := tu.InlineKeyboard(
-		tu.InlineKeyboardRow(
-			tu.InlineKeyboardButton("Cancel task creation").
-				WithCallbackData(telegram.CancelLastActionState),
-		),
-	)
-	message := tu.Message(
-		tu.ID(chatID),
-		"Send task name",
-	).WithReplyMarkup(inlineKeyboard)
+	return t.menu(ctx, chatID, i18n.FromContext(ctx).T(i18n.TaskCreated))
+}
 
-	messageInfo, _ := t.bot.SendMessage(message)
+func (t *Telegram) deleteTaskByNameHandler(ctx context.Context, chatID int64, taskName string) error {
+	err := t.deleteMessages(ctx, chatID)
 	if err != nil {
 		return err
 	}
-	err = t.cache.Set(ctx, chatID, messageInfo.MessageID)
+	l := i18n.FromContext(ctx)
+	taskID, err := t.todoBot.DeleteTask(ctx, chatID, taskName)
+	if message, ok := userMessage(l, err); ok {
+		return t.menu(ctx, chatID, message)
+	}
 	if err != nil {
 		return err
 	}
-	return nil
+	return t.taskDeletedHandler(ctx, chatID, taskID)
 }
 
 func (t *Telegram) deleteTaskHandler(ctx context.Context, chatID int64) error {
@@ -64,68 +130,204 @@ func (t *Telegram) deleteTaskHandler(ctx context.Context, chatID int64) error {
 	if err != nil {
 		return err
 	}
-	err = t.todoBot.SetUserState(chatID, user.WaitingForTaskNameToBeDeleted)
+	err = t.todoBot.SetUserState(ctx, chatID, user.WaitingForTaskNameToBeDeleted)
+	if err != nil {
+		return err
+	}
+	l := i18n.FromContext(ctx)
+	return t.render(ctx, chatID, l.T(i18n.SendTaskName), cancelKeyboard(l.T(i18n.CancelTaskDeletion)))
+}
+
+func (t *Telegram) listOfTasksHandler(ctx context.Context, chatID int64) error {
+	err := t.deleteMessages(ctx, chatID)
 	if err != nil {
 		return err
 	}
-	inlineKeyboard := tu.InlineKeyboard(
-		tu.InlineKeyboardRow(
-			tu.InlineKeyboardButton("Cancel task deletion").
-				WithCallbackData(telegram.CancelLastActionState),
-		),
-	)
-	message := tu.Message(
-		tu.ID(chatID),
-		"Send task name",
-	).WithReplyMarkup(inlineKeyboard)
+	return t.showListPage(ctx, chatID, 0, "")
+}
 
-	messageInfo, _ := t.bot.SendMessage(message)
+func (t *Telegram) cancelLastActionHandler(ctx context.Context, chatID int64) error {
+	err := t.deleteMessages(ctx, chatID)
 	if err != nil {
 		return err
 	}
-	err = t.cache.Set(ctx, chatID, messageInfo.MessageID)
+	return t.menu(ctx, chatID, i18n.FromContext(ctx).T(i18n.NothingToCancel))
+}
+
+func (t *Telegram) defaultHandler(ctx context.Context, chatID int64) error {
+	err := t.deleteMessages(ctx, chatID)
 	if err != nil {
 		return err
 	}
-	return nil
+	return t.menu(ctx, chatID, "")
 }
 
-func (t *Telegram) listOfTasksHandler(ctx context.Context, chatID int64) error {
+// isBusyCommand reports commands that must not interrupt an unfinished
+// multi-step action.
+func isBusyCommand(command string) bool {
+	switch command {
+	case telegram.StartState, telegram.NewTaskState, telegram.AddTaskState, telegram.DeleteTaskState,
+		telegram.ListOfTasksState, telegram.PageSizeState, telegram.SearchState, telegram.LanguageState,
+		telegram.SettingsState, telegram.ExportState, telegram.ImportState, telegram.BulkState,
+		telegram.CalendarState, telegram.CalDAVState, telegram.TrashState, telegram.ArchiveState:
+		return true
+	}
+	return false
+}
+
+func (t *Telegram) finishLastActionHandler(ctx context.Context, chatID int64) error {
 	err := t.deleteMessages(ctx, chatID)
 	if err != nil {
 		return err
 	}
-	err = t.getListOfTasks(ctx, chatID)
+	l := i18n.FromContext(ctx)
+	return t.render(ctx, chatID, l.T(i18n.FinishLastAction), cancelKeyboard(l.T(i18n.Cancel)))
+}
+
+func (t *Telegram) cancelStateHandler(ctx context.Context, chatID int64) error {
+	err := t.todoBot.SetUserState(ctx, chatID, user.Default)
 	if err != nil {
 		return err
 	}
-	err = t.menu(ctx, chatID)
+	err = t.deleteMessages(ctx, chatID)
 	if err != nil {
 		return err
 	}
-	return nil
+	return t.menu(ctx, chatID, i18n.FromContext(ctx).T(i18n.LastActionCanceled))
 }
 
-func (t *Telegram) cancelLastActionHandler(ctx context.Context, chatID int64) error {
-	messageInfo, err := t.bot.SendMessage(tu.Message(tu.ID(chatID), "There is nothing to cancel"))
+func (t *Telegram) taskNameToBeDeletedHandler(ctx context.Context, chatID int64, in input) error {
+	if in.command == telegram.CancelLastActionState {
+		return t.cancelStateHandler(ctx, chatID)
+	}
+	if isBusyCommand(in.command) {
+		return t.finishLastActionHandler(ctx, chatID)
+	}
+	err := t.deleteMessages(ctx, chatID)
 	if err != nil {
 		return err
 	}
-	err = t.cache.Set(ctx, chatID, messageInfo.MessageID)
+	l := i18n.FromContext(ctx)
+	taskID, err := t.todoBot.DeleteTask(ctx, chatID, in.text)
+	message, failed := userMessage(l, err)
+	if !failed && err != nil {
+		return err
+	}
+	err = t.todoBot.SetUserState(ctx, chatID, user.Default)
 	if err != nil {
 		return err
 	}
-	return nil
+	if failed {
+		return t.menu(ctx, chatID, message)
+	}
+	return t.taskDeletedHandler(ctx, chatID, taskID)
 }
 
-func (t *Telegram) defaultHandler(ctx context.Context, chatID int64) error {
+func (t *Telegram) newTaskNameHandler(ctx context.Context, chatID int64, in input) error {
+	if in.command == telegram.CancelLastActionState {
+		err := t.todoBot.CancelDraft(ctx, chatID)
+		if err != nil {
+			return err
+		}
+		return t.cancelStateHandler(ctx, chatID)
+	}
+	if isBusyCommand(in.command) {
+		return t.finishLastActionHandler(ctx, chatID)
+	}
 	err := t.deleteMessages(ctx, chatID)
 	if err != nil {
 		return err
 	}
-	err = t.menu(ctx, chatID)
+	if attachment, ok := attachmentOf(in.message); ok {
+		notice, err := t.attachToDraft(ctx, chatID, attachment)
+		if errors.Is(err, todoBot.ErrDraftNotFound) {
+			return t.draftExpiredHandler(ctx, chatID)
+		}
+		if err != nil {
+			return err
+		}
+		// A caption is taken as the name, as text sent on its own would be.
+		if in.message.Caption == "" {
+			l := i18n.FromContext(ctx)
+			return t.render(ctx, chatID, notice+"\n\n"+l.T(i18n.SendTaskName),
+				cancelKeyboard(l.T(i18n.CancelTaskCreation)))
+		}
+		in.text = in.message.Caption
+	}
+	err = t.todoBot.SetDraftName(ctx, chatID, in.text)
+	if errors.Is(err, todoBot.ErrDraftNotFound) {
+		return t.draftExpiredHandler(ctx, chatID)
+	}
+	if errors.Is(err, todoBot.ErrEmptyTaskName) {
+		l := i18n.FromContext(ctx)
+		return t.render(ctx, chatID, l.T(i18n.EmptyTaskName)+"\n\n"+l.T(i18n.SendTaskName),
+			cancelKeyboard(l.T(i18n.CancelTaskCreation)))
+	}
+	if err != nil {
+		return err
+	}
+	err = t.todoBot.SetUserState(ctx, chatID, user.WaitingForNewTaskDescription)
+	if err != nil {
+		return err
+	}
+	l := i18n.FromContext(ctx)
+	return t.render(ctx, chatID, l.T(i18n.SendTaskDescription), cancelKeyboard(l.T(i18n.CancelTaskCreation)))
+}
+
+func (t *Telegram) newTaskDescriptionHandler(ctx context.Context, chatID int64, in input) error {
+	if in.command == telegram.CancelLastActionState {
+		err := t.todoBot.CancelDraft(ctx, chatID)
+		if err != nil {
+			return err
+		}
+		return t.cancelStateHandler(ctx, chatID)
+	}
+	if isBusyCommand(in.command) {
+		return t.finishLastActionHandler(ctx, chatID)
+	}
+	err := t.deleteMessages(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	if attachment, ok := attachmentOf(in.message); ok {
+		notice, err := t.attachToDraft(ctx, chatID, attachment)
+		if errors.Is(err, todoBot.ErrDraftNotFound) {
+			return t.draftExpiredHandler(ctx, chatID)
+		}
+		if err != nil {
+			return err
+		}
+		if in.message.Caption == "" {
+			l := i18n.FromContext(ctx)
+			return t.render(ctx, chatID, notice+"\n\n"+l.T(i18n.SendTaskDescription),
+				cancelKeyboard(l.T(i18n.CancelTaskCreation)))
+		}
+		in.text = in.message.Caption
+	}
+	err = t.todoBot.SetDraftDescription(ctx, chatID, in.text)
+	if errors.Is(err, todoBot.ErrDraftNotFound) {
+		return t.draftExpiredHandler(ctx, chatID)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = t.todoBot.CommitDraft(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	err = t.todoBot.SetUserState(ctx, chatID, user.Default)
+	if err != nil {
+		return err
+	}
+	return t.menu(ctx, chatID, i18n.FromContext(ctx).T(i18n.TaskCreated))
+}
+
+// draftExpiredHandler ends a creation whose draft vanished from the session,
+// for example after its expiry, instead of leaving the user stuck in it.
+func (t *Telegram) draftExpiredHandler(ctx context.Context, chatID int64) error {
+	err := t.todoBot.SetUserState(ctx, chatID, user.Default)
 	if err != nil {
 		return err
 	}
-	return nil
+	return t.menu(ctx, chatID, i18n.FromContext(ctx).T(i18n.DraftExpired))
 }
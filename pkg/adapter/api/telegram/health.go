@@ -0,0 +1,31 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Alive fails when the long-poll loop has not completed a getUpdates call
+// within the configured window.
+func (t *Telegram) Alive(ctx context.Context) error {
+	polled := t.caller.polled.Load()
+	if polled == 0 {
+		return errors.New("not polling yet")
+	}
+	since := time.Since(time.Unix(polled, 0))
+	if since > t.cfg.PollStaleAfter {
+		return fmt.Errorf("last successful poll %s ago", since.Truncate(time.Second))
+	}
+	return nil
+}
+
+// Ready additionally requires the bot to know who it is, which a wrong token
+// or an unreachable API prevents.
+func (t *Telegram) Ready(ctx context.Context) error {
+	if !t.identified.Load() {
+		return errors.New("getMe has not succeeded yet")
+	}
+	return t.Alive(ctx)
+}
@@ -0,0 +1,127 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	tu "github.com/mymmrac/telego/telegoutil"
+	"html"
+	"strings"
+	todoBot "telegramBot/pkg/adapter/todobot"
+	"telegramBot/pkg/i18n"
+	"telegramBot/pkg/importer"
+	"telegramBot/pkg/model/state/telegram"
+	"telegramBot/pkg/model/state/user"
+)
+
+// importMaxFailures caps the unreadable rows listed in an import preview.
+const importMaxFailures = 10
+
+func (t *Telegram) importHandler(ctx context.Context, chatID int64) error {
+	err := t.deleteMessages(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	err = t.todoBot.SetUserState(ctx, chatID, user.WaitingForImportFile)
+	if err != nil {
+		return err
+	}
+	return t.importPrompt(ctx, chatID, "")
+}
+
+func (t *Telegram) importPrompt(ctx context.Context, chatID int64, notice string) error {
+	l := i18n.FromContext(ctx)
+	text := l.T(i18n.SendImportFile)
+	if notice != "" {
+		text = notice + "\n\n" + text
+	}
+	return t.render(ctx, chatID, text, cancelKeyboard(l.T(i18n.Cancel)))
+}
+
+// importFileHandler previews an uploaded file. The user stays in the state
+// until they confirm, so another file can replace a wrong one.
+func (t *Telegram) importFileHandler(ctx context.Context, chatID int64, in input) error {
+	if in.command == telegram.CancelLastActionState {
+		err := t.todoBot.CancelPending(ctx, chatID, todoBot.PendingImport)
+		if err != nil {
+			return err
+		}
+		return t.cancelStateHandler(ctx, chatID)
+	}
+	if isBusyCommand(in.command) {
+		return t.finishLastActionHandler(ctx, chatID)
+	}
+	err := t.deleteMessages(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	l := i18n.FromContext(ctx)
+	if in.message == nil || in.message.Document == nil {
+		return t.importPrompt(ctx, chatID, html.EscapeString(l.T(i18n.SendFileAsDocument)))
+	}
+	document := in.message.Document
+	if document.FileSize > todoBot.MaxImportSize {
+		return t.importPrompt(ctx, chatID, html.EscapeString(l.T(i18n.ImportTooLarge)))
+	}
+	data, err := t.downloadFile(ctx, document.FileID, todoBot.MaxImportSize+1)
+	if err != nil {
+		return err
+	}
+	result, err := t.todoBot.PrepareImport(ctx, chatID, document.FileName, data)
+	if message, ok := userMessage(l, err); ok {
+		return t.importPrompt(ctx, chatID, html.EscapeString(message))
+	}
+	if err != nil {
+		return err
+	}
+
+	report := importReport(l, result)
+	if len(result.Tasks) == 0 {
+		return t.importPrompt(ctx, chatID, html.EscapeString(l.T(i18n.NothingToImport))+report)
+	}
+	open, done := result.Count()
+	text := html.EscapeString(l.T(i18n.ImportPreview, strings.ToUpper(string(result.Format)), len(result.Tasks),
+		open, done)) + report
+	inlineKeyboard := tu.InlineKeyboard(
+		tu.InlineKeyboardRow(
+			tu.InlineKeyboardButton(l.T(i18n.ButtonImport, len(result.Tasks))).WithCallbackData(telegram.ImportButton),
+			tu.InlineKeyboardButton(l.T(i18n.Cancel)).WithCallbackData(telegram.CancelLastActionState),
+		),
+	)
+	return t.render(ctx, chatID, text, inlineKeyboard)
+}
+
+// importReport lists the rows of an import that could not be read.
+func importReport(l i18n.Localizer, result importer.Result) string {
+	if len(result.Failed) == 0 {
+		return ""
+	}
+	var report strings.Builder
+	fmt.Fprintf(&report, "\n\n%s", html.EscapeString(l.T(i18n.ImportFailed, len(result.Failed))))
+	for i, failure := range result.Failed {
+		if i == importMaxFailures {
+			report.WriteString("\n" + html.EscapeString(l.T(i18n.ImportMore, len(result.Failed)-i)))
+			break
+		}
+		report.WriteString("\n" + html.EscapeString(l.T(i18n.ImportFailure, failure.Line, failure.Err)))
+	}
+	return report.String()
+}
+
+// confirmPendingHandler saves the tasks of an import or a bulk creation and
+// reports how many there were with the message under key.
+func (t *Telegram) confirmPendingHandler(ctx context.Context, chatID int64, kind todoBot.PendingKind,
+	key string) error {
+	l := i18n.FromContext(ctx)
+	saved, err := t.todoBot.ConfirmPending(ctx, chatID, kind)
+	notice := l.T(key, saved)
+	if message, ok := userMessage(l, err); ok {
+		notice = message
+	} else if err != nil {
+		return err
+	}
+	err = t.todoBot.SetUserState(ctx, chatID, user.Default)
+	if err != nil {
+		return err
+	}
+	return t.menu(ctx, chatID, notice)
+}
@@ -0,0 +1,39 @@
+package telegram
+
+import (
+	"context"
+	"github.com/mymmrac/telego"
+	tu "github.com/mymmrac/telego/telegoutil"
+	"telegramBot/pkg/i18n"
+	"telegramBot/pkg/model/state/telegram"
+)
+
+func (t *Telegram) languageHandler(ctx context.Context, chatID int64) error {
+	err := t.deleteMessages(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	l := i18n.FromContext(ctx)
+	var row []telego.InlineKeyboardButton
+	for _, language := range i18n.Languages {
+		label := language.Name
+		if language.Code == l.Language() {
+			label = "• " + label + " •"
+		}
+		row = append(row, tu.InlineKeyboardButton(label).
+			WithCallbackData(telegram.LanguageButton+language.Code))
+	}
+	return t.render(ctx, chatID, l.T(i18n.ChooseLanguage), tu.InlineKeyboard(row))
+}
+
+func (t *Telegram) setLanguageHandler(ctx context.Context, chatID int64, language string) error {
+	err := t.todoBot.SetUserLanguage(ctx, chatID, language)
+	if message, ok := userMessage(i18n.FromContext(ctx), err); ok {
+		return t.menu(ctx, chatID, message)
+	}
+	if err != nil {
+		return err
+	}
+	ctx = i18n.WithLanguage(ctx, language)
+	return t.menu(ctx, chatID, i18n.FromContext(ctx).T(i18n.LanguageSet))
+}
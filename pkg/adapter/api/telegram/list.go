@@ -0,0 +1,223 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"github.com/mymmrac/telego"
+	tu "github.com/mymmrac/telego/telegoutil"
+	"html"
+	"strconv"
+	"strings"
+	"telegramBot/pkg/adapter/todobot"
+	"telegramBot/pkg/i18n"
+	"telegramBot/pkg/model/state/telegram"
+	"telegramBot/pkg/model/task/priority"
+	"telegramBot/pkg/model/task/status"
+)
+
+const listButtonsPerRow = 5
+
+var priorityMarks = map[int]string{priority.High: "🔴", priority.Medium: "🟠", priority.Low: "🔵"}
+
+func (t *Telegram) showListPage(ctx context.Context, chatID int64, number int, notice string) error {
+	l := i18n.FromContext(ctx)
+	text, inlineKeyboard, err := t.listPage(ctx, chatID, number, notice)
+	if err != nil {
+		return err
+	}
+	return t.render(ctx, chatID, text, withMenu(l, inlineKeyboard))
+}
+
+func (t *Telegram) listPage(ctx context.Context, chatID int64, number int,
+	notice string) (string, *telego.InlineKeyboardMarkup, error) {
+	l := i18n.FromContext(ctx)
+	page, err := t.todoBot.GetTasksPage(ctx, chatID, number)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var text strings.Builder
+	if notice != "" {
+		fmt.Fprintf(&text, "%s\n\n", html.EscapeString(notice))
+	}
+	if page.Total == 0 {
+		text.WriteString(l.T(i18n.NoTasks))
+		return text.String(), nil, nil
+	}
+	text.WriteString(l.N(i18n.ListHeader, page.Total, page.First(), page.First()+len(page.Tasks)-1,
+		page.Total, page.Number+1, page.Pages()))
+	text.WriteString("\n")
+
+	var rows [][]telego.InlineKeyboardButton
+	var row []telego.InlineKeyboardButton
+	for i, task := range page.Tasks {
+		fmt.Fprintf(&text, "\n%d. %s", page.First()+i, html.EscapeString(task.TaskName))
+		if count := page.Attachments[task.ID]; count > 0 {
+			fmt.Fprintf(&text, " 📎%d", count)
+		}
+		row = append(row, tu.InlineKeyboardButton(strconv.Itoa(page.First()+i)).
+			WithCallbackData(fmt.Sprintf("%s%d:%d", telegram.OpenTaskButton, task.ID, page.Number)))
+		if len(row) == listButtonsPerRow {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+
+	var navigation []telego.InlineKeyboardButton
+	if page.HasPrevious() {
+		navigation = append(navigation, tu.InlineKeyboardButton("◀").
+			WithCallbackData(telegram.ListPageButton+strconv.Itoa(page.Number-1)))
+	}
+	if page.HasNext() {
+		navigation = append(navigation, tu.InlineKeyboardButton("▶").
+			WithCallbackData(telegram.ListPageButton+strconv.Itoa(page.Number+1)))
+	}
+	if len(navigation) > 0 {
+		rows = append(rows, navigation)
+	}
+	return text.String(), tu.InlineKeyboard(rows...), nil
+}
+
+func (t *Telegram) openTaskHandler(ctx context.Context, chatID, taskID int64, number int) error {
+	l := i18n.FromContext(ctx)
+	task, err := t.todoBot.GetTask(ctx, chatID, taskID)
+	if message, ok := userMessage(l, err); ok {
+		return t.showListPage(ctx, chatID, number, message)
+	}
+	if err != nil {
+		return err
+	}
+
+	text := "<b>" + html.EscapeString(task.TaskName) + "</b>"
+	if task.Status == status.Done {
+		text += "\n✅ " + html.EscapeString(l.T(i18n.TaskStatusDone))
+	}
+	if task.TaskDescription != "" {
+		text += "\n" + html.EscapeString(task.TaskDescription)
+	}
+	if len(task.Tags) > 0 {
+		text += "\n\n#" + html.EscapeString(strings.Join(task.Tags, " #"))
+	}
+	if mark, ok := priorityMarks[task.Priority]; ok {
+		text += "\n" + mark + " !" + priority.Name(task.Priority)
+	}
+	if task.List != "" {
+		text += "\n📂 " + html.EscapeString(task.List)
+	}
+	if !task.Due.IsZero() {
+		p, err := t.todoBot.GetProfile(ctx, chatID)
+		if err != nil {
+			return err
+		}
+		text += "\n📅 " + html.EscapeString(task.Due.Format(p.DateFormat))
+		if task.Repeat != "" {
+			text += " 🔁 @" + task.Repeat
+		}
+	}
+	attachments, err := t.todoBot.GetAttachments(ctx, chatID, int64(task.ID))
+	if err != nil {
+		return err
+	}
+	comments, err := t.todoBot.GetComments(ctx, chatID, int64(task.ID))
+	if err != nil {
+		return err
+	}
+	row := []telego.InlineKeyboardButton{commentsButton(l, len(comments), int64(task.ID), number)}
+	if len(attachments) > 0 {
+		row = append(row, tu.InlineKeyboardButton(l.T(i18n.ButtonAttachments, len(attachments))).
+			WithCallbackData(fmt.Sprintf("%s%d:%d", telegram.AttachmentsButton, task.ID, number)))
+	}
+	statusButton := tu.InlineKeyboardButton(l.T(i18n.ButtonComplete)).
+		WithCallbackData(fmt.Sprintf("%s%d:%d", telegram.CompleteTaskButton, task.ID, number))
+	if task.Status == status.Done {
+		statusButton = tu.InlineKeyboardButton(l.T(i18n.ButtonReopen)).
+			WithCallbackData(fmt.Sprintf("%s%d:%d", telegram.ReopenTaskButton, task.ID, number))
+	}
+	rows := [][]telego.InlineKeyboardButton{{statusButton}, row}
+	rows = append(rows, tu.InlineKeyboardRow(
+		tu.InlineKeyboardButton(l.T(i18n.ButtonDeleteThis)).
+			WithCallbackData(fmt.Sprintf("%s%d:%d", telegram.DeleteTaskButton, task.ID, number)),
+		tu.InlineKeyboardButton(l.T(i18n.ButtonBack)).
+			WithCallbackData(telegram.ListPageButton+strconv.Itoa(number)),
+	))
+	return t.render(ctx, chatID, text, tu.InlineKeyboard(rows...))
+}
+
+// completeTaskHandler and reopenTaskHandler stay on the task view, which
+// shows the new status.
+func (t *Telegram) completeTaskHandler(ctx context.Context, chatID, taskID int64, number int) error {
+	err := t.todoBot.CompleteTask(ctx, chatID, taskID)
+	if message, ok := userMessage(i18n.FromContext(ctx), err); ok {
+		return t.showListPage(ctx, chatID, number, message)
+	}
+	if err != nil {
+		return err
+	}
+	return t.openTaskHandler(ctx, chatID, taskID, number)
+}
+
+func (t *Telegram) reopenTaskHandler(ctx context.Context, chatID, taskID int64, number int) error {
+	err := t.todoBot.ReopenTask(ctx, chatID, taskID)
+	if message, ok := userMessage(i18n.FromContext(ctx), err); ok {
+		return t.showListPage(ctx, chatID, number, message)
+	}
+	if err != nil {
+		return err
+	}
+	return t.openTaskHandler(ctx, chatID, taskID, number)
+}
+
+func (t *Telegram) deleteTaskButtonHandler(ctx context.Context, chatID, taskID int64, number int) error {
+	l := i18n.FromContext(ctx)
+	err := t.todoBot.DeleteTaskByID(ctx, chatID, taskID)
+	if message, ok := userMessage(l, err); ok {
+		return t.showListPage(ctx, chatID, number, message)
+	}
+	if err != nil {
+		return err
+	}
+	text, inlineKeyboard, err := t.listPage(ctx, chatID, number, l.T(i18n.TaskDeleted))
+	if err != nil {
+		return err
+	}
+	rows := [][]telego.InlineKeyboardButton{undoRow(l, taskID, number)}
+	if inlineKeyboard != nil {
+		rows = append(rows, inlineKeyboard.InlineKeyboard...)
+	}
+	return t.render(ctx, chatID, text, withMenu(l, tu.InlineKeyboard(rows...)))
+}
+
+func (t *Telegram) pageSizeHandler(ctx context.Context, chatID int64) error {
+	err := t.deleteMessages(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	current, err := t.todoBot.GetPageSize(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	var row []telego.InlineKeyboardButton
+	for _, size := range todobot.PageSizes {
+		label := strconv.Itoa(size)
+		if size == current {
+			label = "• " + label + " •"
+		}
+		row = append(row, tu.InlineKeyboardButton(label).
+			WithCallbackData(telegram.PageSizeButton+strconv.Itoa(size)))
+	}
+	return t.render(ctx, chatID, i18n.FromContext(ctx).T(i18n.TasksPerPage), tu.InlineKeyboard(row))
+}
+
+func (t *Telegram) setPageSizeHandler(ctx context.Context, chatID int64, size int) error {
+	err := t.todoBot.SetPageSize(ctx, chatID, size)
+	if message, ok := userMessage(i18n.FromContext(ctx), err); ok {
+		return t.menu(ctx, chatID, message)
+	}
+	if err != nil {
+		return err
+	}
+	return t.menu(ctx, chatID, i18n.FromContext(ctx).T(i18n.TasksPerPageSet, size))
+}
@@ -0,0 +1,45 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/zap"
+	"strings"
+)
+
+// telegoLogger routes telego's own logs into zap. Request URLs carry the bot
+// token, so it is cut out of every line.
+type telegoLogger struct {
+	log      *zap.SugaredLogger
+	replacer *strings.Replacer
+}
+
+func newTelegoLogger(log *zap.Logger, token string) telegoLogger {
+	return telegoLogger{
+		log:      log.Named("telego").WithOptions(zap.AddCallerSkip(1)).Sugar(),
+		replacer: strings.NewReplacer(token, "BOT_TOKEN"),
+	}
+}
+
+func (l telegoLogger) Debugf(format string, args ...any) {
+	l.log.Debug(l.replacer.Replace(fmt.Sprintf(format, args...)))
+}
+
+func (l telegoLogger) Errorf(format string, args ...any) {
+	l.log.Error(l.replacer.Replace(fmt.Sprintf(format, args...)))
+}
+
+type loggerKey struct{}
+
+func withLogger(ctx context.Context, log *zap.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, log)
+}
+
+// logger returns the logger of the update being handled, carrying its
+// fields, or the bot-wide one outside of an update.
+func (t *Telegram) logger(ctx context.Context) *zap.Logger {
+	if log, ok := ctx.Value(loggerKey{}).(*zap.Logger); ok {
+		return log
+	}
+	return t.log
+}
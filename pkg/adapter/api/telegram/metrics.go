@@ -0,0 +1,86 @@
+package telegram
+
+import (
+	"strings"
+	"telegramBot/pkg/model/state/telegram"
+	"telegramBot/pkg/model/state/user"
+)
+
+var buttons = []string{
+	telegram.DeleteTaskButton,
+	telegram.SearchPageButton,
+	telegram.ListPageButton,
+	telegram.OpenTaskButton,
+	telegram.PageSizeButton,
+	telegram.LanguageButton,
+	telegram.RetryButton,
+	telegram.SettingButton,
+	telegram.DateFormatButton,
+	telegram.TimezoneButton,
+	telegram.NoListButton,
+	telegram.QuietOffButton,
+	telegram.DigestOffButton,
+	telegram.DoneTaskButton,
+	telegram.CompleteTaskButton,
+	telegram.ReopenTaskButton,
+	telegram.ExportButton,
+	telegram.ImportButton,
+	telegram.BulkButton,
+	telegram.ResetCalendarButton,
+	telegram.NewAppPasswordButton,
+	telegram.RevokeAppPasswordsButton,
+	telegram.ForwardButton,
+	telegram.AttachmentsButton,
+	telegram.CommentsButton,
+	telegram.AddCommentButton,
+	telegram.UndoDeleteButton,
+	telegram.RestoreTaskButton,
+	telegram.PurgeTaskButton,
+	telegram.EmptyTrashButton,
+	telegram.ConfirmEmptyTrashButton,
+	telegram.ArchivePageButton,
+	telegram.UnarchiveButton,
+	telegram.ArchiveAfterButton,
+}
+
+var stateLabels = map[int]string{
+	user.Default:                       "default",
+	user.WaitingForNewTaskName:         "waiting_for_new_task_name",
+	user.WaitingForNewTaskDescription:  "waiting_for_new_task_description",
+	user.WaitingForTaskNameToBeDeleted: "waiting_for_task_name_to_be_deleted",
+	user.WaitingForTimezone:            "waiting_for_timezone",
+	user.WaitingForDefaultList:         "waiting_for_default_list",
+	user.WaitingForQuietHours:          "waiting_for_quiet_hours",
+	user.WaitingForDigestTime:          "waiting_for_digest_time",
+	user.WaitingForImportFile:          "waiting_for_import_file",
+	user.WaitingForBulkTasks:           "waiting_for_bulk_tasks",
+	user.WaitingForComment:             "waiting_for_comment",
+	user.WaitingForArchiveAfter:        "waiting_for_archive_after",
+}
+
+// commandLabel names an action for metrics. Free text and unknown commands
+// share a label so user input never becomes a label value.
+func (t *Telegram) commandLabel(action string) string {
+	for _, button := range buttons {
+		if strings.HasPrefix(action, button) {
+			return button
+		}
+	}
+	in := t.parseInput(action)
+	if in.command == "" {
+		return "text"
+	}
+	for _, command := range commandMenu {
+		if in.command == command {
+			return command
+		}
+	}
+	return "unknown"
+}
+
+func stateLabel(state int) string {
+	if label, ok := stateLabels[state]; ok {
+		return label
+	}
+	return "unknown"
+}
@@ -0,0 +1,85 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"github.com/mymmrac/telego"
+	"github.com/mymmrac/telego/telegoapi"
+	tu "github.com/mymmrac/telego/telegoutil"
+	"go.uber.org/zap"
+	"strings"
+)
+
+// render shows a screen in the chat's dashboard, the single bot message that
+// is edited in place for every step of a conversation. When the dashboard is
+// gone or can no longer be edited a new one is sent and remembered instead.
+func (t *Telegram) render(ctx context.Context, chatID int64, text string,
+	inlineKeyboard *telego.InlineKeyboardMarkup) error {
+	messageID, err := t.cache.GetDashboard(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	if messageID != 0 {
+		_, err = t.bot.EditMessageText(&telego.EditMessageTextParams{
+			ChatID:      tu.ID(chatID),
+			MessageID:   messageID,
+			Text:        text,
+			ParseMode:   telego.ModeHTML,
+			ReplyMarkup: inlineKeyboard,
+		})
+		if err == nil || isNotModified(err) {
+			return nil
+		}
+		t.logger(ctx).Warn("render() -> t.bot.EditMessageText()", zap.Error(err))
+		t.deleteMessage(ctx, chatID, messageID)
+	}
+
+	message := tu.Message(tu.ID(chatID), text).WithParseMode(telego.ModeHTML)
+	if inlineKeyboard != nil {
+		message = message.WithReplyMarkup(inlineKeyboard)
+	}
+	messageInfo, err := t.bot.SendMessage(message)
+	if err != nil {
+		return err
+	}
+	return t.cache.SetDashboard(ctx, chatID, messageInfo.MessageID)
+}
+
+// resetDashboard drops the current dashboard so the next render starts a
+// fresh one at the bottom of the chat.
+func (t *Telegram) resetDashboard(ctx context.Context, chatID int64) error {
+	messageID, err := t.cache.GetDashboard(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	if messageID != 0 {
+		t.deleteMessage(ctx, chatID, messageID)
+	}
+	return t.cache.DeleteDashboard(ctx, chatID)
+}
+
+func (t *Telegram) deleteMessages(ctx context.Context, chatID int64) error {
+	messageIDs, err := t.cache.Get(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	for _, v := range messageIDs {
+		t.deleteMessage(ctx, chatID, v)
+	}
+	return nil
+}
+
+// deleteMessage is best effort: Telegram refuses to delete messages older
+// than 48 hours or already removed by the user, and neither should abort the
+// handler that is cleaning up.
+func (t *Telegram) deleteMessage(ctx context.Context, chatID int64, messageID int) {
+	err := t.bot.DeleteMessage(&telego.DeleteMessageParams{ChatID: tu.ID(chatID), MessageID: messageID})
+	if err != nil {
+		t.logger(ctx).Warn("deleteMessage() -> t.bot.DeleteMessage()", zap.Int("messageID", messageID), zap.Error(err))
+	}
+}
+
+func isNotModified(err error) bool {
+	var apiErr *telegoapi.Error
+	return errors.As(err, &apiErr) && strings.Contains(apiErr.Description, "message is not modified")
+}
@@ -0,0 +1,77 @@
+package telegram
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name          string
+		dashboard     int
+		edit          []string
+		wantMethods   []string
+		wantDashboard int
+	}{
+		{"first screen", 0, nil, []string{"sendMessage"}, 100},
+		{"edited in place", 7, nil, []string{"editMessageText"}, 7},
+		{"same screen again", 7, []string{apiError(400, "Bad Request: message is not modified")},
+			[]string{"editMessageText"}, 7},
+		{"dashboard deleted by the user", 7, []string{apiError(400, "Bad Request: message to edit not found")},
+			[]string{"editMessageText", "deleteMessage", "sendMessage"}, 100},
+		{"dashboard too old to edit", 7, []string{apiError(400, "Bad Request: message can't be edited")},
+			[]string{"editMessageText", "deleteMessage", "sendMessage"}, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			api := startBotAPI(t)
+			tg := newTestTelegram(t, api)
+			if tt.dashboard != 0 {
+				err := tg.cache.SetDashboard(ctx, testChat, tt.dashboard)
+				if err != nil {
+					t.Fatalf("SetDashboard() error = %v", err)
+				}
+			}
+			api.reply("editMessageText", tt.edit...)
+
+			err := tg.render(ctx, testChat, "Menu", nil)
+			if err != nil {
+				t.Fatalf("render() error = %v", err)
+			}
+			if got := api.methods(); !reflect.DeepEqual(got, tt.wantMethods) {
+				t.Errorf("Bot API calls = %q, want %q", got, tt.wantMethods)
+			}
+			dashboard, err := tg.cache.GetDashboard(ctx, testChat)
+			if err != nil || dashboard != tt.wantDashboard {
+				t.Errorf("dashboard = %d, %v, want %d", dashboard, err, tt.wantDashboard)
+			}
+		})
+	}
+}
+
+func TestResetDashboard(t *testing.T) {
+	ctx := context.Background()
+	api := startBotAPI(t)
+	tg := newTestTelegram(t, api)
+	err := tg.cache.SetDashboard(ctx, testChat, 7)
+	if err != nil {
+		t.Fatalf("SetDashboard() error = %v", err)
+	}
+	err = tg.resetDashboard(ctx, testChat)
+	if err != nil {
+		t.Fatalf("resetDashboard() error = %v", err)
+	}
+	if got := api.last("deleteMessage").params["message_id"]; got != "7" {
+		t.Errorf("deleted message %q, want 7", got)
+	}
+	api.methods()
+	err = tg.render(ctx, testChat, "Menu", nil)
+	if err != nil {
+		t.Fatalf("render() error = %v", err)
+	}
+	if got := api.methods(); !reflect.DeepEqual(got, []string{"sendMessage"}) {
+		t.Errorf("Bot API calls after a reset = %q, want a new message", got)
+	}
+}
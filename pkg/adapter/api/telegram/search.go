@@ -0,0 +1,95 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"github.com/mymmrac/telego"
+	tu "github.com/mymmrac/telego/telegoutil"
+	"html"
+	"strconv"
+	"strings"
+	"telegramBot/pkg/i18n"
+	"telegramBot/pkg/model/state/telegram"
+	"telegramBot/pkg/model/task"
+)
+
+const searchPageSize = 5
+
+func (t *Telegram) searchHandler(ctx context.Context, chatID int64, query string) error {
+	err := t.deleteMessages(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return t.menu(ctx, chatID, i18n.FromContext(ctx).T(i18n.SearchUsage))
+	}
+	err = t.cache.SetSearchQuery(ctx, chatID, query)
+	if err != nil {
+		return err
+	}
+	return t.showSearchPage(ctx, chatID, query, 0)
+}
+
+func (t *Telegram) searchPageHandler(ctx context.Context, chatID int64, page int) error {
+	query, err := t.cache.GetSearchQuery(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	if query == "" {
+		return t.menu(ctx, chatID, i18n.FromContext(ctx).T(i18n.SearchExpired))
+	}
+	return t.showSearchPage(ctx, chatID, query, page)
+}
+
+func (t *Telegram) showSearchPage(ctx context.Context, chatID int64, query string, page int) error {
+	l := i18n.FromContext(ctx)
+	text, inlineKeyboard, err := t.searchPage(ctx, chatID, query, page)
+	if err != nil {
+		return err
+	}
+	return t.render(ctx, chatID, text, withMenu(l, inlineKeyboard))
+}
+
+func (t *Telegram) searchPage(ctx context.Context, chatID int64, query string,
+	page int) (string, *telego.InlineKeyboardMarkup, error) {
+	l := i18n.FromContext(ctx)
+	result, err := t.todoBot.SearchTasks(ctx, chatID, query, searchPageSize, page*searchPageSize)
+	if err != nil {
+		return "", nil, err
+	}
+	if result.Total == 0 {
+		return l.T(i18n.SearchNothing, html.EscapeString(query)), nil, nil
+	}
+
+	first := page*searchPageSize + 1
+	var text strings.Builder
+	text.WriteString(l.N(i18n.SearchHeader, result.Total, html.EscapeString(query), first,
+		first+len(result.Matches)-1, result.Total))
+	text.WriteString("\n")
+	for i, match := range result.Matches {
+		fmt.Fprintf(&text, "\n%d. <b>%s</b>", first+i, html.EscapeString(match.Task.TaskName))
+		if match.Snippet != "" {
+			fmt.Fprintf(&text, "\n%s", renderSnippet(match.Snippet))
+		}
+	}
+
+	var row []telego.InlineKeyboardButton
+	if page > 0 {
+		row = append(row, tu.InlineKeyboardButton("◀").
+			WithCallbackData(telegram.SearchPageButton+strconv.Itoa(page-1)))
+	}
+	if first+len(result.Matches)-1 < result.Total {
+		row = append(row, tu.InlineKeyboardButton("▶").
+			WithCallbackData(telegram.SearchPageButton+strconv.Itoa(page+1)))
+	}
+	if len(row) == 0 {
+		return text.String(), nil, nil
+	}
+	return text.String(), tu.InlineKeyboard(row), nil
+}
+
+func renderSnippet(snippet string) string {
+	return strings.NewReplacer(task.HighlightStart, "<b>", task.HighlightEnd, "</b>").
+		Replace(html.EscapeString(snippet))
+}
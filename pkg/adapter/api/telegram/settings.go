@@ -0,0 +1,245 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"github.com/mymmrac/telego"
+	tu "github.com/mymmrac/telego/telegoutil"
+	"html"
+	"strconv"
+	"strings"
+	"telegramBot/pkg/i18n"
+	"telegramBot/pkg/model/profile"
+	"telegramBot/pkg/model/state/telegram"
+	"telegramBot/pkg/model/state/user"
+	"time"
+)
+
+// settingStates maps the fields edited by typing to the state that waits
+// for the value.
+var settingStates = map[string]int{
+	"timezone": user.WaitingForTimezone,
+	"list":     user.WaitingForDefaultList,
+	"quiet":    user.WaitingForQuietHours,
+	"digest":   user.WaitingForDigestTime,
+	"archive":  user.WaitingForArchiveAfter,
+}
+
+// archivePresets are the auto-archive delays offered as buttons, in days.
+var archivePresets = []int{7, 30, 90}
+
+var timezones = []string{"UTC", "Europe/London", "Europe/Berlin", "Europe/Kyiv", "America/New_York", "Asia/Tokyo"}
+
+func (t *Telegram) settingsHandler(ctx context.Context, chatID int64, notice string) error {
+	err := t.deleteMessages(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	l := i18n.FromContext(ctx)
+	p, err := t.todoBot.GetProfile(ctx, chatID)
+	if err != nil {
+		return err
+	}
+
+	var language string
+	for _, supported := range i18n.Languages {
+		if supported.Code == l.Language() {
+			language = supported.Name
+		}
+	}
+	list := l.T(i18n.SettingNoList)
+	if p.DefaultList != "" {
+		list = p.DefaultList
+	}
+	quietHours := l.T(i18n.SettingOff)
+	if p.QuietHours.Enabled() {
+		quietHours = p.QuietHours.String()
+	}
+	digest := l.T(i18n.SettingOff)
+	if p.DigestEnabled {
+		digest = profile.FormatClock(p.DigestAt)
+	}
+	archive := l.T(i18n.SettingOff)
+	if p.ArchiveAfter > 0 {
+		archive = l.N(i18n.ArchiveAfterDays, p.ArchiveAfter, p.ArchiveAfter)
+	}
+	now := time.Now()
+
+	var text strings.Builder
+	if notice != "" {
+		fmt.Fprintf(&text, "%s\n\n", html.EscapeString(notice))
+	}
+	text.WriteString(l.T(i18n.SettingsHeader) + "\n")
+	lines := []string{
+		l.T(i18n.SettingTimezone, p.Timezone, p.Now(now).Format("15:04")),
+		l.T(i18n.SettingLanguage, language),
+		l.T(i18n.SettingDateFormat, p.FormatDate(now)),
+		l.T(i18n.SettingDefaultList, list),
+		l.T(i18n.SettingQuietHours, quietHours),
+		l.T(i18n.SettingPageSize, p.PageSize),
+		l.T(i18n.SettingDigest, digest),
+		l.T(i18n.SettingArchive, archive),
+	}
+	for _, line := range lines {
+		text.WriteString("\n" + html.EscapeString(line))
+	}
+
+	inlineKeyboard := tu.InlineKeyboard(
+		tu.InlineKeyboardRow(
+			tu.InlineKeyboardButton(l.T(i18n.ButtonTimezone)).WithCallbackData(telegram.SettingButton+"timezone"),
+			tu.InlineKeyboardButton(l.T(i18n.ButtonLanguage)).WithCallbackData(telegram.LanguageState),
+		),
+		tu.InlineKeyboardRow(
+			tu.InlineKeyboardButton(l.T(i18n.ButtonDateFormat)).WithCallbackData(telegram.SettingButton+"date"),
+			tu.InlineKeyboardButton(l.T(i18n.ButtonDefaultList)).WithCallbackData(telegram.SettingButton+"list"),
+		),
+		tu.InlineKeyboardRow(
+			tu.InlineKeyboardButton(l.T(i18n.ButtonQuietHours)).WithCallbackData(telegram.SettingButton+"quiet"),
+			tu.InlineKeyboardButton(l.T(i18n.ButtonPageSize)).WithCallbackData(telegram.PageSizeState),
+		),
+		tu.InlineKeyboardRow(
+			tu.InlineKeyboardButton(l.T(i18n.ButtonDigest)).WithCallbackData(telegram.SettingButton+"digest"),
+			tu.InlineKeyboardButton(l.T(i18n.ButtonArchive)).WithCallbackData(telegram.SettingButton+"archive"),
+		),
+	)
+	return t.render(ctx, chatID, text.String(), withMenu(l, inlineKeyboard))
+}
+
+// editSettingHandler opens the editor of one profile field: a choice of
+// buttons for the date format, a prompt for a typed value otherwise.
+func (t *Telegram) editSettingHandler(ctx context.Context, chatID int64, field string) error {
+	err := t.deleteMessages(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	if field == "date" {
+		return t.dateFormatHandler(ctx, chatID)
+	}
+	state, ok := settingStates[field]
+	if !ok {
+		return t.settingsHandler(ctx, chatID, "")
+	}
+	err = t.todoBot.SetUserState(ctx, chatID, state)
+	if err != nil {
+		return err
+	}
+	return t.settingPrompt(ctx, chatID, state, "")
+}
+
+func (t *Telegram) settingPrompt(ctx context.Context, chatID int64, state int, notice string) error {
+	l := i18n.FromContext(ctx)
+	var text string
+	var rows [][]telego.InlineKeyboardButton
+	switch state {
+	case user.WaitingForTimezone:
+		text = l.T(i18n.SendTimezone)
+		var row []telego.InlineKeyboardButton
+		for _, timezone := range timezones {
+			row = append(row, tu.InlineKeyboardButton(timezone).WithCallbackData(telegram.TimezoneButton+timezone))
+			if len(row) == 2 {
+				rows = append(rows, row)
+				row = nil
+			}
+		}
+	case user.WaitingForDefaultList:
+		text = l.T(i18n.SendDefaultList)
+		rows = append(rows, tu.InlineKeyboardRow(
+			tu.InlineKeyboardButton(l.T(i18n.ButtonNoList)).WithCallbackData(telegram.NoListButton)))
+	case user.WaitingForQuietHours:
+		text = l.T(i18n.SendQuietHours)
+		rows = append(rows, tu.InlineKeyboardRow(
+			tu.InlineKeyboardButton(l.T(i18n.ButtonTurnOff)).WithCallbackData(telegram.QuietOffButton)))
+	case user.WaitingForDigestTime:
+		text = l.T(i18n.SendDigestTime)
+		rows = append(rows, tu.InlineKeyboardRow(
+			tu.InlineKeyboardButton(l.T(i18n.ButtonTurnOff)).WithCallbackData(telegram.DigestOffButton)))
+	case user.WaitingForArchiveAfter:
+		text = l.T(i18n.SendArchiveAfter)
+		var row []telego.InlineKeyboardButton
+		for _, days := range archivePresets {
+			row = append(row, tu.InlineKeyboardButton(l.N(i18n.ArchiveAfterDays, days, days)).
+				WithCallbackData(telegram.ArchiveAfterButton+strconv.Itoa(days)))
+		}
+		rows = append(rows, row, tu.InlineKeyboardRow(
+			tu.InlineKeyboardButton(l.T(i18n.ButtonTurnOff)).WithCallbackData(telegram.ArchiveAfterButton+"off")))
+	}
+	if notice != "" {
+		text = html.EscapeString(notice) + "\n\n" + text
+	}
+	rows = append(rows, cancelKeyboard(l.T(i18n.Cancel)).InlineKeyboard...)
+	return t.render(ctx, chatID, text, tu.InlineKeyboard(rows...))
+}
+
+func (t *Telegram) settingInputHandler(ctx context.Context, chatID int64, state int, in input) error {
+	if in.command == telegram.CancelLastActionState {
+		return t.cancelStateHandler(ctx, chatID)
+	}
+	if isBusyCommand(in.command) {
+		return t.finishLastActionHandler(ctx, chatID)
+	}
+	err := t.deleteMessages(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	switch state {
+	case user.WaitingForTimezone:
+		err = t.todoBot.SetTimezone(ctx, chatID, in.text)
+	case user.WaitingForDefaultList:
+		err = t.todoBot.SetDefaultList(ctx, chatID, in.text)
+	case user.WaitingForQuietHours:
+		err = t.todoBot.SetQuietHours(ctx, chatID, in.text)
+	case user.WaitingForDigestTime:
+		err = t.todoBot.SetDigest(ctx, chatID, in.text)
+	case user.WaitingForArchiveAfter:
+		err = t.todoBot.SetArchiveAfter(ctx, chatID, in.text)
+	}
+	if message, ok := userMessage(i18n.FromContext(ctx), err); ok {
+		return t.settingPrompt(ctx, chatID, state, message)
+	}
+	if err != nil {
+		return err
+	}
+	return t.settingSavedHandler(ctx, chatID)
+}
+
+// setSettingHandler applies a value picked with a button, which may also
+// answer a pending prompt.
+func (t *Telegram) setSettingHandler(ctx context.Context, chatID int64,
+	set func(ctx context.Context, userID int64) error) error {
+	err := set(ctx, chatID)
+	if message, ok := userMessage(i18n.FromContext(ctx), err); ok {
+		return t.settingsHandler(ctx, chatID, message)
+	}
+	if err != nil {
+		return err
+	}
+	return t.settingSavedHandler(ctx, chatID)
+}
+
+func (t *Telegram) settingSavedHandler(ctx context.Context, chatID int64) error {
+	err := t.todoBot.SetUserState(ctx, chatID, user.Default)
+	if err != nil {
+		return err
+	}
+	return t.settingsHandler(ctx, chatID, i18n.FromContext(ctx).T(i18n.SettingSaved))
+}
+
+func (t *Telegram) dateFormatHandler(ctx context.Context, chatID int64) error {
+	l := i18n.FromContext(ctx)
+	p, err := t.todoBot.GetProfile(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	var rows [][]telego.InlineKeyboardButton
+	for i, format := range profile.DateFormats {
+		label := p.Now(time.Now()).Format(format)
+		if format == p.DateFormat {
+			label = "• " + label + " •"
+		}
+		rows = append(rows, tu.InlineKeyboardRow(tu.InlineKeyboardButton(label).
+			WithCallbackData(telegram.DateFormatButton+strconv.Itoa(i))))
+	}
+	rows = append(rows, tu.InlineKeyboardRow(
+		tu.InlineKeyboardButton(l.T(i18n.ButtonBack)).WithCallbackData(telegram.SettingsState)))
+	return t.render(ctx, chatID, l.T(i18n.ChooseDateFormat), tu.InlineKeyboard(rows...))
+}
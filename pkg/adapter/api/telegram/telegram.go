@@ -2,452 +2,303 @@ package telegram
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/mymmrac/telego"
+	"github.com/mymmrac/telego/telegoapi"
 	tu "github.com/mymmrac/telego/telegoutil"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
-	"os"
+	"html"
+	"net/http"
+	"sync/atomic"
+	"telegramBot/internal/config"
 	"telegramBot/pkg/adapter/cache/redis"
+	"telegramBot/pkg/adapter/metrics"
 	todoBot "telegramBot/pkg/adapter/todobot"
+	"telegramBot/pkg/adapter/tracing"
+	"telegramBot/pkg/i18n"
 	"telegramBot/pkg/model/state/telegram"
 	"telegramBot/pkg/model/state/user"
+	"time"
+)
+
+const (
+	identifyBackoff    = time.Second
+	maxIdentifyBackoff = 30 * time.Second
 )
 
 type Telegram struct {
 	bot     *telego.Bot
 	todoBot *todoBot.TodoBot
 	cache   *redis.Cache
+	// username is set by identify, before any update is handled.
+	username string
+	log      *zap.Logger
+	// identified is set once GetMe has answered. Until then the bot is not
+	// ready.
+	identified atomic.Bool
+	caller     *retryCaller
+	cfg        config.Config
+	digests    chan digestRequest
 }
 
-func New(todoBot *todoBot.TodoBot, token string, cache *redis.Cache) *Telegram {
-	bot, err := telego.NewBot(token, telego.WithDefaultDebugLogger())
-	if err != nil {
-		zap.L().Error("New() -> telego.NewBot()", zap.Error(err))
-		os.Exit(1)
-	}
-	botUser, err := bot.GetMe()
+func New(todoBot *todoBot.TodoBot, cache *redis.Cache, cfg config.Config, log *zap.Logger) *Telegram {
+	caller := newRetryCaller(log)
+	bot, err := telego.NewBot(cfg.Token, telego.WithLogger(newTelegoLogger(log, cfg.Token)),
+		telego.WithAPICaller(caller))
 	if err != nil {
-		zap.L().Error("New() -> bot.GetMe()", zap.Error(err))
+		log.Fatal("New() -> telego.NewBot()", zap.Error(err))
 	}
-	fmt.Printf("Bot user: %+v\n", botUser)
 	return &Telegram{
 		bot:     bot,
 		todoBot: todoBot,
 		cache:   cache,
+		log:     log,
+		caller:  caller,
+		cfg:     cfg,
+		digests: make(chan digestRequest),
 	}
 }
 
-func (t *Telegram) Run(ctx context.Context) error {
-	updates, _ := t.bot.UpdatesViaLongPolling(nil)
-	defer t.bot.StopLongPolling()
-
-	for update := range updates {
-		var action string
-		var chatID int64
-		if update.CallbackQuery != nil {
-			action = update.CallbackQuery.Data
-			chatID = update.CallbackQuery.Message.Chat.ID
-		} else if update.Message != nil {
-			action = update.Message.Text
-			chatID = update.Message.Chat.ID
-			err := t.cache.Set(ctx, chatID, update.Message.MessageID)
-			if err != nil {
-				zap.L().Error("Run() -> t.cache.Set()", zap.Error(err))
-				continue
-			}
-		} else {
-			continue
+// identify asks Telegram who the bot is until it answers, backing off
+// between attempts. A token Telegram rejects is not retried.
+func (t *Telegram) identify(ctx context.Context) error {
+	backoff := identifyBackoff
+	for {
+		botUser, err := t.bot.GetMe()
+		if err == nil {
+			t.username = botUser.Username
+			t.identified.Store(true)
+			t.log.Info("bot started", zap.Int64("botID", botUser.ID), zap.String("username", botUser.Username))
+			return nil
 		}
-
-		button := false
-		if len(action) > 17 {
-			if action[:17] == "/buttonDeleteTask" {
-				err := t.todoBot.SetUserState(chatID, user.WaitingForTaskNameToBeDeleted)
-				if err != nil {
-					zap.L().Error("Run() -> /newTask -> t.todoBot.SetUserState()", zap.Error(err))
-					continue
-				}
-				action = action[17:]
-				button = true
-			}
+		var apiErr *telegoapi.Error
+		if errors.As(err, &apiErr) &&
+			(apiErr.ErrorCode == http.StatusUnauthorized || apiErr.ErrorCode == http.StatusNotFound) {
+			return fmt.Errorf("t.bot.GetMe(): %w", err)
 		}
-
-		userState, err := t.todoBot.GetUserState(chatID)
-		if err != nil {
-			zap.L().Error("Run() -> t.todoBot.GetUserState()", zap.Error(err))
-			continue
+		t.log.Warn("identify() -> t.bot.GetMe()", zap.Duration("retryIn", backoff), zap.Error(err))
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
 		}
+		backoff *= 2
+		if backoff > maxIdentifyBackoff {
+			backoff = maxIdentifyBackoff
+		}
+	}
+}
 
-		switch userState {
-		case user.Default:
-			switch action {
-			case telegram.StartState:
-				err := t.startHandler(ctx, chatID, update.Message.Chat.FirstName)
-				if err != nil {
-					zap.L().Error("Run() -> t.startHandler()", zap.Error(err))
-					continue
-				}
-			case telegram.NewTaskState:
-				err := t.newTaskHandler(ctx, chatID)
-				if err != nil {
-					zap.L().Error("Run() -> t.newTaskHandler()", zap.Error(err))
-					continue
-				}
-			case telegram.DeleteTaskState:
-				err := t.deleteTaskHandler(ctx, chatID)
-				if err != nil {
-					zap.L().Error("Run() -> t.deleteTaskHandler()", zap.Error(err))
-					continue
-				}
-			case telegram.ListOfTasksState:
-				err = t.listOfTasksHandler(ctx, chatID)
-				if err != nil {
-					zap.L().Error("Run() -> t.listOfTasksHandler()", zap.Error(err))
-					continue
-				}
-			case telegram.CancelLastActionState:
-				err = t.cancelLastActionHandler(ctx, chatID)
-				if err != nil {
-					zap.L().Error("Run() -> t.cancelLastAction()", zap.Error(err))
-					continue
-				}
-			default:
-				err = t.defaultHandler(ctx, chatID)
-				if err != nil {
-					zap.L().Error("Run() -> t.defaultHandler()", zap.Error(err))
-					continue
-				}
-			}
-		case user.WaitingForTaskNameToBeDeleted:
-			if action == telegram.NewTaskState ||
-				action == telegram.ListOfTasksState || action == telegram.DeleteTaskState || action == telegram.StartState {
-				messageInfo, err := t.bot.SendMessage(tu.Message(tu.ID(chatID),
-					"Finish your last action or /cancelLastAction"))
-				if err != nil {
-					zap.L().Error("Run() -> t.bot.SendMessage()", zap.Error(err))
-					continue
-				}
-				err = t.cache.Set(ctx, chatID, messageInfo.MessageID)
-				if err != nil {
-					zap.L().Error("Run() -> t.cache.Set()", zap.Error(err))
-				}
-				continue
-			} else if action == telegram.CancelLastActionState {
-				err = t.todoBot.SetUserState(chatID, user.Default)
-				if err != nil {
-					zap.L().Error("Run() -> t.todoBot.SetUserState()", zap.Error(err))
-					continue
-				}
-				err = t.deleteMessages(ctx, chatID)
-				if err != nil {
-					zap.L().Error("Run() -> t.deleteMessages()", zap.Error(err))
-					continue
-				}
-
-				messageInfo, err := t.bot.SendMessage(tu.Message(tu.ID(chatID), "Last action canceled"))
-				if err != nil {
-					zap.L().Error("Run() -> t.todoBot.SetUserState()", zap.Error(err))
-					continue
-				}
-				err = t.cache.Set(ctx, chatID, messageInfo.MessageID)
-				if err != nil {
-					zap.L().Error("Run() -> t.cache.Set()", zap.Error(err))
-					continue
-				}
-				continue
-			}
-			err = t.deleteMessages(ctx, chatID)
-			if err != nil {
-				zap.L().Error("Run() -> t.deleteMessages()", zap.Error(err))
-				continue
-			}
-
-			message, err := t.todoBot.DeleteTask(action)
-			if err != nil {
-				zap.L().Error("Run() -> t.todoBot.DeleteTask()", zap.Error(err))
-				continue
-			}
-			if button {
-				err = t.getListOfTasks(ctx, chatID)
-				if err != nil {
-					zap.L().Error("Run() -> t.getListOfTasks()", zap.Error(err))
-					continue
-				}
-			}
-
-			messageInfo, err := t.bot.SendMessage(
-				tu.Message(tu.ID(chatID), message))
-			if err != nil {
-				zap.L().Error("Run() -> t.bot.SendMessage()", zap.Error(err))
-				continue
-			}
-			err = t.cache.Set(ctx, chatID, messageInfo.MessageID)
-			if err != nil {
-				zap.L().Error("Run() -> t.cache.Set()", zap.Error(err))
-				continue
-			}
-
-			err = t.todoBot.SetUserState(chatID, user.Default)
-			if err != nil {
-				zap.L().Error("Run() -> t.todoBot.SetUserState()", zap.Error(err))
-				continue
-			}
-			err = t.menu(ctx, chatID)
-			if err != nil {
-				zap.L().Error("Run() -> t.menu()", zap.Error(err))
-				continue
-			}
-		case user.WaitingForNewTaskName:
-			if action == telegram.CancelLastActionState {
-				err = t.todoBot.SetUserState(chatID, user.Default)
-				if err != nil {
-					zap.L().Error("Run() -> t.todoBot.SetUserState()", zap.Error(err))
-					continue
-				}
-				err = t.todoBot.DeleteNotFinishedTask(chatID)
-				if err != nil {
-					zap.L().Error("Run() -> t.todoBot.DeleteNotFinishedTask()", zap.Error(err))
-					continue
-				}
-				err = t.deleteMessages(ctx, chatID)
-				if err != nil {
-					zap.L().Error("Run() -> t.deleteMessages()", zap.Error(err))
-					continue
-				}
+// Run handles updates until ctx is done. Commands addressed to other bots
+// are told apart by the username, so the bot identifies itself first.
+func (t *Telegram) Run(ctx context.Context) error {
+	err := t.identify(ctx)
+	if ctx.Err() != nil {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("t.identify(): %w", err)
+	}
+	err = t.registerCommands()
+	if err != nil {
+		t.log.Error("Run() -> t.registerCommands()", zap.Error(err))
+	}
 
-				messageInfo, err := t.bot.SendMessage(tu.Message(tu.ID(chatID), "Last action canceled"))
-				if err != nil {
-					zap.L().Error("Run() -> t.todoBot.SetUserState()", zap.Error(err))
-					continue
-				}
-				err = t.cache.Set(ctx, chatID, messageInfo.MessageID)
-				if err != nil {
-					zap.L().Error("Run() -> t.cache.Set()", zap.Error(err))
-					continue
-				}
-				err = t.menu(ctx, chatID)
-				if err != nil {
-					zap.L().Error("Run() -> t.menu()", zap.Error(err))
-					continue
-				}
-				continue
-			}
-			if action == telegram.NewTaskState ||
-				action == "/allButtons" || action == telegram.ListOfTasksState ||
-				action == telegram.DeleteTaskState || action == telegram.StartState {
-				messageInfo, err := t.bot.SendMessage(tu.Message(tu.ID(chatID), "Finish your last action or /cancelLastAction"))
-				if err != nil {
-					zap.L().Error("Run() -> t.bot.SendMessage()", zap.Error(err))
-				}
-				err = t.cache.Set(ctx, chatID, messageInfo.MessageID)
-				if err != nil {
-					zap.L().Error("Run() -> t.cache.Set()", zap.Error(err))
-					continue
-				}
-				continue
-			}
-			taskID, err := t.todoBot.GetTaskIDInCreationStatus(chatID)
-			err = t.todoBot.SetTaskName(taskID, action)
-			if err != nil {
-				zap.L().Error("Run() -> t.todoBot.SetTaskName()", zap.Error(err))
-				continue
-			}
+	updates, err := t.bot.UpdatesViaLongPolling(&telego.GetUpdatesParams{Timeout: int(t.cfg.PollTimeout.Seconds())},
+		telego.WithLongPollingRetryTimeout(t.cfg.PollRetryTimeout))
+	if err != nil {
+		return fmt.Errorf("t.bot.UpdatesViaLongPolling(): %w", err)
+	}
+	defer t.bot.StopLongPolling()
 
-			err = t.todoBot.SetUserState(chatID, user.WaitingForNewTaskDescription)
-			if err != nil {
-				zap.L().Error("Run() -> t.todoBot.SetUserState()", zap.Error(err))
-				continue
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case update, ok := <-updates:
+			if !ok {
+				return nil
 			}
-
-			inlineKeyboard := tu.InlineKeyboard(
-				tu.InlineKeyboardRow(
-					tu.InlineKeyboardButton("Cancel task creation").
-						WithCallbackData(telegram.CancelLastActionState),
-				),
-			)
-			message := tu.Message(
-				tu.ID(chatID),
-				"Send task description",
-			).WithReplyMarkup(inlineKeyboard)
-
-			messageInfo, _ := t.bot.SendMessage(message)
+			err := t.handleUpdate(ctx, update)
 			if err != nil {
-				zap.L().Error("Run() -> t.bot.SendMessage()", zap.Error(err))
-				continue
+				t.log.Error("Run() -> t.handleUpdate()", zap.Int("updateID", update.UpdateID), zap.Error(err))
 			}
-			err = t.cache.Set(ctx, chatID, messageInfo.MessageID)
+		case request := <-t.digests:
+			err := t.handleDigest(ctx, request.digest)
 			if err != nil {
-				zap.L().Error("Run() -> t.cache.Set()", zap.Error(err))
-				continue
+				t.log.Error("Run() -> t.handleDigest()", zap.Int64("chatID", request.digest.Profile.UserID),
+					zap.Error(err))
 			}
-		case user.WaitingForNewTaskDescription:
-			if action == telegram.CancelLastActionState {
-				err = t.todoBot.SetUserState(chatID, user.Default)
-				if err != nil {
-					zap.L().Error("Run() -> t.todoBot.SetUserState()", zap.Error(err))
-					continue
-				}
+			request.done <- err
+		}
+	}
+}
 
-				err = t.todoBot.DeleteNotFinishedTask(chatID)
-				if err != nil {
-					zap.L().Error("Run() -> t.todoBot.DeleteNotFinishedTask()", zap.Error(err))
-					continue
-				}
-				err = t.deleteMessages(ctx, chatID)
-				if err != nil {
-					zap.L().Error("Run() -> t.deleteMessages()", zap.Error(err))
-					continue
-				}
+func (t *Telegram) handleUpdate(ctx context.Context, update telego.Update) (err error) {
+	var action string
+	var chatID int64
+	var from *telego.User
+	if update.CallbackQuery != nil {
+		action = update.CallbackQuery.Data
+		chatID = update.CallbackQuery.Message.Chat.ID
+		from = &update.CallbackQuery.From
+	} else if update.Message != nil {
+		action = update.Message.Text
+		chatID = update.Message.Chat.ID
+		from = update.Message.From
+	} else {
+		return nil
+	}
 
-				messageInfo, err := t.bot.SendMessage(tu.Message(tu.ID(chatID), "Last action canceled"))
-				if err != nil {
-					zap.L().Error("Run() -> t.todoBot.SetUserState()", zap.Error(err))
-					continue
-				}
-				err = t.cache.Set(ctx, chatID, messageInfo.MessageID)
-				if err != nil {
-					zap.L().Error("Run() -> t.cache.Set()", zap.Error(err))
-					continue
-				}
-				err = t.menu(ctx, chatID)
-				if err != nil {
-					zap.L().Error("Run() -> t.menu()", zap.Error(err))
-					continue
-				}
-				continue
-			}
-			if action == telegram.NewTaskState || action == telegram.ListOfTasksState ||
-				action == telegram.DeleteTaskState || action == telegram.StartState {
-				messageInfo, err := t.bot.SendMessage(
-					tu.Message(
-						tu.ID(chatID),
-						"Finish your last action or /cancelLastAction",
-					),
-				)
-				if err != nil {
-					zap.L().Error("Run() -> t.bot.SendMessage()", zap.Error(err))
-				}
-				err = t.cache.Set(ctx, chatID, messageInfo.MessageID)
-				if err != nil {
-					zap.L().Error("Run() -> t.cache.Set()", zap.Error(err))
-					continue
-				}
-				continue
-			}
-			err = t.deleteMessages(ctx, chatID)
-			if err != nil {
-				zap.L().Error("Run() -> t.deleteMessages()", zap.Error(err))
-				continue
-			}
-			taskID, err := t.todoBot.GetTaskIDInCreationStatus(chatID)
-			if err != nil {
-				zap.L().Error("Run() -> t.todoBot.GetTaskIDInCreationStatus()", zap.Error(err))
-				continue
-			}
+	var firstName, languageCode string
+	if from != nil {
+		firstName = from.FirstName
+		languageCode = from.LanguageCode
+	}
+	ctx = withCorrelationID(ctx, newCorrelationID())
+	ctx, span := tracing.Start(ctx, "update", trace.WithNewRoot(), trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attribute.Int("telegram.update_id", update.UpdateID),
+			attribute.Int64("telegram.chat_id", chatID), attribute.String("correlation_id", correlationID(ctx))))
+	// failure is what the handlers returned, even when handleError answered
+	// the user and the update counts as handled.
+	var failure error
+	defer func() { tracing.End(span, errors.Join(failure, err)) }()
+	t.caller.update.Store(&ctx)
+	defer t.caller.update.Store(nil)
 
-			err = t.todoBot.SetTaskDescription(taskID, action)
-			if err != nil {
-				zap.L().Error("Run() -> t.todoBot.SetTaskDescription()", zap.Error(err))
-				continue
-			}
+	log := t.log.With(zap.Int("updateID", update.UpdateID), zap.Int64("chatID", chatID),
+		zap.String("action", action), zap.String("correlationID", correlationID(ctx)))
+	if traceID := tracing.TraceID(ctx); traceID != "" {
+		log = log.With(zap.String("traceID", traceID))
+	}
+	ctx = withLogger(ctx, log)
+	log.Debug("update received")
 
-			err = t.todoBot.SetUserState(chatID, user.Default)
-			if err != nil {
-				zap.L().Error("Run() -> t.todoBot.SetUserState()", zap.Error(err))
-				continue
-			}
+	language, err := t.todoBot.GetUserLanguage(ctx, chatID, languageCode)
+	if err != nil {
+		log.Warn("handleUpdate() -> t.todoBot.GetUserLanguage()", zap.Error(err))
+		language = i18n.Match(languageCode)
+	}
+	ctx = i18n.WithLanguage(ctx, language)
 
-			messageInfo, err := t.bot.SendMessage(
-				tu.Message(
-					tu.ID(chatID),
-					"Task created",
-				),
-			)
-			if err != nil {
-				zap.L().Error("Run() -> t.bot.SendMessage()", zap.Error(err))
-				continue
-			}
-			err = t.cache.Set(ctx, chatID, messageInfo.MessageID)
-			if err != nil {
-				zap.L().Error("Run() -> t.cache.Set()", zap.Error(err))
-				continue
-			}
-			err = t.menu(ctx, chatID)
-			if err != nil {
-				zap.L().Error("Run() -> t.menu()", zap.Error(err))
-				continue
-			}
+	if update.CallbackQuery != nil {
+		err := t.bot.AnswerCallbackQuery(tu.CallbackQuery(update.CallbackQuery.ID))
+		if err != nil {
+			log.Warn("handleUpdate() -> t.bot.AnswerCallbackQuery()", zap.Error(err))
+		}
+	} else {
+		err := t.cache.Set(ctx, chatID, update.Message.MessageID)
+		if err != nil {
+			log.Warn("handleUpdate() -> t.cache.Set()", zap.Error(err))
 		}
 	}
-	return nil
-}
 
-func (t *Telegram) deleteMessages(ctx context.Context, chatID int64) error {
-	messageIDs, err := t.cache.Get(ctx, chatID)
+	retry := retryAction{Action: action, Message: update.Message}
+	userState, err := t.todoBot.GetUserState(ctx, chatID)
 	if err != nil {
-		return err
+		failure = fmt.Errorf("t.todoBot.GetUserState(): %w", err)
+		return t.handleError(ctx, chatID, user.Default, retry, failure)
 	}
-	for _, v := range messageIDs {
-		err = t.bot.DeleteMessage(&telego.DeleteMessageParams{ChatID: tu.ID(chatID), MessageID: v})
+	ctx = withLogger(ctx, log.With(zap.Int("state", userState)))
+	if action == telegram.RetryButton {
+		retry, err = t.getRetryAction(ctx, chatID)
 		if err != nil {
-			return err
+			failure = fmt.Errorf("t.getRetryAction(): %w", err)
+			return t.handleError(ctx, chatID, userState, retryAction{Action: telegram.RetryButton}, failure)
+		}
+		if retry.Action == "" && retry.Message == nil {
+			return t.menu(ctx, chatID, "")
 		}
+		action = retry.Action
+		ctx = withLogger(ctx, t.logger(ctx).With(zap.String("retriedAction", action)))
+	}
+	command := t.commandLabel(action)
+	span.SetAttributes(attribute.String("todobot.action", command),
+		attribute.String("todobot.state", stateLabel(userState)))
+	metrics.Updates.WithLabelValues(command, stateLabel(userState)).Inc()
+	start := time.Now()
+	err = t.dispatch(ctx, chatID, userState, action, firstName, retry.Message)
+	metrics.HandlerDuration.WithLabelValues(command).Observe(time.Since(start).Seconds())
+	if err != nil {
+		failure = err
+		metrics.UpdateErrors.WithLabelValues(command).Inc()
+		return t.handleError(ctx, chatID, userState, retry, err)
 	}
 	return nil
 }
 
-func (t *Telegram) getListOfTasks(ctx context.Context, chatID int64) error {
-	tasks, err := t.todoBot.GetListOfTasks(chatID)
+func (t *Telegram) dispatch(ctx context.Context, chatID int64, userState int, action, firstName string,
+	message *telego.Message) error {
+	handled, err := t.buttonHandler(ctx, chatID, action)
 	if err != nil {
-		return err
+		return fmt.Errorf("t.buttonHandler(): %w", err)
+	}
+	if handled {
+		return nil
 	}
-	for _, task := range tasks {
-		inlineKeyboard := tu.InlineKeyboard(
-			tu.InlineKeyboardRow(
-				tu.InlineKeyboardButton("Delete this task").
-					WithCallbackData("/buttonDeleteTask" + task.TaskName),
-			),
-		)
-		message := tu.Messagef(
-			tu.ID(chatID),
-			`Task name:             %s
-Task description:   %s`, task.TaskName, task.TaskDescription,
-		).WithReplyMarkup(inlineKeyboard)
 
-		messageInfo, _ := t.bot.SendMessage(message)
-		if err != nil {
-			return err
-		}
-		err = t.cache.Set(ctx, chatID, messageInfo.MessageID)
-		if err != nil {
-			return err
-		}
+	in := t.parseInput(action)
+	if in.foreign {
+		return nil
+	}
+	in.message = message
+	switch userState {
+	case user.Default:
+		return t.defaultStateHandler(ctx, chatID, in, firstName)
+	case user.WaitingForTaskNameToBeDeleted:
+		return t.taskNameToBeDeletedHandler(ctx, chatID, in)
+	case user.WaitingForNewTaskName:
+		return t.newTaskNameHandler(ctx, chatID, in)
+	case user.WaitingForNewTaskDescription:
+		return t.newTaskDescriptionHandler(ctx, chatID, in)
+	case user.WaitingForTimezone, user.WaitingForDefaultList, user.WaitingForQuietHours, user.WaitingForDigestTime,
+		user.WaitingForArchiveAfter:
+		return t.settingInputHandler(ctx, chatID, userState, in)
+	case user.WaitingForImportFile:
+		return t.importFileHandler(ctx, chatID, in)
+	case user.WaitingForBulkTasks:
+		return t.bulkTasksHandler(ctx, chatID, in)
+	case user.WaitingForComment:
+		return t.commentInputHandler(ctx, chatID, in)
 	}
 	return nil
 }
 
-func (t *Telegram) menu(ctx context.Context, chatID int64) error {
-	keyboard := tu.Keyboard(
-		tu.KeyboardRow(
-			tu.KeyboardButton(telegram.NewTaskState),
-			tu.KeyboardButton(telegram.ListOfTasksState),
+func menuRows(l i18n.Localizer) [][]telego.InlineKeyboardButton {
+	return [][]telego.InlineKeyboardButton{
+		tu.InlineKeyboardRow(
+			tu.InlineKeyboardButton(l.T(i18n.ButtonNewTask)).WithCallbackData(telegram.NewTaskState),
+			tu.InlineKeyboardButton(l.T(i18n.ButtonListOfTasks)).WithCallbackData(telegram.ListOfTasksState),
 		),
-		tu.KeyboardRow(
-			tu.KeyboardButton(telegram.DeleteTaskState),
+		tu.InlineKeyboardRow(
+			tu.InlineKeyboardButton(l.T(i18n.ButtonDeleteTask)).WithCallbackData(telegram.DeleteTaskState),
 		),
-	).WithResizeKeyboard().WithInputFieldPlaceholder("Select something").
-		WithOneTimeKeyboard()
-	message := tu.Message(
-		tu.ID(chatID),
-		"Menu: ",
-	).WithReplyMarkup(keyboard)
-	messageInfo, err := t.bot.SendMessage(message)
-	if err != nil {
-		return err
 	}
-	err = t.cache.Set(ctx, chatID, messageInfo.MessageID)
-	if err != nil {
-		return err
+}
+
+func withMenu(l i18n.Localizer, inlineKeyboard *telego.InlineKeyboardMarkup) *telego.InlineKeyboardMarkup {
+	if inlineKeyboard == nil {
+		return tu.InlineKeyboard(menuRows(l)...)
 	}
-	return nil
+	return tu.InlineKeyboard(append(inlineKeyboard.InlineKeyboard, menuRows(l)...)...)
+}
+
+func cancelKeyboard(label string) *telego.InlineKeyboardMarkup {
+	return tu.InlineKeyboard(
+		tu.InlineKeyboardRow(
+			tu.InlineKeyboardButton(label).WithCallbackData(telegram.CancelLastActionState),
+		),
+	)
+}
+
+func (t *Telegram) menu(ctx context.Context, chatID int64, notice string) error {
+	l := i18n.FromContext(ctx)
+	text := l.T(i18n.Menu)
+	if notice != "" {
+		text = html.EscapeString(notice) + "\n\n" + text
+	}
+	return t.render(ctx, chatID, text, withMenu(l, nil))
 }
@@ -0,0 +1,455 @@
+package telegram
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/mymmrac/telego"
+	"go.uber.org/zap"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"telegramBot/internal/config"
+	"telegramBot/pkg/adapter/cache/redis"
+	"telegramBot/pkg/adapter/storage/sqlite"
+	todoBot "telegramBot/pkg/adapter/todobot"
+	"testing"
+	"time"
+)
+
+const (
+	testToken = "1234567890:AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"
+	testChat  = 42
+)
+
+// fakeRedis serves the Redis commands the cache uses from memory, so the
+// handlers run without a Redis server. Expiry is ignored.
+type fakeRedis struct {
+	mu     sync.Mutex
+	values map[string]string
+	lists  map[string][]string
+}
+
+func startRedis(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	r := &fakeRedis{values: map[string]string{}, lists: map[string][]string{}}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go r.serve(conn)
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func (r *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+		_, err = io.WriteString(conn, r.do(args))
+		if err != nil {
+			return
+		}
+	}
+}
+
+// readCommand reads a command the way clients send them: an array of bulk
+// strings.
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	header, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(header, "*")))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, count)
+	for i := range args {
+		header, err = reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(header, "$")))
+		if err != nil {
+			return nil, err
+		}
+		arg := make([]byte, size+2)
+		_, err = io.ReadFull(reader, arg)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = string(arg[:size])
+	}
+	return args, nil
+}
+
+func bulkString(s string) string {
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(s), s)
+}
+
+func (r *fakeRedis) do(args []string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return "+PONG\r\n"
+	case "SET":
+		r.values[args[1]] = args[2]
+		delete(r.lists, args[1])
+		return "+OK\r\n"
+	case "GET", "GETDEL":
+		value, ok := r.values[args[1]]
+		if !ok {
+			return "$-1\r\n"
+		}
+		if strings.ToUpper(args[0]) == "GETDEL" {
+			delete(r.values, args[1])
+		}
+		return bulkString(value)
+	case "DEL", "EXISTS":
+		var count int
+		for _, key := range args[1:] {
+			_, value := r.values[key]
+			_, list := r.lists[key]
+			if value || list {
+				count++
+			}
+			if strings.ToUpper(args[0]) == "DEL" {
+				delete(r.values, key)
+				delete(r.lists, key)
+			}
+		}
+		return fmt.Sprintf(":%d\r\n", count)
+	case "RPUSH":
+		r.lists[args[1]] = append(r.lists[args[1]], args[2:]...)
+		return fmt.Sprintf(":%d\r\n", len(r.lists[args[1]]))
+	case "LRANGE":
+		list := r.lists[args[1]]
+		start, _ := strconv.Atoi(args[2])
+		stop, _ := strconv.Atoi(args[3])
+		if stop < 0 {
+			stop += len(list)
+		}
+		if stop >= len(list) {
+			stop = len(list) - 1
+		}
+		reply := "*0\r\n"
+		if start <= stop {
+			reply = fmt.Sprintf("*%d\r\n", stop-start+1)
+			for _, value := range list[start : stop+1] {
+				reply += bulkString(value)
+			}
+		}
+		return reply
+	}
+	// HELLO among others: the client falls back to RESP2.
+	return "-ERR unknown command '" + args[0] + "'\r\n"
+}
+
+type apiCall struct {
+	method string
+	params map[string]string
+}
+
+// fakeBotAPI answers Bot API requests with plausible results and records
+// them. Messages get increasing IDs from 100.
+type fakeBotAPI struct {
+	*httptest.Server
+	mu            sync.Mutex
+	calls         []apiCall
+	replies       map[string][]string
+	files         map[string]string
+	nextMessageID int
+}
+
+func startBotAPI(t *testing.T) *fakeBotAPI {
+	t.Helper()
+	api := &fakeBotAPI{replies: map[string][]string{}, files: map[string]string{}, nextMessageID: 100}
+	api.Server = httptest.NewServer(http.HandlerFunc(api.serve))
+	t.Cleanup(api.Close)
+	return api
+}
+
+// reply makes the next calls of method get the given Bot API responses, one
+// per call, before the usual result.
+func (a *fakeBotAPI) reply(method string, responses ...string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.replies[method] = append(a.replies[method], responses...)
+}
+
+// file serves data as a file the user sent, with fileID.
+func (a *fakeBotAPI) file(fileID, data string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.files[fileID] = data
+}
+
+func (a *fakeBotAPI) serve(w http.ResponseWriter, r *http.Request) {
+	if fileID, ok := strings.CutPrefix(r.URL.Path, "/file/bot"+testToken+"/"); ok {
+		a.mu.Lock()
+		data, ok := a.files[fileID]
+		a.mu.Unlock()
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = io.WriteString(w, data)
+		return
+	}
+	call := apiCall{method: path.Base(r.URL.Path), params: readParams(r)}
+	a.mu.Lock()
+	a.calls = append(a.calls, call)
+	var response string
+	if replies := a.replies[call.method]; len(replies) > 0 {
+		response, a.replies[call.method] = replies[0], replies[1:]
+	} else {
+		response = a.result(call)
+	}
+	a.mu.Unlock()
+
+	var reply struct {
+		ErrorCode int `json:"error_code"`
+	}
+	_ = json.Unmarshal([]byte(response), &reply)
+	if reply.ErrorCode != 0 {
+		w.WriteHeader(reply.ErrorCode)
+	}
+	_, _ = io.WriteString(w, response)
+}
+
+func (a *fakeBotAPI) result(call apiCall) string {
+	var result any = true
+	switch call.method {
+	case "getMe":
+		result = telego.User{ID: 1, IsBot: true, FirstName: "Todo", Username: "todo_bot"}
+	case "getFile":
+		result = telego.File{FileID: call.params["file_id"], FilePath: call.params["file_id"]}
+	case "getUpdates":
+		result = []telego.Update{}
+	case "sendMessage", "sendDocument", "sendPhoto", "editMessageText", "editMessageReplyMarkup":
+		chatID, _ := strconv.ParseInt(call.params["chat_id"], 10, 64)
+		messageID, _ := strconv.Atoi(call.params["message_id"])
+		if messageID == 0 {
+			messageID = a.nextMessageID
+			a.nextMessageID++
+		}
+		result = telego.Message{MessageID: messageID, Chat: telego.Chat{ID: chatID, Type: telego.ChatTypePrivate},
+			Text: call.params["text"]}
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		panic(err)
+	}
+	return `{"ok":true,"result":` + string(data) + `}`
+}
+
+// readParams flattens JSON and multipart parameters to strings; nested
+// values such as keyboards stay JSON.
+func readParams(r *http.Request) map[string]string {
+	params := map[string]string{}
+	mediaType, mediaParams, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		form, err := multipart.NewReader(r.Body, mediaParams["boundary"]).ReadForm(1 << 20)
+		if err == nil {
+			for name, values := range form.Value {
+				params[name] = values[0]
+			}
+		}
+		return params
+	}
+	var raw map[string]json.RawMessage
+	_ = json.NewDecoder(r.Body).Decode(&raw)
+	for name, value := range raw {
+		var s string
+		if json.Unmarshal(value, &s) == nil {
+			params[name] = s
+		} else {
+			params[name] = string(value)
+		}
+	}
+	return params
+}
+
+// methods returns the methods called so far, but answerCallbackQuery, and
+// forgets them.
+func (a *fakeBotAPI) methods() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var methods []string
+	for _, call := range a.calls {
+		if call.method != "answerCallbackQuery" {
+			methods = append(methods, call.method)
+		}
+	}
+	a.calls = nil
+	return methods
+}
+
+// last returns the last call of method.
+func (a *fakeBotAPI) last(method string) apiCall {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for i := len(a.calls) - 1; i >= 0; i-- {
+		if a.calls[i].method == method {
+			return a.calls[i]
+		}
+	}
+	return apiCall{}
+}
+
+func apiError(code int, description string) string {
+	return fmt.Sprintf(`{"ok":false,"error_code":%d,"description":%q}`, code, description)
+}
+
+// newTestTelegram wires a Telegram to api, a fresh SQLite store and an
+// in-memory Redis, the way New does but without calling the Bot API.
+func newTestTelegram(t *testing.T, api *fakeBotAPI) *Telegram {
+	t.Helper()
+	log := zap.NewNop()
+	caller := newRetryCaller(log)
+	bot, err := telego.NewBot(testToken, telego.WithAPIServer(api.URL), telego.WithAPICaller(caller),
+		telego.WithDiscardLogger())
+	if err != nil {
+		t.Fatalf("telego.NewBot() error = %v", err)
+	}
+	cfg := config.Config{DatabasePath: filepath.Join(t.TempDir(), "tasks.db"), AddrRedis: startRedis(t),
+		PollTimeout: time.Second}
+	cache := redis.New(cfg, log)
+	tg := &Telegram{
+		bot:      bot,
+		todoBot:  todoBot.New(sqlite.New(cfg, log), cache, log),
+		cache:    cache,
+		username: "todo_bot",
+		log:      log,
+		caller:   caller,
+		cfg:      cfg,
+		digests:  make(chan digestRequest),
+	}
+	tg.identified.Store(true)
+	return tg
+}
+
+func textUpdate(text string) telego.Update {
+	return telego.Update{UpdateID: 1, Message: &telego.Message{MessageID: 10, Text: text,
+		Chat: telego.Chat{ID: testChat, Type: telego.ChatTypePrivate}, From: &telego.User{ID: testChat}}}
+}
+
+func buttonUpdate(data string) telego.Update {
+	return telego.Update{UpdateID: 1, CallbackQuery: &telego.CallbackQuery{ID: "1", Data: data,
+		From:    telego.User{ID: testChat},
+		Message: &telego.Message{MessageID: 100, Chat: telego.Chat{ID: testChat, Type: telego.ChatTypePrivate}}}}
+}
+
+func TestIdentify(t *testing.T) {
+	tests := []struct {
+		name    string
+		replies []string
+		calls   int
+		ok      bool
+	}{
+		{"first try", nil, 1, true},
+		{"after a failure", []string{apiError(400, "Bad Request: something odd")}, 2, true},
+		{"wrong token", []string{apiError(401, "Unauthorized")}, 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := startBotAPI(t)
+			tg := newTestTelegram(t, api)
+			tg.username = ""
+			tg.identified.Store(false)
+			api.reply("getMe", tt.replies...)
+
+			err := tg.identify(context.Background())
+			if (err == nil) != tt.ok {
+				t.Fatalf("identify() error = %v, want ok %t", err, tt.ok)
+			}
+			if got := len(api.methods()); got != tt.calls {
+				t.Errorf("getMe called %d times, want %d", got, tt.calls)
+			}
+			if tg.identified.Load() != tt.ok {
+				t.Errorf("identified = %t, want %t", tg.identified.Load(), tt.ok)
+			}
+			// Commands addressed to the bot by name are its own only once it
+			// knows the name.
+			if foreign := tg.parseInput("/list@todo_bot").foreign; foreign == tt.ok {
+				t.Errorf("/list@todo_bot foreign = %t, want %t", foreign, !tt.ok)
+			}
+		})
+	}
+}
+
+func TestIdentifyStopsWithContext(t *testing.T) {
+	api := startBotAPI(t)
+	tg := newTestTelegram(t, api)
+	tg.identified.Store(false)
+	api.reply("getMe", apiError(400, "Bad Request: something odd"))
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err := tg.identify(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("identify() error = %v, want the context's", err)
+	}
+}
+
+func TestHealth(t *testing.T) {
+	ctx := context.Background()
+	api := startBotAPI(t)
+	tg := newTestTelegram(t, api)
+	tg.identified.Store(false)
+	tg.cfg.PollStaleAfter = time.Minute
+
+	if err := tg.Alive(ctx); err == nil {
+		t.Error("Alive() before any poll = nil, want an error")
+	}
+	api.reply("getUpdates", apiError(401, "Unauthorized"))
+	_, _ = tg.bot.GetUpdates(&telego.GetUpdatesParams{})
+	if err := tg.Alive(ctx); err == nil {
+		t.Error("Alive() after a failed poll = nil, want an error")
+	}
+	_, err := tg.bot.GetUpdates(&telego.GetUpdatesParams{})
+	if err != nil {
+		t.Fatalf("GetUpdates() error = %v", err)
+	}
+	if err := tg.Alive(ctx); err != nil {
+		t.Errorf("Alive() after a poll = %v, want nil", err)
+	}
+	if err := tg.Ready(ctx); err == nil {
+		t.Error("Ready() before getMe = nil, want an error")
+	}
+	err = tg.identify(ctx)
+	if err != nil {
+		t.Fatalf("identify() error = %v", err)
+	}
+	if err := tg.Ready(ctx); err != nil {
+		t.Errorf("Ready() = %v, want nil", err)
+	}
+
+	tg.caller.polled.Store(time.Now().Add(-2 * time.Minute).Unix())
+	if err := tg.Alive(ctx); err == nil {
+		t.Error("Alive() after a stale poll = nil, want an error")
+	}
+}
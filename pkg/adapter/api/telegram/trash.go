@@ -0,0 +1,147 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"github.com/mymmrac/telego"
+	tu "github.com/mymmrac/telego/telegoutil"
+	"html"
+	"strconv"
+	"strings"
+	"telegramBot/pkg/i18n"
+	"telegramBot/pkg/model/state/telegram"
+	"time"
+)
+
+// trashShown caps how many deleted tasks the trash screen lists, newest
+// first, so its keyboard stays usable.
+const trashShown = 10
+
+// undoRow is the Undo button of a deletion notice. number is the list page
+// to return to, or -1 for the menu.
+func undoRow(l i18n.Localizer, taskID int64, number int) []telego.InlineKeyboardButton {
+	return tu.InlineKeyboardRow(tu.InlineKeyboardButton(l.T(i18n.ButtonUndo)).
+		WithCallbackData(fmt.Sprintf("%s%d:%d", telegram.UndoDeleteButton, taskID, number)))
+}
+
+// taskDeletedHandler shows the menu with the deletion notice and its Undo
+// button.
+func (t *Telegram) taskDeletedHandler(ctx context.Context, chatID, taskID int64) error {
+	l := i18n.FromContext(ctx)
+	text := html.EscapeString(l.T(i18n.TaskDeleted)) + "\n\n" + l.T(i18n.Menu)
+	return t.render(ctx, chatID, text, withMenu(l, tu.InlineKeyboard(undoRow(l, taskID, -1))))
+}
+
+func (t *Telegram) undoDeleteHandler(ctx context.Context, chatID, taskID int64, number int) error {
+	l := i18n.FromContext(ctx)
+	notice := l.T(i18n.TaskRestored)
+	err := t.todoBot.UndoDelete(ctx, chatID, taskID)
+	if message, ok := userMessage(l, err); ok {
+		notice = message
+	} else if err != nil {
+		return err
+	}
+	if number < 0 {
+		return t.menu(ctx, chatID, notice)
+	}
+	return t.showListPage(ctx, chatID, number, notice)
+}
+
+// trashHandler lists the deleted tasks with buttons to restore or purge
+// each of them.
+func (t *Telegram) trashHandler(ctx context.Context, chatID int64, notice string) error {
+	err := t.deleteMessages(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	l := i18n.FromContext(ctx)
+	tasks, err := t.todoBot.GetTrash(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	p, err := t.todoBot.GetProfile(ctx, chatID)
+	if err != nil {
+		return err
+	}
+
+	var text strings.Builder
+	if notice != "" {
+		fmt.Fprintf(&text, "%s\n\n", html.EscapeString(notice))
+	}
+	if len(tasks) == 0 {
+		text.WriteString(html.EscapeString(l.T(i18n.TrashEmpty)))
+		return t.render(ctx, chatID, text.String(), withMenu(l, nil))
+	}
+	days := int((t.cfg.TrashRetention + 24*time.Hour - 1) / (24 * time.Hour))
+	fmt.Fprintf(&text, "<b>%s</b>\n<i>%s</i>\n", html.EscapeString(l.T(i18n.TrashTitle, len(tasks))),
+		html.EscapeString(l.N(i18n.TrashPurgeAfter, days, days)))
+
+	var rows [][]telego.InlineKeyboardButton
+	for i, task := range tasks {
+		if i == trashShown {
+			text.WriteString("\n" + html.EscapeString(l.T(i18n.BulkMore, len(tasks)-trashShown)))
+			break
+		}
+		fmt.Fprintf(&text, "\n%d. %s · %s", i+1, html.EscapeString(task.TaskName),
+			l.T(i18n.DeletedOn, formatTime(p, task.DeletedAt)))
+		number := strconv.Itoa(i + 1)
+		rows = append(rows, tu.InlineKeyboardRow(
+			tu.InlineKeyboardButton("♻️ "+number).
+				WithCallbackData(telegram.RestoreTaskButton+strconv.Itoa(task.ID)),
+			tu.InlineKeyboardButton("❌ "+number).
+				WithCallbackData(telegram.PurgeTaskButton+strconv.Itoa(task.ID)),
+		))
+	}
+	rows = append(rows, tu.InlineKeyboardRow(
+		tu.InlineKeyboardButton(l.T(i18n.ButtonEmptyTrash)).WithCallbackData(telegram.EmptyTrashButton)))
+	return t.render(ctx, chatID, text.String(), withMenu(l, tu.InlineKeyboard(rows...)))
+}
+
+func (t *Telegram) restoreTaskHandler(ctx context.Context, chatID, taskID int64) error {
+	l := i18n.FromContext(ctx)
+	err := t.todoBot.RestoreTask(ctx, chatID, taskID)
+	if message, ok := userMessage(l, err); ok {
+		return t.trashHandler(ctx, chatID, message)
+	}
+	if err != nil {
+		return err
+	}
+	return t.trashHandler(ctx, chatID, l.T(i18n.TaskRestored))
+}
+
+func (t *Telegram) purgeTaskHandler(ctx context.Context, chatID, taskID int64) error {
+	l := i18n.FromContext(ctx)
+	err := t.todoBot.PurgeTask(ctx, chatID, taskID)
+	if message, ok := userMessage(l, err); ok {
+		return t.trashHandler(ctx, chatID, message)
+	}
+	if err != nil {
+		return err
+	}
+	return t.trashHandler(ctx, chatID, l.T(i18n.TaskPurged))
+}
+
+// emptyTrashHandler asks before emptying the trash, as it cannot be undone.
+func (t *Telegram) emptyTrashHandler(ctx context.Context, chatID int64) error {
+	l := i18n.FromContext(ctx)
+	tasks, err := t.todoBot.GetTrash(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	if len(tasks) == 0 {
+		return t.trashHandler(ctx, chatID, "")
+	}
+	text := html.EscapeString(l.T(i18n.ConfirmEmptyTrash, len(tasks)))
+	return t.render(ctx, chatID, text, tu.InlineKeyboard(tu.InlineKeyboardRow(
+		tu.InlineKeyboardButton(l.T(i18n.ButtonConfirmEmptyTrash)).WithCallbackData(telegram.ConfirmEmptyTrashButton),
+		tu.InlineKeyboardButton(l.T(i18n.ButtonBack)).WithCallbackData(telegram.TrashState),
+	)))
+}
+
+func (t *Telegram) confirmEmptyTrashHandler(ctx context.Context, chatID int64) error {
+	purged, err := t.todoBot.EmptyTrash(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	return t.trashHandler(ctx, chatID, i18n.FromContext(ctx).T(i18n.TrashEmptied, purged))
+}
@@ -0,0 +1,31 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+)
+
+func archiveKey(chatID int64) string {
+	return "archive:" + strconv.FormatInt(chatID, 10)
+}
+
+// SetArchiveQuery remembers the archive search being paged through, for as
+// long as a search query.
+func (m *Cache) SetArchiveQuery(ctx context.Context, chatID int64, query string) error {
+	return m.client.Set(ctx, archiveKey(chatID), query, searchTTL).Err()
+}
+
+// GetArchiveQuery returns "", which lists the whole archive, once the query
+// has expired.
+func (m *Cache) GetArchiveQuery(ctx context.Context, chatID int64) (string, error) {
+	query, err := m.client.Get(ctx, archiveKey(chatID)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return query, nil
+}
@@ -0,0 +1,33 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+)
+
+const commentTTL = time.Hour
+
+func commentKey(chatID int64) string {
+	return "comment:" + strconv.FormatInt(chatID, 10)
+}
+
+// SetCommentTarget remembers which task the comment the user is writing is
+// for, and where they came from.
+func (m *Cache) SetCommentTarget(ctx context.Context, chatID int64, target string) error {
+	return m.client.Set(ctx, commentKey(chatID), target, commentTTL).Err()
+}
+
+// GetCommentTarget returns "" once the target has expired.
+func (m *Cache) GetCommentTarget(ctx context.Context, chatID int64) (string, error) {
+	target, err := m.client.Get(ctx, commentKey(chatID)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return target, nil
+}
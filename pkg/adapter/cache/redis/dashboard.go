@@ -0,0 +1,32 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+)
+
+func dashboardKey(chatID int64) string {
+	return "dashboard:" + strconv.FormatInt(chatID, 10)
+}
+
+func (m *Cache) SetDashboard(ctx context.Context, chatID int64, messageID int) error {
+	return m.client.Set(ctx, dashboardKey(chatID), messageID, 0).Err()
+}
+
+// GetDashboard returns 0 when the chat has no dashboard message yet.
+func (m *Cache) GetDashboard(ctx context.Context, chatID int64) (int, error) {
+	messageID, err := m.client.Get(ctx, dashboardKey(chatID)).Int()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return messageID, nil
+}
+
+func (m *Cache) DeleteDashboard(ctx context.Context, chatID int64) error {
+	return m.client.Del(ctx, dashboardKey(chatID)).Err()
+}
@@ -0,0 +1,46 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"telegramBot/pkg/adapter/todobot"
+	"telegramBot/pkg/model/task"
+	"time"
+)
+
+const draftTTL = 24 * time.Hour
+
+func draftKey(userID int64) string {
+	return "draft:" + strconv.FormatInt(userID, 10)
+}
+
+func (m *Cache) SetDraft(ctx context.Context, userID int64, draft task.Task) error {
+	value, err := json.Marshal(draft)
+	if err != nil {
+		return err
+	}
+	return m.client.Set(ctx, draftKey(userID), value, draftTTL).Err()
+}
+
+func (m *Cache) GetDraft(ctx context.Context, userID int64) (task.Task, error) {
+	var draft task.Task
+	value, err := m.client.Get(ctx, draftKey(userID)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return draft, todobot.ErrDraftNotFound
+	}
+	if err != nil {
+		return draft, err
+	}
+	err = json.Unmarshal(value, &draft)
+	if err != nil {
+		return draft, err
+	}
+	return draft, nil
+}
+
+func (m *Cache) DeleteDraft(ctx context.Context, userID int64) error {
+	return m.client.Del(ctx, draftKey(userID)).Err()
+}
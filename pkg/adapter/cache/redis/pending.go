@@ -0,0 +1,48 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"telegramBot/pkg/adapter/todobot"
+	"telegramBot/pkg/model/task"
+	"time"
+)
+
+const pendingTTL = time.Hour
+
+// pendingKey keeps each kind of preview apart, so that confirming one never
+// saves another.
+func pendingKey(userID int64, kind todobot.PendingKind) string {
+	return "pending:" + string(kind) + ":" + strconv.FormatInt(userID, 10)
+}
+
+func (m *Cache) SetPending(ctx context.Context, userID int64, kind todobot.PendingKind, tasks []task.Task) error {
+	value, err := json.Marshal(tasks)
+	if err != nil {
+		return err
+	}
+	return m.client.Set(ctx, pendingKey(userID, kind), value, pendingTTL).Err()
+}
+
+func (m *Cache) GetPending(ctx context.Context, userID int64, kind todobot.PendingKind) ([]task.Task, error) {
+	var tasks []task.Task
+	value, err := m.client.Get(ctx, pendingKey(userID, kind)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, todobot.ErrPendingNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(value, &tasks)
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (m *Cache) DeletePending(ctx context.Context, userID int64, kind todobot.PendingKind) error {
+	return m.client.Del(ctx, pendingKey(userID, kind)).Err()
+}
@@ -3,22 +3,29 @@ package redis
 import (
 	"context"
 	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
 	"strconv"
 	"telegramBot/internal/config"
 )
 
 type Cache struct {
 	client *redis.Client
+	log    *zap.Logger
 }
 
-func New(cfg config.Config) *Cache {
+func New(cfg config.Config, log *zap.Logger) *Cache {
 	client := redis.NewClient(&redis.Options{
 		Addr:     cfg.AddrRedis,
 		Password: cfg.PasswordRedis,
-		DB:       0,
+		DB:       cfg.DBRedis,
 	})
+	err := client.Ping(context.Background()).Err()
+	if err != nil {
+		log.Warn("New() -> client.Ping()", zap.String("addr", cfg.AddrRedis), zap.Error(err))
+	}
 	return &Cache{
 		client: client,
+		log:    log,
 	}
 }
 
@@ -50,9 +57,20 @@ func (m *Cache) Get(ctx context.Context, chatID int64) ([]int, error) {
 			}
 			result[i] = int(intValue)
 		}
-		m.client.Del(ctx, key)
+		err = m.client.Del(ctx, key).Err()
+		if err != nil {
+			m.log.Warn("Get() -> m.client.Del()", zap.Int64("chatID", chatID), zap.Error(err))
+		}
 		return result, nil
 	} else {
 		return nil, nil
 	}
 }
+
+func (m *Cache) AddHook(hook redis.Hook) {
+	m.client.AddHook(hook)
+}
+
+func (m *Cache) Ping(ctx context.Context) error {
+	return m.client.Ping(ctx).Err()
+}
@@ -0,0 +1,33 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+)
+
+const retryTTL = time.Hour
+
+func retryKey(chatID int64) string {
+	return "retry:" + strconv.FormatInt(chatID, 10)
+}
+
+// SetRetryAction remembers the last failed update, as the handlers encode it,
+// to be replayed when the user presses the retry button.
+func (m *Cache) SetRetryAction(ctx context.Context, chatID int64, action string) error {
+	return m.client.Set(ctx, retryKey(chatID), action, retryTTL).Err()
+}
+
+// GetRetryAction returns "" when there is nothing to retry.
+func (m *Cache) GetRetryAction(ctx context.Context, chatID int64) (string, error) {
+	action, err := m.client.GetDel(ctx, retryKey(chatID)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return action, nil
+}
@@ -0,0 +1,31 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+)
+
+const searchTTL = 24 * time.Hour
+
+func searchKey(chatID int64) string {
+	return "search:" + strconv.FormatInt(chatID, 10)
+}
+
+func (m *Cache) SetSearchQuery(ctx context.Context, chatID int64, query string) error {
+	return m.client.Set(ctx, searchKey(chatID), query, searchTTL).Err()
+}
+
+// GetSearchQuery returns "" once the query has expired.
+func (m *Cache) GetSearchQuery(ctx context.Context, chatID int64) (string, error) {
+	query, err := m.client.Get(ctx, searchKey(chatID)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return query, nil
+}
@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"math"
+	"net/http"
+)
+
+const namespace = "todobot"
+
+var (
+	Updates = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "updates_total",
+		Help:      "Telegram updates processed, by command and conversation state.",
+	}, []string{"command", "state"})
+	UpdateErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "update_errors_total",
+		Help:      "Telegram updates whose handler failed, by command.",
+	}, []string{"command"})
+	HandlerDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "handler_duration_seconds",
+		Help:      "Time spent handling one update, by command.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"command"})
+	TelegramErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "telegram_api_errors_total",
+		Help:      "Failed Bot API calls after retries, by method.",
+	}, []string{"method"})
+	TelegramRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "telegram_api_retries_total",
+		Help:      "Retried Bot API calls, by method.",
+	}, []string{"method"})
+	StorageDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "storage_query_duration_seconds",
+		Help:      "Latency of storage calls, by method.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"method"})
+	StorageErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "storage_errors_total",
+		Help:      "Failed storage calls, by method.",
+	}, []string{"method"})
+	RedisErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "redis_errors_total",
+		Help:      "Failed Redis commands, by command.",
+	}, []string{"command"})
+	Tasks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tasks_total",
+		Help:      "Task lifecycle events: created, completed or deleted.",
+	}, []string{"event"})
+)
+
+// ActiveConversations exports the number of users in the middle of a
+// multi-step flow. count is called on every scrape.
+func ActiveConversations(count func(ctx context.Context) (int, error)) {
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "active_conversations",
+		Help:      "Users whose conversation state is not the default one.",
+	}, func() float64 {
+		n, err := count(context.Background())
+		if err != nil {
+			return math.NaN()
+		}
+		return float64(n)
+	})
+}
+
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
@@ -0,0 +1,266 @@
+package metrics
+
+import (
+	"context"
+	"telegramBot/pkg/adapter/todobot"
+	"telegramBot/pkg/model/profile"
+	"telegramBot/pkg/model/task"
+	"telegramBot/pkg/model/task/status"
+	"time"
+)
+
+// Storage times every call into the wrapped todobot.Storage and counts the
+// task lifecycle events that pass through it.
+type Storage struct {
+	next todobot.Storage
+}
+
+func NewStorage(next todobot.Storage) *Storage {
+	return &Storage{next: next}
+}
+
+func observe(method string, start time.Time, err error) {
+	StorageDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	if err != nil {
+		StorageErrors.WithLabelValues(method).Inc()
+	}
+}
+
+func (s *Storage) SetUserState(ctx context.Context, userID int64, state int) (err error) {
+	defer func(start time.Time) { observe("SetUserState", start, err) }(time.Now())
+	return s.next.SetUserState(ctx, userID, state)
+}
+
+func (s *Storage) GetUserState(ctx context.Context, userID int64) (state int, err error) {
+	defer func(start time.Time) { observe("GetUserState", start, err) }(time.Now())
+	return s.next.GetUserState(ctx, userID)
+}
+
+func (s *Storage) CountActiveConversations(ctx context.Context) (count int, err error) {
+	defer func(start time.Time) { observe("CountActiveConversations", start, err) }(time.Now())
+	return s.next.CountActiveConversations(ctx)
+}
+
+func (s *Storage) SaveTask(ctx context.Context, newTask task.Task) (taskID int64, err error) {
+	defer func(start time.Time) { observe("SaveTask", start, err) }(time.Now())
+	taskID, err = s.next.SaveTask(ctx, newTask)
+	if err == nil {
+		Tasks.WithLabelValues("created").Inc()
+	}
+	return taskID, err
+}
+
+func (s *Storage) SaveTasks(ctx context.Context, newTasks []task.Task) (taskIDs []int64, err error) {
+	defer func(start time.Time) { observe("SaveTasks", start, err) }(time.Now())
+	taskIDs, err = s.next.SaveTasks(ctx, newTasks)
+	if err == nil {
+		Tasks.WithLabelValues("created").Add(float64(len(taskIDs)))
+	}
+	return taskIDs, err
+}
+
+func (s *Storage) SetTaskStatus(ctx context.Context, userID, taskID int64, taskStatus int) (err error) {
+	defer func(start time.Time) { observe("SetTaskStatus", start, err) }(time.Now())
+	err = s.next.SetTaskStatus(ctx, userID, taskID, taskStatus)
+	if err == nil && taskStatus == status.Done {
+		Tasks.WithLabelValues("completed").Inc()
+	}
+	return err
+}
+
+func (s *Storage) GetTask(ctx context.Context, userID, taskID int64) (t task.Task, err error) {
+	defer func(start time.Time) { observe("GetTask", start, err) }(time.Now())
+	return s.next.GetTask(ctx, userID, taskID)
+}
+
+func (s *Storage) DeleteTask(ctx context.Context, userID int64, taskName string) (taskID int64, err error) {
+	defer func(start time.Time) { observe("DeleteTask", start, err) }(time.Now())
+	taskID, err = s.next.DeleteTask(ctx, userID, taskName)
+	if err == nil {
+		Tasks.WithLabelValues("deleted").Inc()
+	}
+	return taskID, err
+}
+
+func (s *Storage) DeleteTaskByID(ctx context.Context, userID, taskID int64) (err error) {
+	defer func(start time.Time) { observe("DeleteTaskByID", start, err) }(time.Now())
+	err = s.next.DeleteTaskByID(ctx, userID, taskID)
+	if err == nil {
+		Tasks.WithLabelValues("deleted").Inc()
+	}
+	return err
+}
+
+func (s *Storage) DeleteCreatingTasks(ctx context.Context, olderThan time.Time) (deleted int64, err error) {
+	defer func(start time.Time) { observe("DeleteCreatingTasks", start, err) }(time.Now())
+	return s.next.DeleteCreatingTasks(ctx, olderThan)
+}
+
+func (s *Storage) GetListOfTasks(ctx context.Context, userID int64) (tasks []task.Task, err error) {
+	defer func(start time.Time) { observe("GetListOfTasks", start, err) }(time.Now())
+	return s.next.GetListOfTasks(ctx, userID)
+}
+
+func (s *Storage) SearchTasks(ctx context.Context, userID int64, query string,
+	limit, offset int) (result task.SearchResult, err error) {
+	defer func(start time.Time) { observe("SearchTasks", start, err) }(time.Now())
+	return s.next.SearchTasks(ctx, userID, query, limit, offset)
+}
+
+func (s *Storage) GetTasksPage(ctx context.Context, userID int64,
+	limit, offset int) (tasks []task.Task, total int, err error) {
+	defer func(start time.Time) { observe("GetTasksPage", start, err) }(time.Now())
+	return s.next.GetTasksPage(ctx, userID, limit, offset)
+}
+
+func (s *Storage) SetPageSize(ctx context.Context, userID int64, pageSize int) (err error) {
+	defer func(start time.Time) { observe("SetPageSize", start, err) }(time.Now())
+	return s.next.SetPageSize(ctx, userID, pageSize)
+}
+
+func (s *Storage) GetPageSize(ctx context.Context, userID int64) (pageSize int, err error) {
+	defer func(start time.Time) { observe("GetPageSize", start, err) }(time.Now())
+	return s.next.GetPageSize(ctx, userID)
+}
+
+func (s *Storage) SetUserLanguage(ctx context.Context, userID int64, language string) (err error) {
+	defer func(start time.Time) { observe("SetUserLanguage", start, err) }(time.Now())
+	return s.next.SetUserLanguage(ctx, userID, language)
+}
+
+func (s *Storage) GetUserLanguage(ctx context.Context, userID int64) (language string, err error) {
+	defer func(start time.Time) { observe("GetUserLanguage", start, err) }(time.Now())
+	return s.next.GetUserLanguage(ctx, userID)
+}
+
+func (s *Storage) GetProfile(ctx context.Context, userID int64) (p profile.Profile, err error) {
+	defer func(start time.Time) { observe("GetProfile", start, err) }(time.Now())
+	return s.next.GetProfile(ctx, userID)
+}
+
+func (s *Storage) SaveProfile(ctx context.Context, p profile.Profile) (err error) {
+	defer func(start time.Time) { observe("SaveProfile", start, err) }(time.Now())
+	return s.next.SaveProfile(ctx, p)
+}
+
+func (s *Storage) ListDigestProfiles(ctx context.Context) (profiles []profile.Profile, err error) {
+	defer func(start time.Time) { observe("ListDigestProfiles", start, err) }(time.Now())
+	return s.next.ListDigestProfiles(ctx)
+}
+
+func (s *Storage) SetDigestSent(ctx context.Context, userID int64, day string) (err error) {
+	defer func(start time.Time) { observe("SetDigestSent", start, err) }(time.Now())
+	return s.next.SetDigestSent(ctx, userID, day)
+}
+
+func (s *Storage) GetCalendarToken(ctx context.Context, userID int64) (token string, err error) {
+	defer func(start time.Time) { observe("GetCalendarToken", start, err) }(time.Now())
+	return s.next.GetCalendarToken(ctx, userID)
+}
+
+func (s *Storage) SetCalendarToken(ctx context.Context, userID int64, token string) (err error) {
+	defer func(start time.Time) { observe("SetCalendarToken", start, err) }(time.Now())
+	return s.next.SetCalendarToken(ctx, userID, token)
+}
+
+func (s *Storage) GetCalendarUser(ctx context.Context, token string) (userID int64, err error) {
+	defer func(start time.Time) { observe("GetCalendarUser", start, err) }(time.Now())
+	return s.next.GetCalendarUser(ctx, token)
+}
+
+func (s *Storage) UpdateTask(ctx context.Context, userID int64, t task.Task) (err error) {
+	defer func(start time.Time) { observe("UpdateTask", start, err) }(time.Now())
+	return s.next.UpdateTask(ctx, userID, t)
+}
+
+func (s *Storage) AddAppPassword(ctx context.Context, userID int64, hash string) (err error) {
+	defer func(start time.Time) { observe("AddAppPassword", start, err) }(time.Now())
+	return s.next.AddAppPassword(ctx, userID, hash)
+}
+
+func (s *Storage) CountAppPasswords(ctx context.Context, userID int64) (count int, err error) {
+	defer func(start time.Time) { observe("CountAppPasswords", start, err) }(time.Now())
+	return s.next.CountAppPasswords(ctx, userID)
+}
+
+func (s *Storage) DeleteAppPasswords(ctx context.Context, userID int64) (err error) {
+	defer func(start time.Time) { observe("DeleteAppPasswords", start, err) }(time.Now())
+	return s.next.DeleteAppPasswords(ctx, userID)
+}
+
+func (s *Storage) GetAppPasswordUser(ctx context.Context, hash string) (userID int64, err error) {
+	defer func(start time.Time) { observe("GetAppPasswordUser", start, err) }(time.Now())
+	return s.next.GetAppPasswordUser(ctx, hash)
+}
+
+func (s *Storage) GetAttachments(ctx context.Context, userID, taskID int64) (attachments []task.Attachment, err error) {
+	defer func(start time.Time) { observe("GetAttachments", start, err) }(time.Now())
+	return s.next.GetAttachments(ctx, userID, taskID)
+}
+
+func (s *Storage) CountAttachments(ctx context.Context, userID int64,
+	taskIDs []int64) (counts map[int64]int, err error) {
+	defer func(start time.Time) { observe("CountAttachments", start, err) }(time.Now())
+	return s.next.CountAttachments(ctx, userID, taskIDs)
+}
+
+func (s *Storage) AddEvent(ctx context.Context, e task.Event) (err error) {
+	defer func(start time.Time) { observe("AddEvent", start, err) }(time.Now())
+	return s.next.AddEvent(ctx, e)
+}
+
+func (s *Storage) GetEvents(ctx context.Context, userID, taskID int64) (events []task.Event, err error) {
+	defer func(start time.Time) { observe("GetEvents", start, err) }(time.Now())
+	return s.next.GetEvents(ctx, userID, taskID)
+}
+
+func (s *Storage) AddComment(ctx context.Context, userID int64, c task.Comment) (commentID int64, err error) {
+	defer func(start time.Time) { observe("AddComment", start, err) }(time.Now())
+	return s.next.AddComment(ctx, userID, c)
+}
+
+func (s *Storage) GetComments(ctx context.Context, userID, taskID int64) (comments []task.Comment, err error) {
+	defer func(start time.Time) { observe("GetComments", start, err) }(time.Now())
+	return s.next.GetComments(ctx, userID, taskID)
+}
+
+func (s *Storage) GetDeletedTasks(ctx context.Context, userID int64) (tasks []task.Task, err error) {
+	defer func(start time.Time) { observe("GetDeletedTasks", start, err) }(time.Now())
+	return s.next.GetDeletedTasks(ctx, userID)
+}
+
+func (s *Storage) RestoreTask(ctx context.Context, userID, taskID int64, deletedSince time.Time) (err error) {
+	defer func(start time.Time) { observe("RestoreTask", start, err) }(time.Now())
+	return s.next.RestoreTask(ctx, userID, taskID, deletedSince)
+}
+
+func (s *Storage) PurgeTask(ctx context.Context, userID, taskID int64) (err error) {
+	defer func(start time.Time) { observe("PurgeTask", start, err) }(time.Now())
+	return s.next.PurgeTask(ctx, userID, taskID)
+}
+
+func (s *Storage) EmptyTrash(ctx context.Context, userID int64) (purged int64, err error) {
+	defer func(start time.Time) { observe("EmptyTrash", start, err) }(time.Now())
+	return s.next.EmptyTrash(ctx, userID)
+}
+
+func (s *Storage) PurgeDeletedTasks(ctx context.Context, deletedBefore time.Time) (purged int64, err error) {
+	defer func(start time.Time) { observe("PurgeDeletedTasks", start, err) }(time.Now())
+	return s.next.PurgeDeletedTasks(ctx, deletedBefore)
+}
+
+func (s *Storage) SearchArchive(ctx context.Context, userID int64, query string,
+	limit, offset int) (result task.SearchResult, err error) {
+	defer func(start time.Time) { observe("SearchArchive", start, err) }(time.Now())
+	return s.next.SearchArchive(ctx, userID, query, limit, offset)
+}
+
+func (s *Storage) GetArchivedTasks(ctx context.Context, userID int64) (tasks []task.Task, err error) {
+	defer func(start time.Time) { observe("GetArchivedTasks", start, err) }(time.Now())
+	return s.next.GetArchivedTasks(ctx, userID)
+}
+
+func (s *Storage) ArchiveCompletedTasks(ctx context.Context, now time.Time) (archived []task.Task, err error) {
+	defer func(start time.Time) { observe("ArchiveCompletedTasks", start, err) }(time.Now())
+	return s.next.ArchiveCompletedTasks(ctx, now)
+}
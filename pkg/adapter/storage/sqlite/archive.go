@@ -0,0 +1,70 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"telegramBot/pkg/model/task"
+	"telegramBot/pkg/model/task/status"
+	"time"
+)
+
+// GetArchivedTasks returns all of the user's archived tasks.
+func (s *Storage) GetArchivedTasks(ctx context.Context, userID int64) ([]task.Task, error) {
+	rows, err := s.database.QueryContext(ctx, "SELECT "+taskColumns+" FROM tasks WHERE userID = ? AND "+
+		archivedTasks+" ORDER BY id", userID)
+	if err != nil {
+		return nil, fmt.Errorf("archive.go -> GetArchivedTasks() -> s.database.QueryContext(): %w", err)
+	}
+	defer rows.Close()
+	var tasks []task.Task
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("archive.go -> GetArchivedTasks() -> scanTask(): %w", err)
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, nil
+}
+
+// ArchiveCompletedTasks archives the done tasks of every user whose
+// ArchiveAfter days have passed by now, and returns them with their IDs and
+// owners only.
+func (s *Storage) ArchiveCompletedTasks(ctx context.Context, now time.Time) ([]task.Task, error) {
+	tx, err := s.database.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("archive.go -> ArchiveCompletedTasks() -> s.database.BeginTx(): %w", err)
+	}
+	defer tx.Rollback()
+
+	// The taskStatus and deletedAt terms match the partial index tasks_done.
+	rows, err := tx.QueryContext(ctx, `SELECT tasks.id, tasks.userID FROM users
+        JOIN tasks ON tasks.userID = users.id AND taskStatus = 2 AND deletedAt = 0
+        WHERE users.archiveAfter > 0 AND tasks.completedAt <= ? - users.archiveAfter * 86400`, now.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("archive.go -> ArchiveCompletedTasks() -> tx.QueryContext(): %w", err)
+	}
+	defer rows.Close()
+	var archived []task.Task
+	for rows.Next() {
+		var t task.Task
+		err := rows.Scan(&t.ID, &t.ChatId)
+		if err != nil {
+			return nil, fmt.Errorf("archive.go -> ArchiveCompletedTasks() -> rows.Scan(): %w", err)
+		}
+		archived = append(archived, t)
+	}
+	rows.Close()
+
+	for _, t := range archived {
+		_, err := tx.ExecContext(ctx, "UPDATE tasks SET taskStatus = ? WHERE id = ?", status.Archived, t.ID)
+		if err != nil {
+			return nil, fmt.Errorf("archive.go -> ArchiveCompletedTasks() -> tx.ExecContext(): %w", err)
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("archive.go -> ArchiveCompletedTasks() -> tx.Commit(): %w", err)
+	}
+	return archived, nil
+}
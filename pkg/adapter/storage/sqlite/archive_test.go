@@ -0,0 +1,152 @@
+package sqlite
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"telegramBot/pkg/model/profile"
+	"telegramBot/pkg/model/task"
+	"telegramBot/pkg/model/task/status"
+	"testing"
+	"time"
+)
+
+// complete marks tasks done as if they were completed at completedAt.
+func complete(t *testing.T, s *Storage, completedAt time.Time, taskIDs ...int64) {
+	t.Helper()
+	for _, taskID := range taskIDs {
+		_, err := s.database.Exec("UPDATE tasks SET taskStatus = ?, completedAt = ? WHERE id = ?", status.Done,
+			completedAt.Unix(), taskID)
+		if err != nil {
+			t.Fatalf("s.database.Exec() error = %v", err)
+		}
+	}
+}
+
+func archiveAfter(t *testing.T, s *Storage, userID int64, days int) {
+	t.Helper()
+	p := profile.Default(userID)
+	p.ArchiveAfter = days
+	err := s.SaveProfile(context.Background(), p)
+	if err != nil {
+		t.Fatalf("SaveProfile() error = %v", err)
+	}
+}
+
+func TestArchiveCompletedTasks(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	tests := []struct {
+		name      string
+		days      int
+		completed time.Duration
+		deleted   bool
+		want      bool
+	}{
+		{"past the delay", 7, 8 * day, false, true},
+		{"exactly the delay", 7, 7 * day, false, true},
+		{"a second short of the delay", 7, 7*day - time.Second, false, false},
+		{"next day", 1, day, false, true},
+		{"archiving off", 0, 365 * day, false, false},
+		{"in the trash", 1, 8 * day, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestStorage(t)
+			archiveAfter(t, s, testUser, tt.days)
+			taskIDs := saveTasks(t, s, task.Task{TaskName: "Pay rent"}, task.Task{TaskName: "Open"})
+			complete(t, s, now.Add(-tt.completed), taskIDs[0])
+			if tt.deleted {
+				trash(t, s, now, taskIDs[0])
+			}
+
+			archived, err := s.ArchiveCompletedTasks(ctx, now)
+			if err != nil {
+				t.Fatalf("ArchiveCompletedTasks() error = %v", err)
+			}
+			want := []task.Task(nil)
+			if tt.want {
+				want = []task.Task{{ID: int(taskIDs[0]), ChatId: testUser}}
+			}
+			if !reflect.DeepEqual(archived, want) {
+				t.Errorf("ArchiveCompletedTasks() = %+v, want %+v", archived, want)
+			}
+			got, err := s.GetArchivedTasks(ctx, testUser)
+			if err != nil || len(got) != len(want) {
+				t.Errorf("GetArchivedTasks() = %+v, %v, want %d tasks", got, err, len(want))
+			}
+		})
+	}
+}
+
+func TestArchiveQueries(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStorage(t)
+	now := time.Now()
+	archiveAfter(t, s, testUser, 1)
+	archiveAfter(t, s, testUser+1, 30)
+	taskIDs := saveTasks(t, s,
+		task.Task{TaskName: "Pay rent"},
+		task.Task{TaskName: "Paid rent"},
+		task.Task{TaskName: "Call mum"},
+		task.Task{TaskName: "Buy milk"},
+		task.Task{TaskName: "Rent a bike", ChatId: testUser + 1},
+	)
+	complete(t, s, now.Add(-48*time.Hour), taskIDs[1], taskIDs[2], taskIDs[4])
+	complete(t, s, now, taskIDs[3])
+
+	archived, err := s.ArchiveCompletedTasks(ctx, now)
+	if err != nil || len(archived) != 2 {
+		t.Fatalf("ArchiveCompletedTasks() = %+v, %v, want Paid rent and Call mum", archived, err)
+	}
+	// Archiving again finds nothing left to archive.
+	archived, err = s.ArchiveCompletedTasks(ctx, now)
+	if err != nil || len(archived) != 0 {
+		t.Errorf("ArchiveCompletedTasks() again = %+v, %v, want none", archived, err)
+	}
+
+	tasks, err := s.GetListOfTasks(ctx, testUser)
+	if err != nil {
+		t.Fatalf("GetListOfTasks() error = %v", err)
+	}
+	var names []string
+	for _, t := range tasks {
+		names = append(names, t.TaskName)
+	}
+	sort.Strings(names)
+	if want := []string{"Buy milk", "Pay rent"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("GetListOfTasks() = %q, want %q", names, want)
+	}
+	_, total, err := s.GetTasksPage(ctx, testUser, 10, 0)
+	if err != nil || total != 2 {
+		t.Errorf("GetTasksPage() total = %d, %v, want the archive left out", total, err)
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"Call mum", "Paid rent"}},
+		{"rent", []string{"Paid rent"}},
+		{"bike", nil},
+	}
+	for _, tt := range tests {
+		result, err := s.SearchArchive(ctx, testUser, tt.query, 10, 0)
+		if err != nil {
+			t.Fatalf("SearchArchive(%q) error = %v", tt.query, err)
+		}
+		got := matchNames(result)
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, tt.want) || result.Total != len(tt.want) {
+			t.Errorf("SearchArchive(%q) = %q of %d, want %q", tt.query, got, result.Total, tt.want)
+		}
+	}
+	result, err := s.SearchTasks(ctx, testUser, "rent", 10, 0)
+	if err != nil {
+		t.Fatalf("SearchTasks() error = %v", err)
+	}
+	if got := matchNames(result); !reflect.DeepEqual(got, []string{"Pay rent"}) {
+		t.Errorf("SearchTasks() = %q, want the archive left out", got)
+	}
+}
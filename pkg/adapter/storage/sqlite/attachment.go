@@ -0,0 +1,81 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"telegramBot/pkg/model/task"
+	"time"
+)
+
+func insertAttachment(ctx context.Context, tx *sql.Tx, a task.Attachment) error {
+	createdAt := a.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	_, err := tx.ExecContext(ctx, `INSERT INTO attachments
+        (taskID, kind, fileID, fileName, mimeType, size, createdAt) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		a.TaskID, a.Kind, a.FileID, a.FileName, a.MimeType, a.Size, createdAt.Unix())
+	if err != nil {
+		return fmt.Errorf("tx.ExecContext(): %w", err)
+	}
+	return nil
+}
+
+// GetAttachments returns the attachments of a task the user owns, oldest
+// first.
+func (s *Storage) GetAttachments(ctx context.Context, userID, taskID int64) ([]task.Attachment, error) {
+	err := s.checkOwner(ctx, userID, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("attachment.go -> GetAttachments() -> s.checkOwner(): %w", err)
+	}
+	rows, err := s.database.QueryContext(ctx, `SELECT id, taskID, kind, fileID, fileName, mimeType, size, createdAt
+        FROM attachments WHERE taskID = ? ORDER BY id`, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("attachment.go -> GetAttachments() -> s.database.QueryContext(): %w", err)
+	}
+	defer rows.Close()
+	var attachments []task.Attachment
+	for rows.Next() {
+		var a task.Attachment
+		var createdAt int64
+		err := rows.Scan(&a.ID, &a.TaskID, &a.Kind, &a.FileID, &a.FileName, &a.MimeType, &a.Size, &createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("attachment.go -> GetAttachments() -> rows.Scan(): %w", err)
+		}
+		a.CreatedAt = time.Unix(createdAt, 0)
+		attachments = append(attachments, a)
+	}
+	return attachments, nil
+}
+
+// CountAttachments returns how many attachments each of taskIDs has, leaving
+// out the tasks without any and those the user does not own.
+func (s *Storage) CountAttachments(ctx context.Context, userID int64, taskIDs []int64) (map[int64]int, error) {
+	counts := make(map[int64]int)
+	if len(taskIDs) == 0 {
+		return counts, nil
+	}
+	args := []any{userID}
+	for _, taskID := range taskIDs {
+		args = append(args, taskID)
+	}
+	rows, err := s.database.QueryContext(ctx, `SELECT a.taskID, COUNT(*) FROM attachments a
+        JOIN tasks t ON t.id = a.taskID WHERE t.userID = ? AND t.deletedAt = 0 AND a.taskID IN (?`+
+		strings.Repeat(", ?", len(taskIDs)-1)+`) GROUP BY a.taskID`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("attachment.go -> CountAttachments() -> s.database.QueryContext(): %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var taskID int64
+		var count int
+		err := rows.Scan(&taskID, &count)
+		if err != nil {
+			return nil, fmt.Errorf("attachment.go -> CountAttachments() -> rows.Scan(): %w", err)
+		}
+		counts[taskID] = count
+	}
+	return counts, nil
+}
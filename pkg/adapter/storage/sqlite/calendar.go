@@ -0,0 +1,48 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"telegramBot/pkg/adapter/todobot"
+)
+
+// GetCalendarToken returns an empty token for users who never asked for
+// their calendar.
+func (s *Storage) GetCalendarToken(ctx context.Context, userID int64) (string, error) {
+	var token string
+	err := s.database.QueryRowContext(ctx, "SELECT calendarToken FROM users WHERE id = ?", userID).Scan(&token)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("calendar.go -> GetCalendarToken() -> s.database.QueryRowContext(): %w", err)
+	}
+	return token, nil
+}
+
+func (s *Storage) SetCalendarToken(ctx context.Context, userID int64, token string) error {
+	_, err := s.database.ExecContext(ctx, `INSERT INTO users (id, state, calendarToken) VALUES (?, 0, ?)
+        ON CONFLICT (id) DO UPDATE SET calendarToken = excluded.calendarToken`, userID, token)
+	if err != nil {
+		return fmt.Errorf("calendar.go -> SetCalendarToken() -> s.database.ExecContext(): %w", err)
+	}
+	return nil
+}
+
+// GetCalendarUser returns the owner of a calendar token.
+func (s *Storage) GetCalendarUser(ctx context.Context, token string) (int64, error) {
+	if token == "" {
+		return 0, fmt.Errorf("calendar.go -> GetCalendarUser(): %w", todobot.ErrCalendarNotFound)
+	}
+	var userID int64
+	err := s.database.QueryRowContext(ctx, "SELECT id FROM users WHERE calendarToken = ?", token).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("calendar.go -> GetCalendarUser(): %w", todobot.ErrCalendarNotFound)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("calendar.go -> GetCalendarUser() -> s.database.QueryRowContext(): %w", err)
+	}
+	return userID, nil
+}
@@ -0,0 +1,89 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"telegramBot/pkg/model/task"
+	"time"
+)
+
+func (s *Storage) AddEvent(ctx context.Context, e task.Event) error {
+	createdAt := e.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	_, err := s.database.ExecContext(ctx, `INSERT INTO events (taskID, userID, actorID, kind, detail, createdAt)
+        VALUES (?, ?, ?, ?, ?, ?)`, e.TaskID, e.UserID, e.ActorID, e.Kind, e.Detail, createdAt.Unix())
+	if err != nil {
+		return fmt.Errorf("history.go -> AddEvent() -> s.database.ExecContext(): %w", err)
+	}
+	return nil
+}
+
+// GetEvents returns the history of a task the user owns or owned, oldest
+// first.
+func (s *Storage) GetEvents(ctx context.Context, userID, taskID int64) ([]task.Event, error) {
+	rows, err := s.database.QueryContext(ctx, `SELECT id, taskID, userID, actorID, kind, detail, createdAt
+        FROM events WHERE userID = ? AND taskID = ? ORDER BY createdAt, id`, userID, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("history.go -> GetEvents() -> s.database.QueryContext(): %w", err)
+	}
+	defer rows.Close()
+	var events []task.Event
+	for rows.Next() {
+		var e task.Event
+		var createdAt int64
+		err := rows.Scan(&e.ID, &e.TaskID, &e.UserID, &e.ActorID, &e.Kind, &e.Detail, &createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("history.go -> GetEvents() -> rows.Scan(): %w", err)
+		}
+		e.CreatedAt = time.Unix(createdAt, 0)
+		events = append(events, e)
+	}
+	return events, nil
+}
+
+// AddComment adds a comment to a task the user owns.
+func (s *Storage) AddComment(ctx context.Context, userID int64, c task.Comment) (int64, error) {
+	err := s.checkOwner(ctx, userID, c.TaskID)
+	if err != nil {
+		return 0, fmt.Errorf("history.go -> AddComment() -> s.checkOwner(): %w", err)
+	}
+	result, err := s.database.ExecContext(ctx,
+		"INSERT INTO comments (taskID, authorID, text, createdAt) VALUES (?, ?, ?, ?)",
+		c.TaskID, c.AuthorID, c.Text, time.Now().Unix())
+	if err != nil {
+		return 0, fmt.Errorf("history.go -> AddComment() -> s.database.ExecContext(): %w", err)
+	}
+	commentID, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("history.go -> AddComment() -> result.LastInsertId(): %w", err)
+	}
+	return commentID, nil
+}
+
+// GetComments returns the comments on a task the user owns, oldest first.
+func (s *Storage) GetComments(ctx context.Context, userID, taskID int64) ([]task.Comment, error) {
+	err := s.checkOwner(ctx, userID, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("history.go -> GetComments() -> s.checkOwner(): %w", err)
+	}
+	rows, err := s.database.QueryContext(ctx, `SELECT id, taskID, authorID, text, createdAt FROM comments
+        WHERE taskID = ? ORDER BY id`, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("history.go -> GetComments() -> s.database.QueryContext(): %w", err)
+	}
+	defer rows.Close()
+	var comments []task.Comment
+	for rows.Next() {
+		var c task.Comment
+		var createdAt int64
+		err := rows.Scan(&c.ID, &c.TaskID, &c.AuthorID, &c.Text, &createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("history.go -> GetComments() -> rows.Scan(): %w", err)
+		}
+		c.CreatedAt = time.Unix(createdAt, 0)
+		comments = append(comments, c)
+	}
+	return comments, nil
+}
@@ -0,0 +1,30 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+func (s *Storage) SetUserLanguage(ctx context.Context, userID int64, language string) error {
+	_, err := s.database.ExecContext(ctx, `INSERT INTO users (id, state, language) VALUES (?, 0, ?)
+        ON CONFLICT (id) DO UPDATE SET language = excluded.language`, userID, language)
+	if err != nil {
+		return fmt.Errorf("language.go -> SetUserLanguage() -> s.database.ExecContext(): %w", err)
+	}
+	return nil
+}
+
+// GetUserLanguage returns an empty string for users who never chose one.
+func (s *Storage) GetUserLanguage(ctx context.Context, userID int64) (string, error) {
+	var language string
+	err := s.database.QueryRowContext(ctx, "SELECT language FROM users WHERE id = ?", userID).Scan(&language)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("language.go -> GetUserLanguage() -> s.database.QueryRowContext(): %w", err)
+	}
+	return language, nil
+}
@@ -0,0 +1,124 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"go.uber.org/zap"
+)
+
+// migrations are applied in order on top of the base schema created in New.
+// The index of the last applied one is kept in PRAGMA user_version, so
+// entries must only ever be appended.
+var migrations = []string{
+	`ALTER TABLE tasks ADD COLUMN createdAt INTEGER`,
+	`ALTER TABLE tasks ADD COLUMN tags TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE users ADD COLUMN pageSize INTEGER NOT NULL DEFAULT 5`,
+	`ALTER TABLE users ADD COLUMN language TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE users ADD COLUMN timezone TEXT NOT NULL DEFAULT 'UTC'`,
+	`ALTER TABLE users ADD COLUMN dateFormat TEXT NOT NULL DEFAULT '2006-01-02'`,
+	`ALTER TABLE users ADD COLUMN defaultList TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE users ADD COLUMN quietFrom INTEGER NOT NULL DEFAULT 0`,
+	`ALTER TABLE users ADD COLUMN quietTo INTEGER NOT NULL DEFAULT 0`,
+	`ALTER TABLE tasks ADD COLUMN list TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE tasks ADD COLUMN due TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE users ADD COLUMN digestEnabled INTEGER NOT NULL DEFAULT 0`,
+	`ALTER TABLE users ADD COLUMN digestAt INTEGER NOT NULL DEFAULT 480`,
+	`ALTER TABLE users ADD COLUMN digestSent TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE tasks ADD COLUMN priority INTEGER NOT NULL DEFAULT 0`,
+	`ALTER TABLE tasks ADD COLUMN repeat TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE users ADD COLUMN calendarToken TEXT NOT NULL DEFAULT ''`,
+	`CREATE UNIQUE INDEX users_calendarToken ON users (calendarToken) WHERE calendarToken != ''`,
+	`ALTER TABLE tasks ADD COLUMN uid TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE tasks ADD COLUMN resource TEXT NOT NULL DEFAULT ''`,
+	`CREATE TABLE appPasswords (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        userID INTEGER NOT NULL,
+        hash TEXT NOT NULL UNIQUE,
+        createdAt INTEGER NOT NULL
+    )`,
+	`CREATE TABLE attachments (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        taskID INTEGER NOT NULL,
+        kind TEXT NOT NULL,
+        fileID TEXT NOT NULL,
+        fileName TEXT NOT NULL DEFAULT '',
+        mimeType TEXT NOT NULL DEFAULT '',
+        size INTEGER NOT NULL DEFAULT 0,
+        createdAt INTEGER NOT NULL
+    )`,
+	`CREATE INDEX attachments_taskID ON attachments (taskID)`,
+	`CREATE TRIGGER attachments_delete AFTER DELETE ON tasks BEGIN
+        DELETE FROM attachments WHERE taskID = old.id;
+    END`,
+	`CREATE TABLE comments (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        taskID INTEGER NOT NULL,
+        authorID INTEGER NOT NULL,
+        text TEXT NOT NULL,
+        createdAt INTEGER NOT NULL
+    )`,
+	`CREATE INDEX comments_taskID ON comments (taskID)`,
+	`CREATE TRIGGER comments_delete AFTER DELETE ON tasks BEGIN
+        DELETE FROM comments WHERE taskID = old.id;
+    END`,
+	// Events outlive their task, so a deletion stays on record.
+	`CREATE TABLE events (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        taskID INTEGER NOT NULL,
+        userID INTEGER NOT NULL,
+        actorID INTEGER NOT NULL,
+        kind TEXT NOT NULL,
+        detail TEXT NOT NULL DEFAULT '',
+        createdAt INTEGER NOT NULL
+    )`,
+	`CREATE INDEX events_userID_taskID ON events (userID, taskID)`,
+	// Tasks created before the history began get their creation on record.
+	`INSERT INTO events (taskID, userID, actorID, kind, detail, createdAt)
+        SELECT id, userID, userID, 'created', taskName, COALESCE(createdAt, 0) FROM tasks WHERE taskStatus != 0`,
+	// Deleted tasks stay in the trash until they are restored or purged.
+	`ALTER TABLE tasks ADD COLUMN deletedAt INTEGER NOT NULL DEFAULT 0`,
+	`CREATE INDEX tasks_deletedAt ON tasks (deletedAt) WHERE deletedAt != 0`,
+	`ALTER TABLE tasks ADD COLUMN completedAt INTEGER NOT NULL DEFAULT 0`,
+	// Tasks done before completion times were kept count from their last
+	// recorded completion, or from now.
+	`UPDATE tasks SET completedAt = COALESCE(
+            (SELECT MAX(createdAt) FROM events WHERE events.taskID = tasks.id AND events.kind = 'completed'),
+            CAST(strftime('%s', 'now') AS INTEGER))
+        WHERE taskStatus = 2`,
+	`ALTER TABLE users ADD COLUMN archiveAfter INTEGER NOT NULL DEFAULT 0`,
+	// The conditions of these partial indexes are repeated by liveTasks,
+	// archivedTasks and ArchiveCompletedTasks.
+	`CREATE INDEX tasks_live ON tasks (userID, id) WHERE taskStatus IN (1, 2) AND deletedAt = 0`,
+	`CREATE INDEX tasks_archived ON tasks (userID, id) WHERE taskStatus = 3 AND deletedAt = 0`,
+	`CREATE INDEX tasks_done ON tasks (userID, completedAt) WHERE taskStatus = 2 AND deletedAt = 0`,
+}
+
+func migrate(db *sql.DB, log *zap.Logger) error {
+	var version int
+	err := db.QueryRow("PRAGMA user_version").Scan(&version)
+	if err != nil {
+		return fmt.Errorf("migrations.go -> migrate() -> db.QueryRow(): %w", err)
+	}
+	for i := version; i < len(migrations); i++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return fmt.Errorf("migrations.go -> migrate() -> db.Begin(): %w", err)
+		}
+		_, err = tx.Exec(migrations[i])
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migrations.go -> migrate() -> tx.Exec() migration %d: %w", i+1, err)
+		}
+		_, err = tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", i+1))
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migrations.go -> migrate() -> tx.Exec(): %w", err)
+		}
+		err = tx.Commit()
+		if err != nil {
+			return fmt.Errorf("migrations.go -> migrate() -> tx.Commit(): %w", err)
+		}
+		log.Info("migration applied", zap.Int("version", i+1))
+	}
+	return nil
+}
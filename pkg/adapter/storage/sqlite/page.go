@@ -0,0 +1,61 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"telegramBot/pkg/model/task"
+)
+
+const defaultPageSize = 5
+
+func (s *Storage) SetPageSize(ctx context.Context, userID int64, pageSize int) error {
+	_, err := s.database.ExecContext(ctx, `INSERT INTO users (id, state, pageSize) VALUES (?, 0, ?)
+        ON CONFLICT (id) DO UPDATE SET pageSize = excluded.pageSize`, userID, pageSize)
+	if err != nil {
+		return fmt.Errorf("page.go -> SetPageSize() -> s.database.ExecContext(): %w", err)
+	}
+	return nil
+}
+
+func (s *Storage) GetPageSize(ctx context.Context, userID int64) (int, error) {
+	var pageSize int
+	err := s.database.QueryRowContext(ctx, "SELECT pageSize FROM users WHERE id = ?", userID).Scan(&pageSize)
+	if errors.Is(err, sql.ErrNoRows) {
+		return defaultPageSize, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("page.go -> GetPageSize() -> s.database.QueryRowContext(): %w", err)
+	}
+	return pageSize, nil
+}
+
+func (s *Storage) GetTasksPage(ctx context.Context, userID int64, limit, offset int) ([]task.Task, int, error) {
+	var total int
+	err := s.database.QueryRowContext(ctx, "SELECT COUNT(*) FROM tasks WHERE userID = ? AND "+liveTasks,
+		userID).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("page.go -> GetTasksPage() -> s.database.QueryRowContext(): %w", err)
+	}
+
+	rows, err := s.database.QueryContext(ctx, "SELECT id, taskName, taskDescription, tags FROM tasks"+
+		" WHERE userID = ? AND "+liveTasks+" ORDER BY id LIMIT ? OFFSET ?", userID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("page.go -> GetTasksPage() -> s.database.QueryContext(): %w", err)
+	}
+	defer rows.Close()
+	var tasks []task.Task
+	for rows.Next() {
+		var t task.Task
+		var tags string
+		if err := rows.Scan(&t.ID, &t.TaskName, &t.TaskDescription, &tags); err != nil {
+			return nil, 0, fmt.Errorf("page.go -> GetTasksPage() -> rows.Scan(): %w", err)
+		}
+		t.ChatId = userID
+		t.Tags = strings.Fields(tags)
+		tasks = append(tasks, t)
+	}
+	return tasks, total, nil
+}
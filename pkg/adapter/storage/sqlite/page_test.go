@@ -0,0 +1,77 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"telegramBot/pkg/model/task"
+	"telegramBot/pkg/model/task/status"
+	"testing"
+)
+
+func TestGetTasksPage(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStorage(t)
+	var tasks []task.Task
+	for i := 1; i <= 7; i++ {
+		tasks = append(tasks, task.Task{TaskName: fmt.Sprintf("Task %d", i)})
+	}
+	tasks = append(tasks, task.Task{TaskName: "Archived", Status: status.Archived},
+		task.Task{TaskName: "Someone else's", ChatId: testUser + 1})
+	taskIDs := saveTasks(t, s, tasks...)
+	err := s.DeleteTaskByID(ctx, testUser, taskIDs[6])
+	if err != nil {
+		t.Fatalf("DeleteTaskByID() error = %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		limit, offset int
+		want          []string
+	}{
+		{"first page", 3, 0, []string{"Task 1", "Task 2", "Task 3"}},
+		{"last page", 3, 3, []string{"Task 4", "Task 5", "Task 6"}},
+		{"past the end", 3, 6, nil},
+		{"all", 10, 0, []string{"Task 1", "Task 2", "Task 3", "Task 4", "Task 5", "Task 6"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, total, err := s.GetTasksPage(ctx, testUser, tt.limit, tt.offset)
+			if err != nil {
+				t.Fatalf("GetTasksPage() error = %v", err)
+			}
+			var names []string
+			for _, task := range got {
+				names = append(names, task.TaskName)
+			}
+			if !reflect.DeepEqual(names, tt.want) || total != 6 {
+				t.Errorf("GetTasksPage() = %q of %d, want %q of 6", names, total, tt.want)
+			}
+		})
+	}
+}
+
+func TestPageSize(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStorage(t)
+	got, err := s.GetPageSize(ctx, testUser)
+	if err != nil || got != defaultPageSize {
+		t.Fatalf("GetPageSize() of a new user = %d, %v, want %d", got, err, defaultPageSize)
+	}
+	err = s.SetUserState(ctx, testUser, 1)
+	if err != nil {
+		t.Fatalf("SetUserState() error = %v", err)
+	}
+	err = s.SetPageSize(ctx, testUser, 10)
+	if err != nil {
+		t.Fatalf("SetPageSize() error = %v", err)
+	}
+	got, err = s.GetPageSize(ctx, testUser)
+	if err != nil || got != 10 {
+		t.Errorf("GetPageSize() = %d, %v, want 10", got, err)
+	}
+	state, err := s.GetUserState(ctx, testUser)
+	if err != nil || state != 1 {
+		t.Errorf("GetUserState() = %d, %v, want the state kept", state, err)
+	}
+}
@@ -0,0 +1,51 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"telegramBot/pkg/adapter/todobot"
+	"time"
+)
+
+// AddAppPassword stores the hash of a new app password; the password itself
+// is never stored.
+func (s *Storage) AddAppPassword(ctx context.Context, userID int64, hash string) error {
+	_, err := s.database.ExecContext(ctx, "INSERT INTO appPasswords (userID, hash, createdAt) VALUES (?, ?, ?)",
+		userID, hash, time.Now().Unix())
+	if err != nil {
+		return fmt.Errorf("password.go -> AddAppPassword() -> s.database.ExecContext(): %w", err)
+	}
+	return nil
+}
+
+func (s *Storage) CountAppPasswords(ctx context.Context, userID int64) (int, error) {
+	var count int
+	err := s.database.QueryRowContext(ctx, "SELECT COUNT(*) FROM appPasswords WHERE userID = ?", userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("password.go -> CountAppPasswords() -> s.database.QueryRowContext(): %w", err)
+	}
+	return count, nil
+}
+
+func (s *Storage) DeleteAppPasswords(ctx context.Context, userID int64) error {
+	_, err := s.database.ExecContext(ctx, "DELETE FROM appPasswords WHERE userID = ?", userID)
+	if err != nil {
+		return fmt.Errorf("password.go -> DeleteAppPasswords() -> s.database.ExecContext(): %w", err)
+	}
+	return nil
+}
+
+// GetAppPasswordUser returns the owner of the app password with hash.
+func (s *Storage) GetAppPasswordUser(ctx context.Context, hash string) (int64, error) {
+	var userID int64
+	err := s.database.QueryRowContext(ctx, "SELECT userID FROM appPasswords WHERE hash = ?", hash).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("password.go -> GetAppPasswordUser(): %w", todobot.ErrInvalidAppPassword)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("password.go -> GetAppPasswordUser() -> s.database.QueryRowContext(): %w", err)
+	}
+	return userID, nil
+}
@@ -0,0 +1,77 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"telegramBot/pkg/model/profile"
+)
+
+// profileColumns are the columns scanProfile reads, in its order.
+const profileColumns = `id, timezone, language, dateFormat, defaultList, quietFrom, quietTo, pageSize,
+        digestEnabled, digestAt, digestSent, archiveAfter`
+
+func scanProfile(row scanner) (profile.Profile, error) {
+	var p profile.Profile
+	err := row.Scan(&p.UserID, &p.Timezone, &p.Language, &p.DateFormat, &p.DefaultList, &p.QuietHours.From,
+		&p.QuietHours.To, &p.PageSize, &p.DigestEnabled, &p.DigestAt, &p.DigestSent,
+		&p.ArchiveAfter)
+	return p, err
+}
+
+// GetProfile returns the defaults for users who have no row yet.
+func (s *Storage) GetProfile(ctx context.Context, userID int64) (profile.Profile, error) {
+	p, err := scanProfile(s.database.QueryRowContext(ctx, "SELECT "+profileColumns+" FROM users WHERE id = ?", userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return profile.Default(userID), nil
+	}
+	if err != nil {
+		return profile.Profile{}, fmt.Errorf("profile.go -> GetProfile() -> scanProfile(): %w", err)
+	}
+	return p, nil
+}
+
+// SaveProfile writes the user's preferences. DigestSent is left alone, it
+// belongs to SetDigestSent.
+func (s *Storage) SaveProfile(ctx context.Context, p profile.Profile) error {
+	_, err := s.database.ExecContext(ctx, `INSERT INTO users
+        (id, state, timezone, language, dateFormat, defaultList, quietFrom, quietTo, pageSize, digestEnabled, digestAt,
+        archiveAfter)
+        VALUES (?, 0, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+        ON CONFLICT (id) DO UPDATE SET timezone = excluded.timezone, language = excluded.language,
+        dateFormat = excluded.dateFormat, defaultList = excluded.defaultList, quietFrom = excluded.quietFrom,
+        quietTo = excluded.quietTo, pageSize = excluded.pageSize, digestEnabled = excluded.digestEnabled,
+        digestAt = excluded.digestAt, archiveAfter = excluded.archiveAfter`,
+		p.UserID, p.Timezone, p.Language, p.DateFormat, p.DefaultList, p.QuietHours.From, p.QuietHours.To, p.PageSize,
+		p.DigestEnabled, p.DigestAt, p.ArchiveAfter)
+	if err != nil {
+		return fmt.Errorf("profile.go -> SaveProfile() -> s.database.ExecContext(): %w", err)
+	}
+	return nil
+}
+
+func (s *Storage) ListDigestProfiles(ctx context.Context) ([]profile.Profile, error) {
+	rows, err := s.database.QueryContext(ctx, "SELECT "+profileColumns+" FROM users WHERE digestEnabled = 1")
+	if err != nil {
+		return nil, fmt.Errorf("profile.go -> ListDigestProfiles() -> s.database.QueryContext(): %w", err)
+	}
+	defer rows.Close()
+	var profiles []profile.Profile
+	for rows.Next() {
+		p, err := scanProfile(rows)
+		if err != nil {
+			return nil, fmt.Errorf("profile.go -> ListDigestProfiles() -> scanProfile(): %w", err)
+		}
+		profiles = append(profiles, p)
+	}
+	return profiles, rows.Err()
+}
+
+func (s *Storage) SetDigestSent(ctx context.Context, userID int64, day string) error {
+	_, err := s.database.ExecContext(ctx, "UPDATE users SET digestSent = ? WHERE id = ?", day, userID)
+	if err != nil {
+		return fmt.Errorf("profile.go -> SetDigestSent() -> s.database.ExecContext(): %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"go.uber.org/zap"
+	"strings"
+	"telegramBot/pkg/model/task"
+	"unicode"
+)
+
+var searchTriggers = []string{
+	`CREATE TRIGGER IF NOT EXISTS tasks_fts_insert AFTER INSERT ON tasks BEGIN
+        INSERT INTO tasks_fts (rowid, taskName, taskDescription, tags)
+        VALUES (new.id, new.taskName, new.taskDescription, new.tags);
+    END`,
+	`CREATE TRIGGER IF NOT EXISTS tasks_fts_delete AFTER DELETE ON tasks BEGIN
+        INSERT INTO tasks_fts (tasks_fts, rowid, taskName, taskDescription, tags)
+        VALUES ('delete', old.id, old.taskName, old.taskDescription, old.tags);
+    END`,
+	`CREATE TRIGGER IF NOT EXISTS tasks_fts_update AFTER UPDATE ON tasks BEGIN
+        INSERT INTO tasks_fts (tasks_fts, rowid, taskName, taskDescription, tags)
+        VALUES ('delete', old.id, old.taskName, old.taskDescription, old.tags);
+        INSERT INTO tasks_fts (rowid, taskName, taskDescription, tags)
+        VALUES (new.id, new.taskName, new.taskDescription, new.tags);
+    END`,
+}
+
+// setupSearch maintains the tasks_fts index when the driver is built with
+// FTS5 (go build -tags sqlite_fts5, as the Makefile and Dockerfile do).
+// Without it the sync triggers are dropped, so a database indexed by an FTS5
+// build stays writable, and SearchTasks falls back to plain LIKE matching;
+// that is logged as an error, since it is a broken build rather than a mode.
+func setupSearch(db *sql.DB, log *zap.Logger) (bool, error) {
+	var enabled bool
+	err := db.QueryRow("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&enabled)
+	if err != nil {
+		return false, fmt.Errorf("search.go -> setupSearch() -> db.QueryRow(): %w", err)
+	}
+	if !enabled {
+		for _, trigger := range []string{"tasks_fts_insert", "tasks_fts_delete", "tasks_fts_update"} {
+			_, err = db.Exec("DROP TRIGGER IF EXISTS " + trigger)
+			if err != nil {
+				return false, fmt.Errorf("search.go -> setupSearch() -> db.Exec(): %w", err)
+			}
+		}
+		log.Error("SQLite is built without FTS5, search falls back to LIKE; build with -tags sqlite_fts5")
+		return false, nil
+	}
+
+	var triggers int
+	err = db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'trigger' AND name LIKE 'tasks_fts_%'").
+		Scan(&triggers)
+	if err != nil {
+		return false, fmt.Errorf("search.go -> setupSearch() -> db.QueryRow(): %w", err)
+	}
+	_, err = db.Exec(`CREATE VIRTUAL TABLE IF NOT EXISTS tasks_fts USING fts5 (
+        taskName, taskDescription, tags,
+        content = 'tasks', content_rowid = 'id', tokenize = 'unicode61 remove_diacritics 2'
+    )`)
+	if err != nil {
+		return false, fmt.Errorf("search.go -> setupSearch() -> db.Exec(): %w", err)
+	}
+	for _, trigger := range searchTriggers {
+		_, err = db.Exec(trigger)
+		if err != nil {
+			return false, fmt.Errorf("search.go -> setupSearch() -> db.Exec(): %w", err)
+		}
+	}
+	// Without all of its triggers the index may have missed writes.
+	if triggers < len(searchTriggers) {
+		_, err = db.Exec("INSERT INTO tasks_fts (tasks_fts) VALUES ('rebuild')")
+		if err != nil {
+			return false, fmt.Errorf("search.go -> setupSearch() -> db.Exec(): %w", err)
+		}
+		log.Info("search index rebuilt")
+	}
+	return true, nil
+}
+
+func searchTerms(query string) []string {
+	return strings.FieldsFunc(query, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-'
+	})
+}
+
+func (s *Storage) SearchTasks(ctx context.Context, userID int64, query string,
+	limit, offset int) (task.SearchResult, error) {
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return task.SearchResult{}, nil
+	}
+	return s.search(ctx, liveTasks, userID, terms, limit, offset)
+}
+
+// SearchArchive searches the user's archived tasks. An empty query matches
+// all of them, newest first.
+func (s *Storage) SearchArchive(ctx context.Context, userID int64, query string,
+	limit, offset int) (task.SearchResult, error) {
+	return s.search(ctx, archivedTasks, userID, searchTerms(query), limit, offset)
+}
+
+// search matches terms among the user's tasks that scope selects.
+func (s *Storage) search(ctx context.Context, scope string, userID int64, terms []string,
+	limit, offset int) (task.SearchResult, error) {
+	if !s.fts || len(terms) == 0 {
+		return s.searchTasksLike(ctx, scope, userID, terms, limit, offset)
+	}
+
+	// Every term is quoted to neutralise FTS5 syntax and made a prefix query.
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = `"` + term + `"*`
+	}
+	matchQuery := strings.Join(quoted, " ")
+
+	var result task.SearchResult
+	err := s.database.QueryRowContext(ctx, `SELECT COUNT(*) FROM tasks_fts JOIN tasks ON tasks.id = tasks_fts.rowid
+        WHERE tasks_fts MATCH ? AND tasks.userID = ? AND `+scope, matchQuery, userID).Scan(&result.Total)
+	if err != nil {
+		return task.SearchResult{}, fmt.Errorf("search.go -> search() -> s.database.QueryRowContext(): %w", err)
+	}
+
+	rows, err := s.database.QueryContext(ctx, `SELECT tasks.id, tasks.taskName, tasks.taskDescription, tasks.tags,
+            snippet(tasks_fts, -1, ?, ?, '…', 12)
+        FROM tasks_fts JOIN tasks ON tasks.id = tasks_fts.rowid
+        WHERE tasks_fts MATCH ? AND tasks.userID = ? AND `+scope+`
+        ORDER BY bm25(tasks_fts, 10.0, 1.0, 5.0)
+        LIMIT ? OFFSET ?`,
+		task.HighlightStart, task.HighlightEnd, matchQuery, userID, limit, offset)
+	if err != nil {
+		return task.SearchResult{}, fmt.Errorf("search.go -> search() -> s.database.QueryContext(): %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var match task.Match
+		var tags string
+		err := rows.Scan(&match.Task.ID, &match.Task.TaskName, &match.Task.TaskDescription, &tags, &match.Snippet)
+		if err != nil {
+			return task.SearchResult{}, fmt.Errorf("search.go -> search() -> rows.Scan(): %w", err)
+		}
+		match.Task.ChatId = userID
+		match.Task.Tags = strings.Fields(tags)
+		result.Matches = append(result.Matches, match)
+	}
+	return result, nil
+}
+
+// likeEscaper makes the LIKE wildcards in a search term match themselves.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func (s *Storage) searchTasksLike(ctx context.Context, scope string, userID int64, terms []string,
+	limit, offset int) (task.SearchResult, error) {
+	where := "userID = ? AND " + scope
+	args := []any{userID}
+	for _, term := range terms {
+		where += ` AND (taskName LIKE ? ESCAPE '\' OR taskDescription LIKE ? ESCAPE '\' OR tags LIKE ? ESCAPE '\')`
+		pattern := "%" + likeEscaper.Replace(term) + "%"
+		args = append(args, pattern, pattern, pattern)
+	}
+
+	var result task.SearchResult
+	err := s.database.QueryRowContext(ctx, "SELECT COUNT(*) FROM tasks WHERE "+where, args...).Scan(&result.Total)
+	if err != nil {
+		return task.SearchResult{}, fmt.Errorf(
+			"search.go -> searchTasksLike() -> s.database.QueryRowContext(): %w", err)
+	}
+
+	rows, err := s.database.QueryContext(ctx, "SELECT id, taskName, taskDescription, tags FROM tasks WHERE "+where+
+		" ORDER BY id DESC LIMIT ? OFFSET ?", append(args, limit, offset)...)
+	if err != nil {
+		return task.SearchResult{}, fmt.Errorf(
+			"search.go -> searchTasksLike() -> s.database.QueryContext(): %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var match task.Match
+		var tags string
+		err := rows.Scan(&match.Task.ID, &match.Task.TaskName, &match.Task.TaskDescription, &tags)
+		if err != nil {
+			return task.SearchResult{}, fmt.Errorf("search.go -> searchTasksLike() -> rows.Scan(): %w", err)
+		}
+		match.Task.ChatId = userID
+		match.Task.Tags = strings.Fields(tags)
+		match.Snippet = highlight(match.Task.TaskDescription, terms)
+		result.Matches = append(result.Matches, match)
+	}
+	return result, nil
+}
+
+func highlight(text string, terms []string) string {
+	lower := strings.ToLower(text)
+	if len(lower) != len(text) {
+		return text
+	}
+	var builder strings.Builder
+	for i := 0; i < len(text); {
+		matched := 0
+		for _, term := range terms {
+			if strings.HasPrefix(lower[i:], strings.ToLower(term)) && len(term) > matched {
+				matched = len(term)
+			}
+		}
+		if matched > 0 {
+			builder.WriteString(task.HighlightStart + text[i:i+matched] + task.HighlightEnd)
+			i += matched
+			continue
+		}
+		builder.WriteByte(text[i])
+		i++
+	}
+	return builder.String()
+}
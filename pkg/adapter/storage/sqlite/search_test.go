@@ -0,0 +1,155 @@
+package sqlite
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"path/filepath"
+	"reflect"
+	"telegramBot/internal/config"
+	"telegramBot/pkg/model/task"
+	"telegramBot/pkg/model/task/status"
+	"testing"
+)
+
+const testUser = 42
+
+func newTestStorage(t *testing.T) *Storage {
+	t.Helper()
+	return New(config.Config{DatabasePath: filepath.Join(t.TempDir(), "tasks.db")}, zap.NewNop())
+}
+
+// saveTasks saves tasks for testUser unless they have an owner and returns
+// their IDs.
+func saveTasks(t *testing.T, s *Storage, tasks ...task.Task) []int64 {
+	t.Helper()
+	for i := range tasks {
+		if tasks[i].ChatId == 0 {
+			tasks[i].ChatId = testUser
+		}
+		if tasks[i].Status == status.Creating {
+			tasks[i].Status = status.Created
+		}
+	}
+	taskIDs, err := s.SaveTasks(context.Background(), tasks)
+	if err != nil {
+		t.Fatalf("SaveTasks() error = %v", err)
+	}
+	return taskIDs
+}
+
+func matchNames(result task.SearchResult) []string {
+	var names []string
+	for _, match := range result.Matches {
+		names = append(names, match.Task.TaskName)
+	}
+	return names
+}
+
+func TestSearchTasks(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStorage(t)
+	taskIDs := saveTasks(t, s,
+		task.Task{TaskName: "Pay rent", TaskDescription: "Before the 5th", Tags: []string{"bills"}},
+		task.Task{TaskName: "Renew passport"},
+		task.Task{TaskName: "Fix a_b"},
+		task.Task{TaskName: "Fix axb"},
+		task.Task{TaskName: "100% done"},
+		task.Task{TaskName: "Paid rent", Status: status.Archived},
+		task.Task{TaskName: "Rent a car"},
+		task.Task{TaskName: "Rent a bike", ChatId: testUser + 1},
+	)
+	err := s.DeleteTaskByID(ctx, testUser, taskIDs[6])
+	if err != nil {
+		t.Fatalf("DeleteTaskByID() error = %v", err)
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"rent", []string{"Pay rent"}},
+		{"RENT bills", []string{"Pay rent"}},
+		{"5th", []string{"Pay rent"}},
+		{"a_b", []string{"Fix a_b"}},
+		{"100%", []string{"100% done"}},
+		{"nothing", nil},
+		{"?!", nil},
+	}
+	modes := []struct {
+		name string
+		fts  bool
+	}{
+		{"fts5", true},
+		{"like", false},
+	}
+	for _, mode := range modes {
+		t.Run(mode.name, func(t *testing.T) {
+			if mode.fts && !s.fts {
+				t.Skip("SQLite is built without FTS5")
+			}
+			s.fts = mode.fts
+			for _, tt := range tests {
+				result, err := s.SearchTasks(ctx, testUser, tt.query, 10, 0)
+				if err != nil {
+					t.Fatalf("SearchTasks(%q) error = %v", tt.query, err)
+				}
+				if got := matchNames(result); !reflect.DeepEqual(got, tt.want) || result.Total != len(tt.want) {
+					t.Errorf("SearchTasks(%q) = %q of %d, want %q", tt.query, got, result.Total, tt.want)
+				}
+			}
+
+			result, err := s.SearchArchive(ctx, testUser, "rent", 10, 0)
+			if err != nil {
+				t.Fatalf("SearchArchive() error = %v", err)
+			}
+			if got := matchNames(result); !reflect.DeepEqual(got, []string{"Paid rent"}) {
+				t.Errorf("SearchArchive() = %q, want the archived task", got)
+			}
+		})
+	}
+}
+
+func TestSearchTasksPrefixAndPages(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStorage(t)
+	saveTasks(t, s, task.Task{TaskName: "Call mum"}, task.Task{TaskName: "Call dad"},
+		task.Task{TaskName: "Callback the bank"})
+
+	for _, fts := range []bool{true, false} {
+		if fts && !s.fts {
+			continue
+		}
+		s.fts = fts
+		first, err := s.SearchTasks(ctx, testUser, "cal", 2, 0)
+		if err != nil {
+			t.Fatalf("SearchTasks() error = %v", err)
+		}
+		rest, err := s.SearchTasks(ctx, testUser, "cal", 2, 2)
+		if err != nil {
+			t.Fatalf("SearchTasks() error = %v", err)
+		}
+		if first.Total != 3 || len(first.Matches) != 2 || len(rest.Matches) != 1 {
+			t.Errorf("fts5 %t: pages = %q, %q of %d, want 2 and 1 of 3", fts, matchNames(first), matchNames(rest),
+				first.Total)
+		}
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		text  string
+		terms []string
+		want  string
+	}{
+		{"Pay the rent", []string{"rent"}, "Pay the " + task.HighlightStart + "rent" + task.HighlightEnd},
+		{"Rent rental", []string{"rent", "rental"},
+			task.HighlightStart + "Rent" + task.HighlightEnd + " " + task.HighlightStart + "rental" + task.HighlightEnd},
+		{"nothing here", []string{"rent"}, "nothing here"},
+		{"", []string{"rent"}, ""},
+	}
+	for _, tt := range tests {
+		if got := highlight(tt.text, tt.terms); got != tt.want {
+			t.Errorf("highlight(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
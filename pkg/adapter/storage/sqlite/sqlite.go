@@ -1,23 +1,41 @@
 package sqlite
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	_ "github.com/mattn/go-sqlite3"
 	"go.uber.org/zap"
+	"strings"
+	"telegramBot/internal/config"
+	"telegramBot/pkg/adapter/todobot"
+	"telegramBot/pkg/model/state/user"
 	"telegramBot/pkg/model/task"
 	"telegramBot/pkg/model/task/status"
+	"time"
+)
+
+// liveTasks selects the tasks of normal listings: neither drafts, nor
+// archived, nor in the trash. archivedTasks selects the archive. SQLite uses
+// a partial index only for queries that contain its condition, so these
+// repeat the conditions of tasks_live and tasks_archived word for word, with
+// status.Created, status.Done and status.Archived as numbers.
+const (
+	liveTasks     = "taskStatus IN (1, 2) AND deletedAt = 0"
+	archivedTasks = "taskStatus = 3 AND deletedAt = 0"
 )
 
 type Storage struct {
 	database *sql.DB
+	fts      bool
+	log      *zap.Logger
 }
 
-func New() *Storage {
-	db, err := sql.Open("sqlite3", "./database.db")
+func New(cfg config.Config, log *zap.Logger) *Storage {
+	db, err := sql.Open("sqlite3", dsn(cfg.DatabasePath))
 	if err != nil {
-		zap.L().Fatal("New() -> sql.Open()", zap.Error(err))
+		log.Fatal("New() -> sql.Open()", zap.Error(err))
 	}
 
 	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS tasks (
@@ -28,186 +46,371 @@ func New() *Storage {
         taskStatus INTEGER
     )`)
 	if err != nil {
-		zap.L().Fatal("New() -> sql.Open()", zap.Error(err))
+		log.Fatal("New() -> sql.Open()", zap.Error(err))
 	}
 	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS users (
         id INTEGER PRIMARY KEY ,
         state INTEGER
     )`)
 	if err != nil {
-		zap.L().Fatal("New() -> sql.Open()", zap.Error(err))
+		log.Fatal("New() -> sql.Open()", zap.Error(err))
+	}
+	err = migrate(db, log)
+	if err != nil {
+		log.Fatal("New() -> migrate()", zap.Error(err))
+	}
+	fts, err := setupSearch(db, log)
+	if err != nil {
+		log.Fatal("New() -> setupSearch()", zap.Error(err))
 	}
+	log.Info("storage opened", zap.Bool("fts5", fts))
 	return &Storage{
 		database: db,
+		fts:      fts,
+		log:      log,
 	}
 }
 
-func (s *Storage) SetUserState(userID int64, state int) error {
+// dsn opens path in WAL mode, so readers do not block the writer, with a
+// busy timeout and immediate write transactions, so the bot, CalDAV and the
+// background jobs wait for each other instead of failing with "database is
+// locked".
+func dsn(path string) string {
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	return "file:" + strings.TrimPrefix(path, "file:") + separator +
+		"_journal_mode=WAL&_busy_timeout=5000&_txlock=immediate"
+}
+
+func (s *Storage) SetUserState(ctx context.Context, userID int64, state int) error {
 	var count int
-	err := s.database.QueryRow("SELECT COUNT(*) FROM users WHERE id = ?", userID).Scan(&count)
+	err := s.database.QueryRowContext(ctx, "SELECT COUNT(*) FROM users WHERE id = ?", userID).Scan(&count)
 	if err != nil {
-		return errors.New(fmt.Sprintf("Storage.go -> SetUserState() -> s.database.QueryRow() %s", err.Error()))
+		return fmt.Errorf("Storage.go -> SetUserState() -> s.database.QueryRowContext(): %w", err)
 	}
 
 	if count > 0 {
-		_, err = s.database.Exec("UPDATE users SET state = ? WHERE id = ?", state, userID)
+		_, err = s.database.ExecContext(ctx, "UPDATE users SET state = ? WHERE id = ?", state, userID)
 		if err != nil {
-			return errors.New(fmt.Sprintf("Storage.go -> SetUserState() -> s.database.Exec() %s", err.Error()))
+			return fmt.Errorf("Storage.go -> SetUserState() -> s.database.ExecContext(): %w", err)
 		}
 	} else {
-		_, err := s.database.Exec("INSERT INTO users (id, state) VALUES (?, ?)", userID, state)
+		_, err := s.database.ExecContext(ctx, "INSERT INTO users (id, state) VALUES (?, ?)", userID, state)
 		if err != nil {
-			return errors.New(fmt.Sprintf("Storage.go -> SetUserState() -> s.database.Exec() %s", err.Error()))
+			return fmt.Errorf("Storage.go -> SetUserState() -> s.database.ExecContext(): %w", err)
 		}
 	}
 	return nil
 }
 
-func (s *Storage) GetUserState(userID int64) (int, error) {
-	rows, err := s.database.Query("SELECT state FROM users WHERE id = ?", userID)
+func (s *Storage) GetUserState(ctx context.Context, userID int64) (int, error) {
+	rows, err := s.database.QueryContext(ctx, "SELECT state FROM users WHERE id = ?", userID)
 	if err != nil {
-		return 0, errors.New(fmt.Sprintf("Storage.go -> GetUserState() -> s.database.Query() %s", err.Error()))
+		return 0, fmt.Errorf("Storage.go -> GetUserState() -> s.database.QueryContext(): %w", err)
 	}
 	defer rows.Close()
 	var state int
 	for rows.Next() {
 		err := rows.Scan(&state)
 		if err != nil {
-			return 0, errors.New(fmt.Sprintf("Storage.go -> GetUserState() -> rows.Scan() %s", err.Error()))
+			return 0, fmt.Errorf("Storage.go -> GetUserState() -> rows.Scan(): %w", err)
 		}
 	}
 	return state, nil
 }
 
-func (s *Storage) CreateNewTask(userID int64) (taskID int64, err error) {
-	result, err := s.database.Exec("INSERT INTO tasks (userID) VALUES (?)", userID)
+func (s *Storage) CountActiveConversations(ctx context.Context) (int, error) {
+	var count int
+	err := s.database.QueryRowContext(ctx, "SELECT COUNT(*) FROM users WHERE state != ?", user.Default).Scan(&count)
 	if err != nil {
-		return 0, errors.New(fmt.Sprintf("Storage.go -> SaveTask() -> s.database.Exec() %s", err.Error()))
+		return 0, fmt.Errorf("Storage.go -> CountActiveConversations() -> s.database.QueryRowContext(): %w", err)
 	}
+	return count, nil
+}
 
-	taskID, err = result.LastInsertId()
+func (s *Storage) SaveTask(ctx context.Context, newTask task.Task) (taskID int64, err error) {
+	tx, err := s.database.BeginTx(ctx, nil)
 	if err != nil {
-		return 0, errors.New(fmt.Sprintf("Storage.go -> CreateNewTask() -> result.LastInsertId() %s", err.Error()))
+		return 0, fmt.Errorf("Storage.go -> SaveTask() -> s.database.BeginTx(): %w", err)
 	}
-	return taskID, nil
-}
+	defer tx.Rollback()
 
-func (s *Storage) SetTaskName(taskID int64, taskName string) (err error) {
-	_, err = s.database.Exec("UPDATE tasks SET taskName = ? WHERE id = ?", taskName, taskID)
+	newTask.Status = status.Created
+	newTask.CreatedAt = time.Time{}
+	taskID, err = insertTask(ctx, tx, newTask)
 	if err != nil {
-		return errors.New(fmt.Sprintf("Storage.go -> SetTaskName() -> s.database.Exec() %s", err.Error()))
+		return 0, fmt.Errorf("Storage.go -> SaveTask() -> insertTask(): %w", err)
 	}
-	return nil
+	err = tx.Commit()
+	if err != nil {
+		return 0, fmt.Errorf("Storage.go -> SaveTask() -> tx.Commit(): %w", err)
+	}
+	return taskID, nil
 }
 
-func (s *Storage) GetTaskName(taskID int64) (string, error) {
-	rows, err := s.database.Query("SELECT taskName FROM tasks WHERE id = ?", taskID)
+// SaveTasks saves all of newTasks or, on error, none of them. Unlike SaveTask
+// it keeps their status, Done included, and their creation time when set.
+func (s *Storage) SaveTasks(ctx context.Context, newTasks []task.Task) (taskIDs []int64, err error) {
+	tx, err := s.database.BeginTx(ctx, nil)
 	if err != nil {
-		return "", errors.New(fmt.Sprintf("Storage.go -> GetTaskName() -> s.database.Query() %s", err.Error()))
+		return nil, fmt.Errorf("Storage.go -> SaveTasks() -> s.database.BeginTx(): %w", err)
 	}
-	defer rows.Close()
-	var taskName string
-	for rows.Next() {
-		err := rows.Scan(&taskName)
+	defer tx.Rollback()
+
+	taskIDs = make([]int64, 0, len(newTasks))
+	for i, newTask := range newTasks {
+		if newTask.Status == status.Creating {
+			newTask.Status = status.Created
+		}
+		taskID, err := insertTask(ctx, tx, newTask)
 		if err != nil {
-			return "", errors.New(fmt.Sprintf("Storage.go -> GetTaskName() -> rows.Scan() %s", err.Error()))
+			return nil, fmt.Errorf("Storage.go -> SaveTasks() -> insertTask() task %d: %w", i, err)
 		}
+		taskIDs = append(taskIDs, taskID)
 	}
-	return taskName, nil
-}
-
-func (s *Storage) SetTaskDescription(taskID int64, taskDescription string) error {
-	_, err := s.database.Exec("UPDATE tasks SET taskDescription = ? WHERE id = ?", taskDescription, taskID)
+	err = tx.Commit()
 	if err != nil {
-		return errors.New(fmt.Sprintf("Storage.go -> SetTaskDescription() -> s.database.Exec() %s", err.Error()))
+		return nil, fmt.Errorf("Storage.go -> SaveTasks() -> tx.Commit(): %w", err)
 	}
-	return nil
+	return taskIDs, nil
 }
 
-func (s *Storage) GetTaskDescription(taskID int64) (string, error) {
-	rows, err := s.database.Query("SELECT taskDescription FROM tasks WHERE id = ?", taskID)
+// insertTask writes newTask with its status, stamped with the current time
+// unless it has a creation time already. A done task counts as completed now.
+func insertTask(ctx context.Context, tx *sql.Tx, newTask task.Task) (int64, error) {
+	createdAt := newTask.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	var completedAt int64
+	if newTask.Status == status.Done {
+		completedAt = time.Now().Unix()
+	}
+	result, err := tx.ExecContext(ctx, `INSERT INTO tasks
+        (userID, taskName, taskDescription, taskStatus, priority, repeat, createdAt, tags, list, due, uid,
+        resource, completedAt)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, newTask.ChatId, newTask.TaskName, newTask.TaskDescription,
+		newTask.Status, newTask.Priority, newTask.Repeat, createdAt.Unix(), strings.Join(newTask.Tags, " "),
+		newTask.List, formatDue(newTask.Due), newTask.UID, newTask.Resource, completedAt)
 	if err != nil {
-		return "", errors.New(fmt.Sprintf("Storage.go -> GetTaskDescription() -> s.database.Query() %s",
-			err.Error()))
+		return 0, fmt.Errorf("tx.ExecContext(): %w", err)
 	}
-	defer rows.Close()
-	var taskDescription string
-	for rows.Next() {
-		err := rows.Scan(&taskDescription)
+	taskID, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("result.LastInsertId(): %w", err)
+	}
+	for _, a := range newTask.Attachments {
+		a.TaskID = taskID
+		err := insertAttachment(ctx, tx, a)
 		if err != nil {
-			return "", errors.New(fmt.Sprintf("Storage.go -> GetTaskDescription() -> rows.Scan() %s", err.Error()))
+			return 0, fmt.Errorf("insertAttachment(): %w", err)
 		}
 	}
-	return taskDescription, nil
+	return taskID, nil
+}
+
+// UpdateTask overwrites every field of a task the user owns but its owner,
+// creation and completion times.
+func (s *Storage) UpdateTask(ctx context.Context, userID int64, t task.Task) error {
+	err := s.checkOwner(ctx, userID, int64(t.ID))
+	if err != nil {
+		return fmt.Errorf("Storage.go -> UpdateTask() -> s.checkOwner(): %w", err)
+	}
+	args := append(completedAtArgs(t.Status), t.TaskName, t.TaskDescription, t.Status, t.Priority, t.Repeat,
+		strings.Join(t.Tags, " "), t.List, formatDue(t.Due), t.UID, t.Resource, t.ID)
+	_, err = s.database.ExecContext(ctx, "UPDATE tasks SET "+setCompletedAt+`, taskName = ?, taskDescription = ?,
+        taskStatus = ?, priority = ?, repeat = ?, tags = ?, list = ?, due = ?, uid = ?, resource = ? WHERE id = ?`,
+		args...)
+	if err != nil {
+		return fmt.Errorf("Storage.go -> UpdateTask() -> s.database.ExecContext(): %w", err)
+	}
+	return nil
 }
 
-func (s *Storage) SetTaskStatus(taskID int64, taskStatus int) error {
-	_, err := s.database.Exec("UPDATE tasks SET taskStatus = ? WHERE id = ?", taskStatus, taskID)
+// SetTaskStatus sets the status of a task the user owns.
+func (s *Storage) SetTaskStatus(ctx context.Context, userID, taskID int64, taskStatus int) error {
+	err := s.checkOwner(ctx, userID, taskID)
 	if err != nil {
-		return errors.New(fmt.Sprintf("Storage.go -> SetTaskStatus() -> s.database.Exec() %s", err.Error()))
+		return fmt.Errorf("Storage.go -> SetTaskStatus() -> s.checkOwner(): %w", err)
+	}
+	_, err = s.database.ExecContext(ctx, "UPDATE tasks SET "+setCompletedAt+", taskStatus = ? WHERE id = ?",
+		append(completedAtArgs(taskStatus), taskStatus, taskID)...)
+	if err != nil {
+		return fmt.Errorf("Storage.go -> SetTaskStatus() -> s.database.ExecContext(): %w", err)
 	}
 	return nil
 }
 
-func (s *Storage) GetTaskIDInCreationStatus(userID int64) (int64, error) {
-	rows, err := s.database.Query("SELECT id FROM tasks WHERE userID = ? AND taskStatus = ?", userID, status.Creating)
+// DeleteTask moves the user's only task named taskName to the trash and
+// returns its ID.
+func (s *Storage) DeleteTask(ctx context.Context, userID int64, taskName string) (int64, error) {
+	var taskIDs []int64
+	rows, err := s.database.QueryContext(ctx,
+		"SELECT id FROM tasks WHERE userID = ? AND taskName = ? AND "+liveTasks+" LIMIT 2", userID, taskName)
 	if err != nil {
-		return 0, errors.New(fmt.Sprintf("Storage.go -> GetTaskIDInCreationStatus() -> s.database.Query() %s",
-			err.Error()))
+		return 0, fmt.Errorf("Storage.go -> DeleteTask() -> s.database.QueryContext(): %w", err)
 	}
 	defer rows.Close()
-	var taskID int64
 	for rows.Next() {
+		var taskID int64
 		err := rows.Scan(&taskID)
 		if err != nil {
-			return 0, errors.New(fmt.Sprintf("Storage.go -> GetTaskIDInCreationStatus() -> rows.Scan() %s",
-				err.Error()))
+			return 0, fmt.Errorf("Storage.go -> DeleteTask() -> rows.Scan(): %w", err)
 		}
+		taskIDs = append(taskIDs, taskID)
 	}
-	return taskID, nil
+	rows.Close()
+	if len(taskIDs) == 0 {
+		return 0, fmt.Errorf("Storage.go -> DeleteTask() %q: %w", taskName, todobot.ErrTaskNotFound)
+	}
+	if len(taskIDs) > 1 {
+		return 0, fmt.Errorf("Storage.go -> DeleteTask() %q: %w", taskName, todobot.ErrAmbiguousName)
+	}
+	_, err = s.database.ExecContext(ctx, "UPDATE tasks SET deletedAt = ? WHERE id = ?", time.Now().Unix(), taskIDs[0])
+	if err != nil {
+		return 0, fmt.Errorf("Storage.go -> DeleteTask() -> s.database.ExecContext(): %w", err)
+	}
+	return taskIDs[0], nil
 }
 
-func (s *Storage) DeleteTask(taskName string) (string, error) {
-	var count int
-	err := s.database.QueryRow("SELECT COUNT(*) FROM tasks WHERE taskName = ?", taskName).Scan(&count)
+// checkOwner returns ErrTaskNotFound or ErrNotOwner unless userID owns taskID.
+// Tasks in the trash are not found.
+func (s *Storage) checkOwner(ctx context.Context, userID, taskID int64) error {
+	var ownerID int64
+	err := s.database.QueryRowContext(ctx, "SELECT userID FROM tasks WHERE id = ? AND taskStatus != ? AND deletedAt = 0",
+		taskID, status.Creating).Scan(&ownerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("task %d: %w", taskID, todobot.ErrTaskNotFound)
+	}
 	if err != nil {
-		return "", errors.New(fmt.Sprintf("Storage.go -> DeleteTask() -> s.database.QueryRow() %s", err.Error()))
+		return fmt.Errorf("s.database.QueryRowContext(): %w", err)
 	}
-	if count == 0 {
-		return "There is no such Task", nil
+	if ownerID != userID {
+		return fmt.Errorf("task %d: %w", taskID, todobot.ErrNotOwner)
+	}
+	return nil
+}
+
+func (s *Storage) GetTask(ctx context.Context, userID, taskID int64) (task.Task, error) {
+	err := s.checkOwner(ctx, userID, taskID)
+	if err != nil {
+		return task.Task{}, fmt.Errorf("Storage.go -> GetTask() -> s.checkOwner(): %w", err)
 	}
-	_, err = s.database.Exec("DELETE FROM tasks WHERE taskName = ?", taskName)
+	t, err := scanTask(s.database.QueryRowContext(ctx, "SELECT "+taskColumns+" FROM tasks WHERE id = ?", taskID))
 	if err != nil {
-		return "", errors.New(fmt.Sprintf("Storage.go -> DeleteTask() -> s.database.Exec() %s",
-			err.Error()))
+		return task.Task{}, fmt.Errorf("Storage.go -> GetTask() -> scanTask(): %w", err)
 	}
-	return "Task deleted successfully", nil
+	return t, nil
 }
 
-func (s *Storage) DeleteNotFinishedTask(chatId int64) error {
-	_, err := s.database.Exec("DELETE FROM tasks WHERE taskStatus = ? AND userID = ?", 0, chatId)
+// DeleteTaskByID moves a task the user owns to the trash.
+func (s *Storage) DeleteTaskByID(ctx context.Context, userID, taskID int64) error {
+	err := s.checkOwner(ctx, userID, taskID)
 	if err != nil {
-		return errors.New(fmt.Sprintf("Storage.go -> DeleteNotFinishedTask() -> s.database.Exec() %s",
-			err.Error()))
+		return fmt.Errorf("Storage.go -> DeleteTaskByID() -> s.checkOwner(): %w", err)
+	}
+	_, err = s.database.ExecContext(ctx, "UPDATE tasks SET deletedAt = ? WHERE id = ?", time.Now().Unix(), taskID)
+	if err != nil {
+		return fmt.Errorf("Storage.go -> DeleteTaskByID() -> s.database.ExecContext(): %w", err)
 	}
 	return nil
 }
 
-func (s *Storage) GetListOfTasks(userID int64) ([]task.Task, error) {
+// DeleteCreatingTasks purges rows left behind by the old row-per-draft
+// creation flow, including those without a status from a crash between their
+// insert and SetTaskStatus. Rows written before createdAt existed have no age
+// and are treated as stale.
+func (s *Storage) DeleteCreatingTasks(ctx context.Context, olderThan time.Time) (int64, error) {
+	result, err := s.database.ExecContext(ctx, `DELETE FROM tasks WHERE (taskStatus = ? OR taskStatus IS NULL)
+        AND (createdAt IS NULL OR createdAt < ?)`, status.Creating, olderThan.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("Storage.go -> DeleteCreatingTasks() -> s.database.ExecContext(): %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("Storage.go -> DeleteCreatingTasks() -> result.RowsAffected(): %w", err)
+	}
+	return deleted, nil
+}
+
+// GetListOfTasks returns the user's tasks but the archived ones.
+func (s *Storage) GetListOfTasks(ctx context.Context, userID int64) ([]task.Task, error) {
 	var tasks []task.Task
 
-	rows, err := s.database.Query("SELECT taskName, taskDescription FROM tasks WHERE userID = ?", userID)
+	rows, err := s.database.QueryContext(ctx, "SELECT "+taskColumns+" FROM tasks WHERE userID = ? AND "+liveTasks,
+		userID)
 	if err != nil {
-		return []task.Task{}, errors.New(fmt.Sprintf("GetListOfTasks() -> s.database.Query() %s", err.Error()))
+		return []task.Task{}, fmt.Errorf("GetListOfTasks() -> s.database.QueryContext(): %w", err)
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var taskold task.Task
-		if err := rows.Scan(&taskold.TaskName, &taskold.TaskDescription); err != nil {
-			return []task.Task{}, errors.New(fmt.Sprintf("GetListOfTasks() -> rows.Scan() %s", err.Error()))
+		taskold, err := scanTask(rows)
+		if err != nil {
+			return []task.Task{}, fmt.Errorf("GetListOfTasks() -> scanTask(): %w", err)
 		}
 		tasks = append(tasks, taskold)
 	}
 	return tasks, nil
 }
+
+func (s *Storage) Ping(ctx context.Context) error {
+	var one int
+	return s.database.QueryRowContext(ctx, "SELECT 1").Scan(&one)
+}
+
+// taskColumns are the columns scanTask reads, in its order.
+const taskColumns = `id, userID, taskName, taskDescription, tags, list, taskStatus, priority, repeat, due, createdAt,
+        uid, resource, completedAt, deletedAt`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTask(row scanner) (task.Task, error) {
+	var t task.Task
+	var tags, due string
+	var createdAt sql.NullInt64
+	var completedAt, deletedAt int64
+	err := row.Scan(&t.ID, &t.ChatId, &t.TaskName, &t.TaskDescription, &tags, &t.List, &t.Status, &t.Priority,
+		&t.Repeat, &due, &createdAt, &t.UID, &t.Resource,
+		&completedAt, &deletedAt)
+	if err != nil {
+		return task.Task{}, err
+	}
+	t.Tags = strings.Fields(tags)
+	if due != "" {
+		t.Due, err = time.Parse(task.DateLayout, due)
+		if err != nil {
+			return task.Task{}, fmt.Errorf("task %d due %q: %w", t.ID, due, err)
+		}
+	}
+	if createdAt.Valid {
+		t.CreatedAt = time.Unix(createdAt.Int64, 0)
+	}
+	if completedAt != 0 {
+		t.CompletedAt = time.Unix(completedAt, 0)
+	}
+	if deletedAt != 0 {
+		t.DeletedAt = time.Unix(deletedAt, 0)
+	}
+	return t, nil
+}
+
+// setCompletedAt keeps completedAt in step with a status change, given
+// completedAtArgs of the new status: an unchanged status keeps its time, a
+// task becoming done is completed now and any other change clears it.
+const setCompletedAt = "completedAt = CASE WHEN taskStatus = ? THEN completedAt WHEN ? = ? THEN ? ELSE 0 END"
+
+func completedAtArgs(taskStatus int) []any {
+	return []any{taskStatus, taskStatus, status.Done, time.Now().Unix()}
+}
+
+func formatDue(due time.Time) string {
+	if due.IsZero() {
+		return ""
+	}
+	return due.Format(task.DateLayout)
+}
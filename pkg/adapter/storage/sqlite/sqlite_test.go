@@ -0,0 +1,93 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"telegramBot/pkg/adapter/todobot"
+	"telegramBot/pkg/model/task"
+	"telegramBot/pkg/model/task/status"
+	"testing"
+)
+
+func TestOwnerChecks(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStorage(t)
+	taskIDs := saveTasks(t, s, task.Task{TaskName: "Pay rent"}, task.Task{TaskName: "Deleted"})
+	own, deleted := taskIDs[0], taskIDs[1]
+	err := s.DeleteTaskByID(ctx, testUser, deleted)
+	if err != nil {
+		t.Fatalf("DeleteTaskByID() error = %v", err)
+	}
+
+	calls := []struct {
+		name string
+		call func(userID, taskID int64) error
+	}{
+		{"GetTask", func(userID, taskID int64) error {
+			_, err := s.GetTask(ctx, userID, taskID)
+			return err
+		}},
+		{"UpdateTask", func(userID, taskID int64) error {
+			return s.UpdateTask(ctx, userID, task.Task{ID: int(taskID), TaskName: "Pay rent", Status: status.Created})
+		}},
+		{"SetTaskStatus", func(userID, taskID int64) error {
+			return s.SetTaskStatus(ctx, userID, taskID, status.Created)
+		}},
+		{"GetAttachments", func(userID, taskID int64) error {
+			_, err := s.GetAttachments(ctx, userID, taskID)
+			return err
+		}},
+		{"GetComments", func(userID, taskID int64) error {
+			_, err := s.GetComments(ctx, userID, taskID)
+			return err
+		}},
+	}
+	tests := []struct {
+		name   string
+		userID int64
+		taskID int64
+		want   error
+	}{
+		{"owner", testUser, own, nil},
+		{"someone else", testUser + 1, own, todobot.ErrNotOwner},
+		{"missing task", testUser, own + 100, todobot.ErrTaskNotFound},
+		{"task in the trash", testUser, deleted, todobot.ErrTaskNotFound},
+	}
+	for _, call := range calls {
+		for _, tt := range tests {
+			err := call.call(tt.userID, tt.taskID)
+			if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+				t.Errorf("%s: %s: error = %v, want %v", call.name, tt.name, err, tt.want)
+			}
+		}
+	}
+
+	// The calls of the other user changed nothing.
+	got, err := s.GetTask(ctx, testUser, own)
+	if err != nil || got.Status != status.Created || got.TaskName != "Pay rent" {
+		t.Errorf("GetTask() = %+v, %v, want the task unchanged", got, err)
+	}
+}
+
+func TestSetTaskStatusCompletion(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStorage(t)
+	taskID := saveTasks(t, s, task.Task{TaskName: "Pay rent"})[0]
+
+	err := s.SetTaskStatus(ctx, testUser, taskID, status.Done)
+	if err != nil {
+		t.Fatalf("SetTaskStatus() error = %v", err)
+	}
+	got, err := s.GetTask(ctx, testUser, taskID)
+	if err != nil || got.Status != status.Done || got.CompletedAt.IsZero() {
+		t.Errorf("GetTask() = %+v, %v, want done with a completion time", got, err)
+	}
+	err = s.SetTaskStatus(ctx, testUser, taskID, status.Created)
+	if err != nil {
+		t.Fatalf("SetTaskStatus() error = %v", err)
+	}
+	got, err = s.GetTask(ctx, testUser, taskID)
+	if err != nil || got.Status != status.Created || !got.CompletedAt.IsZero() {
+		t.Errorf("GetTask() = %+v, %v, want open without a completion time", got, err)
+	}
+}
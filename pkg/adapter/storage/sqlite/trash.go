@@ -0,0 +1,106 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"telegramBot/pkg/adapter/todobot"
+	"telegramBot/pkg/model/task"
+	"time"
+)
+
+// GetDeletedTasks returns the user's trash, most recently deleted first.
+func (s *Storage) GetDeletedTasks(ctx context.Context, userID int64) ([]task.Task, error) {
+	rows, err := s.database.QueryContext(ctx, "SELECT "+taskColumns+
+		" FROM tasks WHERE userID = ? AND deletedAt != 0 ORDER BY deletedAt DESC, id DESC", userID)
+	if err != nil {
+		return nil, fmt.Errorf("trash.go -> GetDeletedTasks() -> s.database.QueryContext(): %w", err)
+	}
+	defer rows.Close()
+	var tasks []task.Task
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("trash.go -> GetDeletedTasks() -> scanTask(): %w", err)
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, nil
+}
+
+// RestoreTask takes a task the user owns out of the trash. Unless
+// deletedSince is zero, a task deleted before it gives ErrUndoExpired.
+func (s *Storage) RestoreTask(ctx context.Context, userID, taskID int64, deletedSince time.Time) error {
+	deletedAt, err := s.checkTrashed(ctx, userID, taskID)
+	if err != nil {
+		return fmt.Errorf("trash.go -> RestoreTask() -> s.checkTrashed(): %w", err)
+	}
+	if !deletedSince.IsZero() && deletedAt.Before(deletedSince) {
+		return fmt.Errorf("trash.go -> RestoreTask() task %d: %w", taskID, todobot.ErrUndoExpired)
+	}
+	_, err = s.database.ExecContext(ctx, "UPDATE tasks SET deletedAt = 0 WHERE id = ?", taskID)
+	if err != nil {
+		return fmt.Errorf("trash.go -> RestoreTask() -> s.database.ExecContext(): %w", err)
+	}
+	return nil
+}
+
+// PurgeTask deletes a task in the user's trash for good.
+func (s *Storage) PurgeTask(ctx context.Context, userID, taskID int64) error {
+	_, err := s.checkTrashed(ctx, userID, taskID)
+	if err != nil {
+		return fmt.Errorf("trash.go -> PurgeTask() -> s.checkTrashed(): %w", err)
+	}
+	_, err = s.database.ExecContext(ctx, "DELETE FROM tasks WHERE id = ?", taskID)
+	if err != nil {
+		return fmt.Errorf("trash.go -> PurgeTask() -> s.database.ExecContext(): %w", err)
+	}
+	return nil
+}
+
+// EmptyTrash deletes every task in the user's trash for good.
+func (s *Storage) EmptyTrash(ctx context.Context, userID int64) (int64, error) {
+	result, err := s.database.ExecContext(ctx, "DELETE FROM tasks WHERE userID = ? AND deletedAt != 0", userID)
+	if err != nil {
+		return 0, fmt.Errorf("trash.go -> EmptyTrash() -> s.database.ExecContext(): %w", err)
+	}
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("trash.go -> EmptyTrash() -> result.RowsAffected(): %w", err)
+	}
+	return purged, nil
+}
+
+// PurgeDeletedTasks deletes for good the tasks of every user that went to
+// the trash before deletedBefore.
+func (s *Storage) PurgeDeletedTasks(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	result, err := s.database.ExecContext(ctx, "DELETE FROM tasks WHERE deletedAt != 0 AND deletedAt < ?",
+		deletedBefore.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("trash.go -> PurgeDeletedTasks() -> s.database.ExecContext(): %w", err)
+	}
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("trash.go -> PurgeDeletedTasks() -> result.RowsAffected(): %w", err)
+	}
+	return purged, nil
+}
+
+// checkTrashed is checkOwner for tasks in the trash. It returns when the task
+// was deleted.
+func (s *Storage) checkTrashed(ctx context.Context, userID, taskID int64) (time.Time, error) {
+	var ownerID, deletedAt int64
+	err := s.database.QueryRowContext(ctx, "SELECT userID, deletedAt FROM tasks WHERE id = ? AND deletedAt != 0",
+		taskID).Scan(&ownerID, &deletedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, fmt.Errorf("task %d: %w", taskID, todobot.ErrTaskNotFound)
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("s.database.QueryRowContext(): %w", err)
+	}
+	if ownerID != userID {
+		return time.Time{}, fmt.Errorf("task %d: %w", taskID, todobot.ErrNotOwner)
+	}
+	return time.Unix(deletedAt, 0), nil
+}
@@ -0,0 +1,134 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"telegramBot/pkg/adapter/todobot"
+	"telegramBot/pkg/model/task"
+	"testing"
+	"time"
+)
+
+// trash moves tasks to the trash as if they were deleted at deletedAt.
+func trash(t *testing.T, s *Storage, deletedAt time.Time, taskIDs ...int64) {
+	t.Helper()
+	for _, taskID := range taskIDs {
+		_, err := s.database.Exec("UPDATE tasks SET deletedAt = ? WHERE id = ?", deletedAt.Unix(), taskID)
+		if err != nil {
+			t.Fatalf("s.database.Exec() error = %v", err)
+		}
+	}
+}
+
+func TestRestoreTask(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	tests := []struct {
+		name         string
+		userID       int64
+		deleted      time.Duration
+		deletedSince time.Duration
+		want         error
+		listed       bool
+	}{
+		{"within the undo window", testUser, time.Minute, todobot.UndoWindow, nil, true},
+		{"past the undo window", testUser, time.Hour, todobot.UndoWindow, todobot.ErrUndoExpired, false},
+		{"from the trash list", testUser, 30 * 24 * time.Hour, 0, nil, true},
+		{"someone else's task", testUser + 1, time.Minute, todobot.UndoWindow, todobot.ErrNotOwner, false},
+		{"not in the trash", testUser, 0, 0, todobot.ErrTaskNotFound, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestStorage(t)
+			taskID := saveTasks(t, s, task.Task{TaskName: "Pay rent"})[0]
+			if tt.deleted != 0 {
+				trash(t, s, now.Add(-tt.deleted), taskID)
+			}
+			var deletedSince time.Time
+			if tt.deletedSince != 0 {
+				deletedSince = now.Add(-tt.deletedSince)
+			}
+
+			err := s.RestoreTask(ctx, tt.userID, taskID, deletedSince)
+			if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+				t.Fatalf("RestoreTask() error = %v, want %v", err, tt.want)
+			}
+			_, err = s.GetTask(ctx, testUser, taskID)
+			if listed := err == nil; listed != tt.listed {
+				t.Errorf("GetTask() error = %v, want the task out of the trash: %t", err, tt.listed)
+			}
+		})
+	}
+}
+
+func TestPurgeTask(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStorage(t)
+	taskIDs := saveTasks(t, s, task.Task{TaskName: "Deleted"}, task.Task{TaskName: "Open"})
+	deleted, open := taskIDs[0], taskIDs[1]
+	trash(t, s, time.Now(), deleted)
+
+	tests := []struct {
+		name   string
+		userID int64
+		taskID int64
+		want   error
+	}{
+		{"someone else's task", testUser + 1, deleted, todobot.ErrNotOwner},
+		{"not in the trash", testUser, open, todobot.ErrTaskNotFound},
+		{"owner", testUser, deleted, nil},
+		{"purged already", testUser, deleted, todobot.ErrTaskNotFound},
+	}
+	for _, tt := range tests {
+		err := s.PurgeTask(ctx, tt.userID, tt.taskID)
+		if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+			t.Errorf("%s: PurgeTask() error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+	_, err := s.GetTask(ctx, testUser, open)
+	if err != nil {
+		t.Errorf("GetTask() error = %v, want the open task kept", err)
+	}
+}
+
+func TestEmptyAndPurgeTrash(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStorage(t)
+	now := time.Now()
+	taskIDs := saveTasks(t, s,
+		task.Task{TaskName: "Old"},
+		task.Task{TaskName: "Recent"},
+		task.Task{TaskName: "Open"},
+		task.Task{TaskName: "Other's old", ChatId: testUser + 1},
+		task.Task{TaskName: "Other's recent", ChatId: testUser + 1},
+	)
+	trash(t, s, now.Add(-48*time.Hour), taskIDs[0], taskIDs[3])
+	trash(t, s, now.Add(-time.Hour), taskIDs[1], taskIDs[4])
+
+	trashed, err := s.GetDeletedTasks(ctx, testUser)
+	if err != nil {
+		t.Fatalf("GetDeletedTasks() error = %v", err)
+	}
+	if len(trashed) != 2 || trashed[0].TaskName != "Recent" || trashed[1].TaskName != "Old" {
+		t.Errorf("GetDeletedTasks() = %+v, want Recent, then Old", trashed)
+	}
+
+	purged, err := s.PurgeDeletedTasks(ctx, now.Add(-24*time.Hour))
+	if err != nil || purged != 2 {
+		t.Errorf("PurgeDeletedTasks() = %d, %v, want the two old tasks", purged, err)
+	}
+	purged, err = s.EmptyTrash(ctx, testUser)
+	if err != nil || purged != 1 {
+		t.Errorf("EmptyTrash() = %d, %v, want Recent", purged, err)
+	}
+	for user, want := range map[int64]int{testUser: 0, testUser + 1: 1} {
+		trashed, err := s.GetDeletedTasks(ctx, user)
+		if err != nil || len(trashed) != want {
+			t.Errorf("GetDeletedTasks(%d) = %+v, %v, want %d tasks", user, trashed, err, want)
+		}
+	}
+	_, err = s.GetTask(ctx, testUser, taskIDs[2])
+	if err != nil {
+		t.Errorf("GetTask() error = %v, want the open task kept", err)
+	}
+}
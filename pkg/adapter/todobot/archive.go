@@ -0,0 +1,116 @@
+package todobot
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/zap"
+	"strconv"
+	"strings"
+	"telegramBot/pkg/clock"
+	"telegramBot/pkg/model/profile"
+	"telegramBot/pkg/model/task"
+	"telegramBot/pkg/model/task/status"
+	"time"
+)
+
+// MaxArchiveAfter caps the days a done task may wait before it is archived.
+const MaxArchiveAfter = 365
+
+// ArchiveInterval is how often Archiver looks for tasks to archive.
+const ArchiveInterval = time.Hour
+
+// SetArchiveAfter takes the number of days after which done tasks move to
+// the archive, or "off".
+func (s *TodoBot) SetArchiveAfter(ctx context.Context, userID int64, days string) error {
+	days = strings.TrimSpace(days)
+	if strings.EqualFold(days, "off") {
+		days = "0"
+	}
+	n, err := strconv.Atoi(days)
+	if err != nil || n < 0 || n > MaxArchiveAfter {
+		return fmt.Errorf("%w: %q", ErrInvalidArchiveAfter, days)
+	}
+	return s.updateProfile(ctx, userID, func(p *profile.Profile) { p.ArchiveAfter = n })
+}
+
+// SearchArchive searches the user's archived tasks. An empty query lists all
+// of them.
+func (s *TodoBot) SearchArchive(ctx context.Context, userID int64, query string,
+	limit, offset int) (task.SearchResult, error) {
+	return s.storage.SearchArchive(ctx, userID, strings.TrimSpace(query), limit, offset)
+}
+
+// UnarchiveTask moves an archived task back to the list as done. It counts
+// as completed now, so it is archived again only after another ArchiveAfter
+// days.
+func (s *TodoBot) UnarchiveTask(ctx context.Context, userID, taskID int64) error {
+	t, err := s.storage.GetTask(ctx, userID, taskID)
+	if err != nil {
+		return err
+	}
+	if t.Status != status.Archived {
+		return fmt.Errorf("task %d: %w", taskID, ErrNotArchived)
+	}
+	t.Status = status.Done
+	err = s.storage.UpdateTask(ctx, userID, t)
+	if err != nil {
+		return err
+	}
+	s.record(ctx, userID, t, task.EventUnarchived, "")
+	return nil
+}
+
+// ArchiveCompletedTasks archives the tasks of every user that have been done
+// for longer than their ArchiveAfter.
+func (s *TodoBot) ArchiveCompletedTasks(ctx context.Context, now time.Time) (int, error) {
+	archived, err := s.storage.ArchiveCompletedTasks(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+	for _, t := range archived {
+		s.record(ctx, t.ChatId, t, task.EventArchived, "")
+	}
+	return len(archived), nil
+}
+
+// Archiver archives done tasks once their owners' ArchiveAfter has passed,
+// checking every interval of its clock.
+type Archiver struct {
+	todoBot  *TodoBot
+	clock    clock.Clock
+	interval time.Duration
+}
+
+func NewArchiver(todoBot *TodoBot, clock clock.Clock) *Archiver {
+	return &Archiver{
+		todoBot:  todoBot,
+		clock:    clock,
+		interval: ArchiveInterval,
+	}
+}
+
+func (a *Archiver) Run(ctx context.Context) {
+	for {
+		err := a.Tick(ctx)
+		if err != nil {
+			a.todoBot.log.Error("Archiver.Run() -> a.Tick()", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-a.clock.After(a.interval):
+		}
+	}
+}
+
+// Tick archives the tasks due at the clock's current time.
+func (a *Archiver) Tick(ctx context.Context) error {
+	archived, err := a.todoBot.ArchiveCompletedTasks(ctx, a.clock.Now())
+	if err != nil {
+		return err
+	}
+	if archived > 0 {
+		a.todoBot.log.Info("archived done tasks", zap.Int("count", archived))
+	}
+	return nil
+}
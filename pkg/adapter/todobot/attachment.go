@@ -0,0 +1,49 @@
+package todobot
+
+import (
+	"context"
+	"fmt"
+	"telegramBot/pkg/model/task"
+)
+
+// MaxAttachments is the most files one task may carry.
+const MaxAttachments = 10
+
+// AttachToDraft adds a file to the task being created and returns how many
+// the draft has now.
+func (s *TodoBot) AttachToDraft(ctx context.Context, userID int64, a task.Attachment) (int, error) {
+	draft, err := s.session.GetDraft(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	if len(draft.Attachments) >= MaxAttachments {
+		return 0, fmt.Errorf("%w: %d", ErrTooManyAttachments, len(draft.Attachments))
+	}
+	draft.Attachments = append(draft.Attachments, a)
+	err = s.session.SetDraft(ctx, userID, draft)
+	if err != nil {
+		return 0, err
+	}
+	return len(draft.Attachments), nil
+}
+
+func (s *TodoBot) GetAttachments(ctx context.Context, userID, taskID int64) ([]task.Attachment, error) {
+	return s.storage.GetAttachments(ctx, userID, taskID)
+}
+
+// countAttachments fills in page.Attachments.
+func (s *TodoBot) countAttachments(ctx context.Context, userID int64, page *task.Page) error {
+	taskIDs := make([]int64, 0, len(page.Tasks))
+	for _, t := range page.Tasks {
+		taskIDs = append(taskIDs, int64(t.ID))
+	}
+	counts, err := s.storage.CountAttachments(ctx, userID, taskIDs)
+	if err != nil {
+		return err
+	}
+	page.Attachments = make(map[int]int, len(counts))
+	for taskID, count := range counts {
+		page.Attachments[int(taskID)] = count
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package todobot
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"telegramBot/pkg/model/task"
+)
+
+// MaxBulkTasks is the most tasks one bulk message may create.
+const MaxBulkTasks = 100
+
+// bullet matches what lists pasted from notes start their lines with: a dash,
+// star or dot, a number, and a checkbox.
+var bullet = regexp.MustCompile(`^(?:[-*•–—+]\s*|\d{1,3}[.)]\s*)?(?:\[[ xX]?\]\s*)?`)
+
+// IsBulk reports whether text holds more than one task, one per line.
+func IsBulk(text string) bool {
+	return len(bulkLines(text)) > 1
+}
+
+func bulkLines(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(bullet.ReplaceAllString(strings.TrimSpace(line), ""))
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// PrepareBulk reads one task per line of text and keeps them in the session
+// until ConfirmPending saves them. Each line may carry #tags, a !priority, an
+// @due date and an @repeat frequency, which are read as they are for a single
+// task; all but the tags are then left out of the name.
+func (s *TodoBot) PrepareBulk(ctx context.Context, userID int64, text string) ([]task.Task, error) {
+	lines := bulkLines(text)
+	if len(lines) == 0 {
+		return nil, ErrEmptyTaskName
+	}
+	if len(lines) > MaxBulkTasks {
+		return nil, fmt.Errorf("%w: %d lines", ErrTooManyTasks, len(lines))
+	}
+	tasks := make([]task.Task, 0, len(lines))
+	for _, line := range lines {
+		newTask := task.Task{ChatId: userID, TaskName: line, Tags: parseTags(line)}
+		err := s.applyProfile(ctx, &newTask)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, newTask)
+	}
+	err := s.session.SetPending(ctx, userID, PendingBulk, tasks)
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
@@ -0,0 +1,98 @@
+package todobot
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"telegramBot/pkg/model/task"
+	"telegramBot/pkg/model/task/status"
+)
+
+// NewAppPassword creates a password a CalDAV client signs in with. Only its
+// hash is kept, so it cannot be shown again.
+func (s *TodoBot) NewAppPassword(ctx context.Context, userID int64) (string, error) {
+	secret := make([]byte, 10)
+	_, err := rand.Read(secret)
+	if err != nil {
+		return "", fmt.Errorf("caldav.go -> NewAppPassword() -> rand.Read(): %w", err)
+	}
+	// Four groups of four characters are easier to type on a phone.
+	encoded := strings.ToLower(base32.StdEncoding.EncodeToString(secret))
+	password := encoded[0:4] + "-" + encoded[4:8] + "-" + encoded[8:12] + "-" + encoded[12:16]
+	err = s.storage.AddAppPassword(ctx, userID, hashAppPassword(password))
+	if err != nil {
+		return "", err
+	}
+	return password, nil
+}
+
+func (s *TodoBot) CountAppPasswords(ctx context.Context, userID int64) (int, error) {
+	return s.storage.CountAppPasswords(ctx, userID)
+}
+
+// RevokeAppPasswords signs every CalDAV client of the user out.
+func (s *TodoBot) RevokeAppPasswords(ctx context.Context, userID int64) error {
+	return s.storage.DeleteAppPasswords(ctx, userID)
+}
+
+// CheckAppPassword returns ErrInvalidAppPassword unless password is one of
+// the user's app passwords.
+func (s *TodoBot) CheckAppPassword(ctx context.Context, userID int64, password string) error {
+	owner, err := s.storage.GetAppPasswordUser(ctx, hashAppPassword(password))
+	if err != nil {
+		return err
+	}
+	if owner != userID {
+		return ErrInvalidAppPassword
+	}
+	return nil
+}
+
+// hashAppPassword ignores case, spaces and dashes, which people add or drop
+// when they copy a password by hand. App passwords are random enough for a
+// plain hash.
+func hashAppPassword(password string) string {
+	password = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(password))
+	sum := sha256.Sum256([]byte(password))
+	return hex.EncodeToString(sum[:])
+}
+
+// SyncTask saves a task a CalDAV client sent: it replaces the user's task
+// with the same ID, or creates one when the ID is zero. It returns the task
+// as stored.
+func (s *TodoBot) SyncTask(ctx context.Context, userID int64, t task.Task) (task.Task, error) {
+	t.ChatId = userID
+	if t.Status == status.Creating {
+		t.Status = status.Created
+	}
+	if t.ID == 0 {
+		taskIDs, err := s.storage.SaveTasks(ctx, []task.Task{t})
+		if err != nil {
+			return task.Task{}, err
+		}
+		t.ID = int(taskIDs[0])
+		s.record(ctx, userID, t, task.EventCreated, t.TaskName)
+		if t.Status == status.Done {
+			s.record(ctx, userID, t, task.EventCompleted, "")
+		}
+		return s.storage.GetTask(ctx, userID, int64(t.ID))
+	}
+	before, err := s.storage.GetTask(ctx, userID, int64(t.ID))
+	if err != nil {
+		return task.Task{}, err
+	}
+	err = s.storage.UpdateTask(ctx, userID, t)
+	if err != nil {
+		return task.Task{}, err
+	}
+	after, err := s.storage.GetTask(ctx, userID, int64(t.ID))
+	if err != nil {
+		return task.Task{}, err
+	}
+	s.recordChanges(ctx, userID, before, after)
+	return after, nil
+}
@@ -0,0 +1,55 @@
+package todobot
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"fmt"
+	"io"
+	"strings"
+	"telegramBot/pkg/ical"
+	"time"
+)
+
+// calendarName is how calendar apps title the feed until the user renames it.
+const calendarName = "Tasks"
+
+// CalendarToken returns the secret that names the user's calendar feed in
+// its URL, creating it on first use.
+func (s *TodoBot) CalendarToken(ctx context.Context, userID int64) (string, error) {
+	token, err := s.storage.GetCalendarToken(ctx, userID)
+	if err != nil || token != "" {
+		return token, err
+	}
+	return s.ResetCalendarToken(ctx, userID)
+}
+
+// ResetCalendarToken replaces the user's calendar token, so the URLs given
+// out before stop working.
+func (s *TodoBot) ResetCalendarToken(ctx context.Context, userID int64) (string, error) {
+	secret := make([]byte, 20)
+	_, err := rand.Read(secret)
+	if err != nil {
+		return "", fmt.Errorf("calendar.go -> ResetCalendarToken() -> rand.Read(): %w", err)
+	}
+	token := strings.ToLower(base32.StdEncoding.EncodeToString(secret))
+	err = s.storage.SetCalendarToken(ctx, userID, token)
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// CalendarUser returns the owner of a calendar token, or ErrCalendarNotFound.
+func (s *TodoBot) CalendarUser(ctx context.Context, token string) (int64, error) {
+	return s.storage.GetCalendarUser(ctx, token)
+}
+
+// WriteCalendar writes the user's tasks with due dates as an iCalendar feed.
+func (s *TodoBot) WriteCalendar(ctx context.Context, userID int64, w io.Writer) error {
+	tasks, err := s.GetListOfTasks(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return ical.WriteFeed(w, calendarName, tasks, time.Now())
+}
@@ -0,0 +1,160 @@
+package todobot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"sort"
+	"telegramBot/pkg/clock"
+	"telegramBot/pkg/model/profile"
+	"telegramBot/pkg/model/state/user"
+	"telegramBot/pkg/model/task"
+	"telegramBot/pkg/model/task/status"
+	"time"
+)
+
+const DigestInterval = time.Minute
+
+type ListCount struct {
+	List string
+	Open int
+}
+
+// Digest is a user's daily summary of their open tasks.
+type Digest struct {
+	Profile profile.Profile
+	// Day is the user's local day, as task.Day returns it.
+	Day      time.Time
+	Overdue  []task.Task
+	DueToday []task.Task
+	Lists    []ListCount
+}
+
+func (d Digest) Open() int {
+	var open int
+	for _, list := range d.Lists {
+		open += list.Open
+	}
+	return open
+}
+
+func (d Digest) Empty() bool {
+	return d.Open() == 0
+}
+
+// BuildDigest summarizes the user's open tasks as of now in their timezone.
+func (s *TodoBot) BuildDigest(ctx context.Context, p profile.Profile, now time.Time) (Digest, error) {
+	tasks, err := s.storage.GetListOfTasks(ctx, p.UserID)
+	if err != nil {
+		return Digest{}, err
+	}
+	digest := Digest{Profile: p, Day: task.Day(p.Now(now))}
+	open := make(map[string]int)
+	for _, t := range tasks {
+		if t.Status != status.Created {
+			continue
+		}
+		open[t.List]++
+		switch {
+		case t.Due.IsZero():
+		case t.Due.Before(digest.Day):
+			digest.Overdue = append(digest.Overdue, t)
+		case t.Due.Equal(digest.Day):
+			digest.DueToday = append(digest.DueToday, t)
+		}
+	}
+	sort.SliceStable(digest.Overdue, func(i, j int) bool { return digest.Overdue[i].Due.Before(digest.Overdue[j].Due) })
+	for list, count := range open {
+		digest.Lists = append(digest.Lists, ListCount{List: list, Open: count})
+	}
+	sort.Slice(digest.Lists, func(i, j int) bool { return digest.Lists[i].List < digest.Lists[j].List })
+	return digest, nil
+}
+
+// GetDigest is the user's digest as of now, whether or not they opted in.
+func (s *TodoBot) GetDigest(ctx context.Context, userID int64, now time.Time) (Digest, error) {
+	p, err := s.storage.GetProfile(ctx, userID)
+	if err != nil {
+		return Digest{}, err
+	}
+	return s.BuildDigest(ctx, p, now)
+}
+
+// DigestScheduler sends the daily digests. It wakes up every interval of its
+// clock and sends every digest that profile.DigestDue says is due; users in
+// the middle of a conversation get theirs once they are done with it.
+type DigestScheduler struct {
+	todoBot  *TodoBot
+	clock    clock.Clock
+	interval time.Duration
+	send     func(ctx context.Context, digest Digest) error
+}
+
+func NewDigestScheduler(todoBot *TodoBot, clock clock.Clock,
+	send func(ctx context.Context, digest Digest) error) *DigestScheduler {
+	return &DigestScheduler{
+		todoBot:  todoBot,
+		clock:    clock,
+		interval: DigestInterval,
+		send:     send,
+	}
+}
+
+func (d *DigestScheduler) Run(ctx context.Context) {
+	for {
+		err := d.Tick(ctx)
+		if err != nil {
+			d.todoBot.log.Error("DigestScheduler.Run() -> d.Tick()", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.clock.After(d.interval):
+		}
+	}
+}
+
+// Tick sends the digests due at the clock's current time. A failed digest is
+// retried on the next tick.
+func (d *DigestScheduler) Tick(ctx context.Context) error {
+	now := d.clock.Now()
+	profiles, err := d.todoBot.storage.ListDigestProfiles(ctx)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, p := range profiles {
+		if !p.DigestDue(now) {
+			continue
+		}
+		err := d.sendDigest(ctx, p, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("user %d: %w", p.UserID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (d *DigestScheduler) sendDigest(ctx context.Context, p profile.Profile, now time.Time) error {
+	state, err := d.todoBot.storage.GetUserState(ctx, p.UserID)
+	if err != nil {
+		return err
+	}
+	if state != user.Default {
+		return nil
+	}
+	digest, err := d.todoBot.BuildDigest(ctx, p, now)
+	if err != nil {
+		return err
+	}
+	// Nothing is sent on days without open tasks, but the day still counts
+	// as done.
+	if !digest.Empty() {
+		err = d.send(ctx, digest)
+		if err != nil {
+			return err
+		}
+	}
+	return d.todoBot.storage.SetDigestSent(ctx, p.UserID, digest.Day.Format(task.DateLayout))
+}
@@ -0,0 +1,171 @@
+package todobot_test
+
+import (
+	"context"
+	"strings"
+	"telegramBot/pkg/adapter/todobot"
+	"telegramBot/pkg/clock"
+	"telegramBot/pkg/model/state/user"
+	"telegramBot/pkg/model/task"
+	"testing"
+	"time"
+)
+
+const digestUser = 42
+
+type digestTest struct {
+	t         *testing.T
+	ctx       context.Context
+	bot       *todobot.TodoBot
+	clock     *clock.Fake
+	scheduler *todobot.DigestScheduler
+	sent      []todobot.Digest
+}
+
+// newDigestTest starts on a Monday at midnight UTC with one open task and
+// the digest set for 08:00 UTC.
+func newDigestTest(t *testing.T) *digestTest {
+	t.Helper()
+	d := &digestTest{
+		t:     t,
+		ctx:   context.Background(),
+		bot:   newTestBot(t, nil),
+		clock: clock.NewFake(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)),
+	}
+	d.scheduler = todobot.NewDigestScheduler(d.bot, d.clock, func(ctx context.Context, digest todobot.Digest) error {
+		d.sent = append(d.sent, digest)
+		return nil
+	})
+	_, err := d.bot.SyncTask(d.ctx, digestUser, task.Task{TaskName: "Pay rent"})
+	if err != nil {
+		t.Fatalf("SyncTask() error = %v", err)
+	}
+	d.must(d.bot.SetDigest(d.ctx, digestUser, "08:00"))
+	return d
+}
+
+func (d *digestTest) must(err error) {
+	d.t.Helper()
+	if err != nil {
+		d.t.Fatalf("error = %v", err)
+	}
+}
+
+// at ticks the scheduler at a UTC time of the given March day and checks
+// which local days have had a digest by then.
+func (d *digestTest) at(day, hour, minute int, wantDays ...string) {
+	d.t.Helper()
+	d.clock.Set(time.Date(2026, 3, day, hour, minute, 0, 0, time.UTC))
+	d.must(d.scheduler.Tick(d.ctx))
+	var days []string
+	for _, digest := range d.sent {
+		days = append(days, digest.Day.Format(task.DateLayout))
+	}
+	if strings.Join(days, " ") != strings.Join(wantDays, " ") {
+		d.t.Fatalf("at %s digests went out for %v, want %v", d.clock.Now().Format(time.RFC3339), days, wantDays)
+	}
+}
+
+func TestDigestOncePerDay(t *testing.T) {
+	d := newDigestTest(t)
+	d.at(2, 7, 59)
+	d.at(2, 8, 0, "2026-03-02")
+	d.at(2, 8, 1, "2026-03-02")
+	d.at(2, 23, 59, "2026-03-02")
+	d.at(3, 7, 0, "2026-03-02")
+	// A missed minute is made up for on the next tick.
+	d.at(3, 9, 30, "2026-03-02", "2026-03-03")
+	d.at(3, 10, 0, "2026-03-02", "2026-03-03")
+	if got := d.sent[0]; len(got.Lists) != 1 || got.Open() != 1 {
+		t.Errorf("digest = %+v, want the open task", got)
+	}
+}
+
+func TestDigestWaitsForConversation(t *testing.T) {
+	d := newDigestTest(t)
+	d.must(d.bot.SetUserState(d.ctx, digestUser, user.WaitingForNewTaskName))
+	d.at(2, 8, 0)
+	d.must(d.bot.SetUserState(d.ctx, digestUser, user.Default))
+	d.at(2, 8, 5, "2026-03-02")
+}
+
+func TestDigestQuietHours(t *testing.T) {
+	d := newDigestTest(t)
+	d.must(d.bot.SetQuietHours(d.ctx, digestUser, "07:00-09:00"))
+	d.at(2, 8, 0)
+	d.at(2, 8, 59)
+	// Held back, the digest goes out as soon as quiet hours end.
+	d.at(2, 9, 0, "2026-03-02")
+	d.at(2, 9, 1, "2026-03-02")
+
+	// Quiet hours past midnight hold back a digest set inside them until
+	// the morning.
+	d.must(d.bot.SetQuietHours(d.ctx, digestUser, "23:00-07:00"))
+	d.must(d.bot.SetDigest(d.ctx, digestUser, "06:00"))
+	d.at(3, 6, 0, "2026-03-02")
+	d.at(3, 7, 0, "2026-03-02", "2026-03-03")
+}
+
+func TestDigestTimezoneChange(t *testing.T) {
+	d := newDigestTest(t)
+	d.at(2, 8, 0, "2026-03-02")
+
+	// Moving east: 10:00 UTC is 19:00 on the 2nd in Tokyo, whose digest went
+	// out; 08:00 on the 3rd there is 23:00 UTC on the 2nd.
+	d.must(d.bot.SetTimezone(d.ctx, digestUser, "Asia/Tokyo"))
+	d.at(2, 10, 0, "2026-03-02")
+	d.at(2, 22, 59, "2026-03-02")
+	d.at(2, 23, 0, "2026-03-02", "2026-03-03")
+
+	// Moving back west lands on a day that is already over for the user:
+	// 23:30 UTC is still the 2nd in New York. No second digest that day.
+	d.must(d.bot.SetTimezone(d.ctx, digestUser, "America/New_York"))
+	d.at(2, 23, 30, "2026-03-02", "2026-03-03")
+	d.at(3, 12, 59, "2026-03-02", "2026-03-03")
+	// 08:00 on the 4th in New York is 13:00 UTC.
+	d.at(4, 12, 59, "2026-03-02", "2026-03-03")
+	d.at(4, 13, 0, "2026-03-02", "2026-03-03", "2026-03-04")
+}
+
+func TestDigestSchedulerRun(t *testing.T) {
+	d := newDigestTest(t)
+	sent := make(chan todobot.Digest, 1)
+	scheduler := todobot.NewDigestScheduler(d.bot, d.clock, func(ctx context.Context, digest todobot.Digest) error {
+		sent <- digest
+		return nil
+	})
+	ctx, cancel := context.WithCancel(d.ctx)
+	done := make(chan struct{})
+	go func() {
+		scheduler.Run(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	// Run ticks at once, then sleeps on the clock until 08:00 comes.
+	for minute := 0; minute < 8*60; minute++ {
+		for d.clock.Waiters() == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		select {
+		case digest := <-sent:
+			t.Fatalf("digest for %s sent at %s", digest.Day.Format(task.DateLayout), d.clock.Now())
+		default:
+		}
+		d.clock.Advance(todobot.DigestInterval)
+	}
+	select {
+	case digest := <-sent:
+		if got := d.clock.Now().Format("15:04"); got != "08:00" {
+			t.Errorf("digest sent at %s, want 08:00", got)
+		}
+		if got := digest.Day.Format(task.DateLayout); got != "2026-03-02" {
+			t.Errorf("digest day = %s, want 2026-03-02", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no digest sent at 08:00")
+	}
+}
@@ -0,0 +1,90 @@
+package todobot
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"strings"
+	"telegramBot/pkg/model/task"
+)
+
+// StartDraft begins a task that lives only in the session until CommitDraft
+// writes it to storage in one go, so abandoned creations leave nothing behind.
+func (s *TodoBot) StartDraft(ctx context.Context, userID int64) error {
+	return s.session.SetDraft(ctx, userID, task.Task{ChatId: userID})
+}
+
+func (s *TodoBot) SetDraftName(ctx context.Context, userID int64, taskName string) error {
+	taskName = strings.TrimSpace(taskName)
+	if taskName == "" {
+		return ErrEmptyTaskName
+	}
+	draft, err := s.session.GetDraft(ctx, userID)
+	if err != nil {
+		return err
+	}
+	draft.TaskName = taskName
+	return s.session.SetDraft(ctx, userID, draft)
+}
+
+func (s *TodoBot) SetDraftDescription(ctx context.Context, userID int64, taskDescription string) error {
+	draft, err := s.session.GetDraft(ctx, userID)
+	if err != nil {
+		return err
+	}
+	draft.TaskDescription = taskDescription
+	return s.session.SetDraft(ctx, userID, draft)
+}
+
+func (s *TodoBot) CommitDraft(ctx context.Context, userID int64) (taskID int64, err error) {
+	draft, err := s.session.GetDraft(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	draft.Tags = parseTags(draft.TaskName, draft.TaskDescription)
+	err = s.applyProfile(ctx, &draft)
+	if err != nil {
+		return 0, err
+	}
+	taskID, err = s.storage.SaveTask(ctx, draft)
+	if err != nil {
+		return 0, err
+	}
+	draft.ID = int(taskID)
+	s.record(ctx, userID, draft, task.EventCreated, draft.TaskName)
+	// The task is saved at this point, so failing here would invite a retry
+	// that saves it twice. A leftover draft expires on its own.
+	err = s.session.DeleteDraft(ctx, userID)
+	if err != nil {
+		s.log.Warn("CommitDraft() -> s.session.DeleteDraft()", zap.Int64("userID", userID), zap.Error(err))
+	}
+	return taskID, nil
+}
+
+// CreateTask saves a complete task at once, skipping the draft.
+func (s *TodoBot) CreateTask(ctx context.Context, userID int64, taskName, taskDescription string) (int64, error) {
+	taskName = strings.TrimSpace(taskName)
+	if taskName == "" {
+		return 0, ErrEmptyTaskName
+	}
+	newTask := task.Task{
+		ChatId:          userID,
+		TaskName:        taskName,
+		TaskDescription: taskDescription,
+		Tags:            parseTags(taskName, taskDescription),
+	}
+	err := s.applyProfile(ctx, &newTask)
+	if err != nil {
+		return 0, err
+	}
+	taskID, err := s.storage.SaveTask(ctx, newTask)
+	if err != nil {
+		return 0, err
+	}
+	newTask.ID = int(taskID)
+	s.record(ctx, userID, newTask, task.EventCreated, newTask.TaskName)
+	return taskID, nil
+}
+
+func (s *TodoBot) CancelDraft(ctx context.Context, userID int64) error {
+	return s.session.DeleteDraft(ctx, userID)
+}
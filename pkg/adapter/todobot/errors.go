@@ -0,0 +1,34 @@
+package todobot
+
+import "errors"
+
+// Domain errors returned, possibly wrapped, by TodoBot and its Storage and
+// Session implementations. Frontends match them with errors.Is to choose a
+// reply for the user.
+var (
+	ErrTaskNotFound        = errors.New("task not found")
+	ErrAmbiguousName       = errors.New("several tasks have this name")
+	ErrNotOwner            = errors.New("task belongs to another user")
+	ErrDraftNotFound       = errors.New("no task is being created")
+	ErrEmptyTaskName       = errors.New("task name is empty")
+	ErrInvalidPageSize     = errors.New("unsupported page size")
+	ErrUnsupportedLanguage = errors.New("unsupported language")
+	ErrInvalidTimezone     = errors.New("unknown timezone")
+	ErrInvalidDateFormat   = errors.New("unsupported date format")
+	ErrInvalidListName     = errors.New("invalid list name")
+	ErrInvalidQuietHours   = errors.New("invalid quiet hours")
+	ErrInvalidDigestTime   = errors.New("invalid digest time")
+	ErrUnsupportedFormat   = errors.New("unsupported file format")
+	ErrUnreadableFile      = errors.New("file cannot be read")
+	ErrImportTooLarge      = errors.New("file is too large to import")
+	ErrPendingNotFound     = errors.New("no tasks are waiting for confirmation")
+	ErrTooManyTasks        = errors.New("too many tasks at once")
+	ErrCalendarNotFound    = errors.New("no calendar has this token")
+	ErrInvalidAppPassword  = errors.New("invalid app password")
+	ErrTooManyAttachments  = errors.New("too many attachments")
+	ErrEmptyComment        = errors.New("comment is empty")
+	ErrCommentTooLong      = errors.New("comment is too long")
+	ErrUndoExpired         = errors.New("too late to undo")
+	ErrInvalidArchiveAfter = errors.New("invalid number of days before archiving")
+	ErrNotArchived         = errors.New("task is not archived")
+)
@@ -0,0 +1,27 @@
+package todobot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"telegramBot/pkg/export"
+	"time"
+)
+
+// Export writes all of the user's tasks, archived ones included, to w in
+// format.
+func (s *TodoBot) Export(ctx context.Context, userID int64, format export.Format, w io.Writer) error {
+	if !export.Supported(format) {
+		return fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+	tasks, err := s.GetListOfTasks(ctx, userID)
+	if err != nil {
+		return err
+	}
+	archived, err := s.storage.GetArchivedTasks(ctx, userID)
+	if err != nil {
+		return err
+	}
+	tasks = append(tasks, archived...)
+	return export.Write(w, format, tasks, time.Now())
+}
@@ -0,0 +1,66 @@
+package todobot
+
+import (
+	"context"
+	"strings"
+	"telegramBot/pkg/model/task"
+	"time"
+	"unicode/utf8"
+)
+
+// maxForwardName caps the name the first line of a forwarded message becomes.
+const maxForwardName = 64
+
+// Forward is a message the user forwarded from another chat. Sender and Link
+// are empty when the chat or the sender's privacy settings hide them.
+type Forward struct {
+	Text   string
+	Sender string
+	Date   time.Time
+	Link   string
+}
+
+// PrepareForward turns a forwarded message into a task and keeps it in the
+// session until ConfirmPending saves it. The name sums up the first line and
+// the description holds the whole text followed by where it came from.
+// Markers are not read: they were written for someone else, not the bot.
+func (s *TodoBot) PrepareForward(ctx context.Context, userID int64, f Forward) (task.Task, error) {
+	text := strings.TrimSpace(f.Text)
+	firstLine, _, _ := strings.Cut(text, "\n")
+	name := summarize(strings.TrimSpace(firstLine), maxForwardName)
+	if name == "" {
+		return task.Task{}, ErrEmptyTaskName
+	}
+	p, err := s.storage.GetProfile(ctx, userID)
+	if err != nil {
+		return task.Task{}, err
+	}
+	origin := p.FormatDate(f.Date) + p.Now(f.Date).Format(" 15:04")
+	if f.Sender != "" {
+		origin = f.Sender + ", " + origin
+	}
+	description := text + "\n\n— " + origin
+	if f.Link != "" {
+		description += "\n" + f.Link
+	}
+	newTask := task.Task{ChatId: userID, TaskName: name, TaskDescription: description, List: p.DefaultList}
+	err = s.session.SetPending(ctx, userID, PendingForward, []task.Task{newTask})
+	if err != nil {
+		return task.Task{}, err
+	}
+	return newTask, nil
+}
+
+// summarize shortens line to at most limit runes, at a word boundary when
+// there is one.
+func summarize(line string, limit int) string {
+	if utf8.RuneCountInString(line) <= limit {
+		return line
+	}
+	runes := []rune(line)[:limit-1]
+	cut := string(runes)
+	if i := strings.LastIndexAny(cut, " \t"); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " \t.,;:") + "…"
+}
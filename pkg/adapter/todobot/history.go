@@ -0,0 +1,75 @@
+package todobot
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"strings"
+	"telegramBot/pkg/model/task"
+	"telegramBot/pkg/model/task/status"
+	"unicode/utf8"
+)
+
+// MaxCommentLength caps a comment, in characters.
+const MaxCommentLength = 500
+
+// record adds an event to a task's history. The change it records is saved
+// by then, so a failure is only logged: an error would invite a retry that
+// makes the change twice.
+func (s *TodoBot) record(ctx context.Context, actorID int64, t task.Task, kind, detail string) {
+	err := s.storage.AddEvent(ctx, task.Event{TaskID: int64(t.ID), UserID: t.ChatId, ActorID: actorID, Kind: kind,
+		Detail: detail})
+	if err != nil {
+		s.log.Warn("record() -> s.storage.AddEvent()", zap.Int("taskID", t.ID), zap.String("kind", kind),
+			zap.Error(err))
+	}
+}
+
+// recordChanges adds the events that tell before from after.
+func (s *TodoBot) recordChanges(ctx context.Context, actorID int64, before, after task.Task) {
+	if after.TaskName != before.TaskName {
+		s.record(ctx, actorID, after, task.EventRenamed, after.TaskName)
+	}
+	// Moves to and from the archive are recorded by those who make them.
+	if after.Status == status.Done && before.Status == status.Created {
+		s.record(ctx, actorID, after, task.EventCompleted, "")
+	}
+	if after.Status == status.Created && before.Status != status.Created {
+		s.record(ctx, actorID, after, task.EventReopened, "")
+	}
+	if after.List != before.List {
+		s.record(ctx, actorID, after, task.EventAssigned, after.List)
+	}
+}
+
+// GetTaskHistory returns what happened to a task, oldest first. A deleted
+// task keeps its history.
+func (s *TodoBot) GetTaskHistory(ctx context.Context, userID, taskID int64) ([]task.Event, error) {
+	events, err := s.storage.GetEvents(ctx, userID, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		// Tell a task without history from one that is not the user's.
+		_, err := s.storage.GetTask(ctx, userID, taskID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return events, nil
+}
+
+func (s *TodoBot) AddComment(ctx context.Context, userID, taskID int64, text string) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return ErrEmptyComment
+	}
+	if utf8.RuneCountInString(text) > MaxCommentLength {
+		return ErrCommentTooLong
+	}
+	_, err := s.storage.AddComment(ctx, userID, task.Comment{TaskID: taskID, AuthorID: userID, Text: text})
+	return err
+}
+
+func (s *TodoBot) GetComments(ctx context.Context, userID, taskID int64) ([]task.Comment, error) {
+	return s.storage.GetComments(ctx, userID, taskID)
+}
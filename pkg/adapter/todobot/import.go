@@ -0,0 +1,42 @@
+package todobot
+
+import (
+	"context"
+	"fmt"
+	"telegramBot/pkg/importer"
+)
+
+// MaxImportSize is the largest file, in bytes, PrepareImport accepts.
+const MaxImportSize = 1 << 20
+
+// PrepareImport reads an uploaded file and keeps its tasks in the session
+// until ConfirmPending saves them, so the user can check a preview first.
+// Nothing is kept when no task could be read.
+func (s *TodoBot) PrepareImport(ctx context.Context, userID int64, fileName string,
+	data []byte) (importer.Result, error) {
+	if len(data) > MaxImportSize {
+		return importer.Result{}, fmt.Errorf("%w: %d bytes", ErrImportTooLarge, len(data))
+	}
+	format, err := importer.Detect(fileName, data)
+	if err != nil {
+		return importer.Result{}, fmt.Errorf("%w: %q: %v", ErrUnsupportedFormat, fileName, err)
+	}
+	result, err := importer.Parse(format, data)
+	if err != nil {
+		return importer.Result{}, fmt.Errorf("%w: %v", ErrUnreadableFile, err)
+	}
+	if len(result.Tasks) == 0 {
+		return result, nil
+	}
+	for i := range result.Tasks {
+		result.Tasks[i].ChatId = userID
+		if len(result.Tasks[i].Tags) == 0 {
+			result.Tasks[i].Tags = parseTags(result.Tasks[i].TaskName, result.Tasks[i].TaskDescription)
+		}
+	}
+	err = s.session.SetPending(ctx, userID, PendingImport, result.Tasks)
+	if err != nil {
+		return importer.Result{}, err
+	}
+	return result, nil
+}
@@ -0,0 +1,141 @@
+package todobot
+
+import (
+	"strings"
+	"telegramBot/pkg/model/task"
+	"telegramBot/pkg/model/task/priority"
+	"telegramBot/pkg/model/task/repeat"
+	"time"
+	"unicode"
+)
+
+func parseTags(texts ...string) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, text := range texts {
+		for _, word := range strings.Fields(text) {
+			if !strings.HasPrefix(word, "#") {
+				continue
+			}
+			tag := strings.ToLower(strings.TrimFunc(word[1:], func(r rune) bool {
+				return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-'
+			}))
+			if tag == "" || seen[tag] {
+				continue
+			}
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// parseDue finds the first due date marker: @today, @tomorrow, a weekday such
+// as @fri meaning its next occurrence, or @2006-01-02. today is the user's
+// local day as task.Day returns it. The zero time means no marker.
+func parseDue(today time.Time, texts ...string) time.Time {
+	for _, text := range texts {
+		for _, word := range strings.Fields(text) {
+			if due, ok := dueMarker(today, word); ok {
+				return due
+			}
+		}
+	}
+	return time.Time{}
+}
+
+func dueMarker(today time.Time, word string) (time.Time, bool) {
+	if !strings.HasPrefix(word, "@") {
+		return time.Time{}, false
+	}
+	marker := strings.ToLower(strings.TrimRight(word[1:], ".,;:!?)"))
+	switch marker {
+	case "today":
+		return today, true
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), true
+	}
+	if weekday, ok := weekdays[marker]; ok {
+		days := (int(weekday) - int(today.Weekday()) + 7) % 7
+		if days == 0 {
+			days = 7
+		}
+		return today.AddDate(0, 0, days), true
+	}
+	if due, err := time.Parse(task.DateLayout, marker); err == nil {
+		return due, true
+	}
+	return time.Time{}, false
+}
+
+// parsePriority finds the first priority marker: !low, !medium or !high.
+func parsePriority(texts ...string) int {
+	for _, text := range texts {
+		for _, word := range strings.Fields(text) {
+			if p, ok := priorityMarker(word); ok {
+				return p
+			}
+		}
+	}
+	return priority.None
+}
+
+func priorityMarker(word string) (int, bool) {
+	if !strings.HasPrefix(word, "!") {
+		return priority.None, false
+	}
+	return priority.Parse(strings.TrimRight(word[1:], ".,;:!?)"))
+}
+
+// parseRepeat finds the first repeat marker: @daily, @weekly, @monthly or
+// @yearly.
+func parseRepeat(texts ...string) string {
+	for _, text := range texts {
+		for _, word := range strings.Fields(text) {
+			if frequency, ok := repeatMarker(word); ok {
+				return frequency
+			}
+		}
+	}
+	return repeat.None
+}
+
+func repeatMarker(word string) (string, bool) {
+	if !strings.HasPrefix(word, "@") {
+		return repeat.None, false
+	}
+	return repeat.Parse(strings.TrimRight(word[1:], ".,;:!?)"))
+}
+
+// stripMarkers removes due date, repeat and priority markers from a task name, which
+// reads better without them. Tags stay, being words of the name too. A name
+// made of markers alone is kept as it is.
+func stripMarkers(name string) string {
+	var words []string
+	for _, word := range strings.Fields(name) {
+		if _, ok := dueMarker(time.Time{}, word); ok {
+			continue
+		}
+		if _, ok := priorityMarker(word); ok {
+			continue
+		}
+		if _, ok := repeatMarker(word); ok {
+			continue
+		}
+		words = append(words, word)
+	}
+	if len(words) == 0 {
+		return name
+	}
+	return strings.Join(words, " ")
+}
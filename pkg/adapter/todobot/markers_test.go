@@ -0,0 +1,150 @@
+package todobot
+
+import (
+	"reflect"
+	"telegramBot/pkg/model/task/priority"
+	"telegramBot/pkg/model/task/repeat"
+	"testing"
+	"time"
+)
+
+func TestParseDue(t *testing.T) {
+	// A Wednesday.
+	today := time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		texts []string
+		want  time.Time
+	}{
+		{"none", []string{"Pay rent"}, time.Time{}},
+		{"today", []string{"Pay rent @today"}, today},
+		{"tomorrow with punctuation", []string{"Pay rent @Tomorrow!"}, today.AddDate(0, 0, 1)},
+		{"later weekday", []string{"Pay rent @fri"}, today.AddDate(0, 0, 2)},
+		{"earlier weekday", []string{"Pay rent @monday"}, today.AddDate(0, 0, 5)},
+		{"same weekday is next week", []string{"Pay rent @wed"}, today.AddDate(0, 0, 7)},
+		{"date", []string{"Pay rent @2026-04-01"}, time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"first marker wins", []string{"@tomorrow @today"}, today.AddDate(0, 0, 1)},
+		{"from the description", []string{"Pay rent", "by @today"}, today},
+		{"not a marker", []string{"email me@today.com", "@someday"}, time.Time{}},
+		{"repeat is not a date", []string{"Gym @daily"}, time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseDue(today, tt.texts...); !got.Equal(tt.want) {
+				t.Errorf("parseDue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePriority(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"Pay rent", priority.None},
+		{"Pay rent !high", priority.High},
+		{"Pay rent !MED.", priority.Medium},
+		{"!low first !high", priority.Low},
+		{"Wow!", priority.None},
+		{"Pay rent !soon", priority.None},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := parsePriority(tt.text); got != tt.want {
+				t.Errorf("parsePriority() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRepeat(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Gym", repeat.None},
+		{"Gym @daily", repeat.Daily},
+		{"Pay rent @Monthly,", repeat.Monthly},
+		{"Birthday @annually", repeat.Yearly},
+		{"Gym @fri @weekly", repeat.Weekly},
+		{"Gym @hourly", repeat.None},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := parseRepeat(tt.text); got != tt.want {
+				t.Errorf("parseRepeat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		name  string
+		texts []string
+		want  []string
+	}{
+		{"none", []string{"Pay rent"}, nil},
+		{"several", []string{"Pay #Rent #home-office, #bills_2026"}, []string{"rent", "home-office", "bills_2026"}},
+		{"duplicates across texts", []string{"#home", "#HOME #work"}, []string{"home", "work"}},
+		{"bare hash", []string{"Item # 5 #"}, nil},
+		{"cyrillic", []string{"Сплатити #оренда"}, []string{"оренда"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseTags(tt.texts...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseTags() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripMarkers(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Pay rent", "Pay rent"},
+		{"Pay rent @fri !high @monthly #bills", "Pay rent #bills"},
+		{"@2026-04-01 Pay   rent", "Pay rent"},
+		{"@today !high", "@today !high"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stripMarkers(tt.name); got != tt.want {
+				t.Errorf("stripMarkers() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBulkLines(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+		bulk bool
+	}{
+		{"single task", "Pay rent", []string{"Pay rent"}, false},
+		{"single task among blank lines", "\n  Pay rent  \n\n", []string{"Pay rent"}, false},
+		{"plain lines", "Pay rent\nCall mum", []string{"Pay rent", "Call mum"}, true},
+		{"dashes and stars", "- Pay rent\n* Call mum\n• Buy milk", []string{"Pay rent", "Call mum", "Buy milk"}, true},
+		{"numbered", "1. Pay rent\n2) Call mum", []string{"Pay rent", "Call mum"}, true},
+		{"checkboxes", "- [ ] Pay rent\n- [x] Call mum\n[] Buy milk", []string{"Pay rent", "Call mum", "Buy milk"},
+			true},
+		{"bullets alone", "-\n*\n1.", nil, false},
+		{"markers stay", "- Pay rent @fri !high\n- Gym @daily", []string{"Pay rent @fri !high", "Gym @daily"}, true},
+		{"windows line endings", "Pay rent\r\nCall mum\r\n", []string{"Pay rent", "Call mum"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bulkLines(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("bulkLines() = %q, want %q", got, tt.want)
+			}
+			if got := IsBulk(tt.text); got != tt.bulk {
+				t.Errorf("IsBulk() = %t, want %t", got, tt.bulk)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package todobot
+
+import (
+	"context"
+	"fmt"
+	"telegramBot/pkg/model/task"
+)
+
+var PageSizes = []int{5, 10, 20}
+
+func (s *TodoBot) SetPageSize(ctx context.Context, userID int64, pageSize int) error {
+	for _, size := range PageSizes {
+		if size == pageSize {
+			return s.storage.SetPageSize(ctx, userID, pageSize)
+		}
+	}
+	return fmt.Errorf("%w: %d", ErrInvalidPageSize, pageSize)
+}
+
+func (s *TodoBot) GetPageSize(ctx context.Context, userID int64) (int, error) {
+	return s.storage.GetPageSize(ctx, userID)
+}
+
+// GetTasksPage returns the requested page of the user's tasks, clamped to the
+// last existing page so a list shrunk by deletions never shows an empty page.
+func (s *TodoBot) GetTasksPage(ctx context.Context, userID int64, number int) (task.Page, error) {
+	size, err := s.storage.GetPageSize(ctx, userID)
+	if err != nil {
+		return task.Page{}, err
+	}
+	page := task.Page{Number: number, Size: size}
+	if page.Number < 0 {
+		page.Number = 0
+	}
+	page.Tasks, page.Total, err = s.storage.GetTasksPage(ctx, userID, size, page.Number*size)
+	if err != nil {
+		return task.Page{}, err
+	}
+	if page.Number > 0 && page.Number >= page.Pages() {
+		page.Number = page.Pages() - 1
+		page.Tasks, page.Total, err = s.storage.GetTasksPage(ctx, userID, size, page.Number*size)
+		if err != nil {
+			return task.Page{}, err
+		}
+	}
+	err = s.countAttachments(ctx, userID, &page)
+	if err != nil {
+		return task.Page{}, err
+	}
+	return page, nil
+}
@@ -0,0 +1,47 @@
+package todobot
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"telegramBot/pkg/model/task"
+)
+
+// PendingKind names what left tasks waiting for confirmation. Each kind has
+// its own preview, so a stale Confirm button of one cannot save another.
+type PendingKind string
+
+const (
+	PendingImport  PendingKind = "import"
+	PendingBulk    PendingKind = "bulk"
+	PendingForward PendingKind = "forward"
+)
+
+var pendingKinds = []PendingKind{PendingImport, PendingBulk, PendingForward}
+
+// ConfirmPending saves the tasks of the kind left waiting for confirmation,
+// in a single transaction, and returns how many there were.
+func (s *TodoBot) ConfirmPending(ctx context.Context, userID int64, kind PendingKind) (int, error) {
+	tasks, err := s.session.GetPending(ctx, userID, kind)
+	if err != nil {
+		return 0, err
+	}
+	taskIDs, err := s.storage.SaveTasks(ctx, tasks)
+	if err != nil {
+		return 0, err
+	}
+	for i, taskID := range taskIDs {
+		tasks[i].ID = int(taskID)
+		s.record(ctx, userID, tasks[i], task.EventCreated, tasks[i].TaskName)
+	}
+	// As in CommitDraft, the tasks are saved and a retry must not save them
+	// again; the leftover expires on its own.
+	err = s.session.DeletePending(ctx, userID, kind)
+	if err != nil {
+		s.log.Warn("ConfirmPending() -> s.session.DeletePending()", zap.Int64("userID", userID), zap.Error(err))
+	}
+	return len(taskIDs), nil
+}
+
+func (s *TodoBot) CancelPending(ctx context.Context, userID int64, kind PendingKind) error {
+	return s.session.DeletePending(ctx, userID, kind)
+}
@@ -0,0 +1,119 @@
+package todobot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"telegramBot/pkg/model/profile"
+	"telegramBot/pkg/model/task"
+	"telegramBot/pkg/model/task/priority"
+	"telegramBot/pkg/model/task/repeat"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const maxListName = 32
+
+// GetProfile returns the user's preferences, with defaults for anything they
+// never set.
+func (s *TodoBot) GetProfile(ctx context.Context, userID int64) (profile.Profile, error) {
+	return s.storage.GetProfile(ctx, userID)
+}
+
+func (s *TodoBot) updateProfile(ctx context.Context, userID int64, update func(p *profile.Profile)) error {
+	p, err := s.storage.GetProfile(ctx, userID)
+	if err != nil {
+		return err
+	}
+	update(&p)
+	return s.storage.SaveProfile(ctx, p)
+}
+
+// SetTimezone takes an IANA name such as "Europe/Kyiv".
+func (s *TodoBot) SetTimezone(ctx context.Context, userID int64, timezone string) error {
+	timezone = strings.TrimSpace(timezone)
+	location, err := time.LoadLocation(timezone)
+	if err != nil || timezone == "" || strings.EqualFold(timezone, "Local") {
+		return fmt.Errorf("%w: %q", ErrInvalidTimezone, timezone)
+	}
+	return s.updateProfile(ctx, userID, func(p *profile.Profile) { p.Timezone = location.String() })
+}
+
+func (s *TodoBot) SetDateFormat(ctx context.Context, userID int64, layout string) error {
+	for _, format := range profile.DateFormats {
+		if format == layout {
+			return s.updateProfile(ctx, userID, func(p *profile.Profile) { p.DateFormat = layout })
+		}
+	}
+	return fmt.Errorf("%w: %q", ErrInvalidDateFormat, layout)
+}
+
+// SetDefaultList names the list new tasks go to. An empty name leaves them
+// outside any list.
+func (s *TodoBot) SetDefaultList(ctx context.Context, userID int64, list string) error {
+	list = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(list), "#"))
+	if utf8.RuneCountInString(list) > maxListName || strings.HasPrefix(list, "/") ||
+		strings.IndexFunc(list, unicode.IsSpace) >= 0 {
+		return fmt.Errorf("%w: %q", ErrInvalidListName, list)
+	}
+	return s.updateProfile(ctx, userID, func(p *profile.Profile) { p.DefaultList = list })
+}
+
+// SetQuietHours takes a window such as "22:00-07:00", or "off".
+func (s *TodoBot) SetQuietHours(ctx context.Context, userID int64, window string) error {
+	var quietHours profile.QuietHours
+	if !strings.EqualFold(strings.TrimSpace(window), "off") {
+		var err error
+		quietHours, err = profile.ParseQuietHours(window)
+		if err != nil {
+			return fmt.Errorf("%w: %q: %v", ErrInvalidQuietHours, window, err)
+		}
+	}
+	return s.updateProfile(ctx, userID, func(p *profile.Profile) { p.QuietHours = quietHours })
+}
+
+// SetDigest takes the local time of the daily digest, such as "08:00", or
+// "off".
+func (s *TodoBot) SetDigest(ctx context.Context, userID int64, at string) error {
+	if strings.EqualFold(strings.TrimSpace(at), "off") {
+		return s.updateProfile(ctx, userID, func(p *profile.Profile) { p.DigestEnabled = false })
+	}
+	minute, err := profile.ParseClock(at)
+	if err != nil {
+		return fmt.Errorf("%w: %q: %v", ErrInvalidDigestTime, at, err)
+	}
+	return s.updateProfile(ctx, userID, func(p *profile.Profile) {
+		p.DigestEnabled = true
+		p.DigestAt = minute
+	})
+}
+
+// applyProfile fills in what a new task takes from its owner's profile: the
+// default list and a due date marker read in the owner's timezone. It also
+// reads the priority and repeat markers; a repeating task without a due date
+// starts today. The markers are then left out of the name.
+func (s *TodoBot) applyProfile(ctx context.Context, newTask *task.Task) error {
+	p, err := s.storage.GetProfile(ctx, newTask.ChatId)
+	if err != nil {
+		return err
+	}
+	if newTask.List == "" {
+		newTask.List = p.DefaultList
+	}
+	today := task.Day(p.Now(time.Now()))
+	if newTask.Due.IsZero() {
+		newTask.Due = parseDue(today, newTask.TaskName, newTask.TaskDescription)
+	}
+	if newTask.Repeat == repeat.None {
+		newTask.Repeat = parseRepeat(newTask.TaskName, newTask.TaskDescription)
+	}
+	if newTask.Repeat != repeat.None && newTask.Due.IsZero() {
+		newTask.Due = today
+	}
+	if newTask.Priority == priority.None {
+		newTask.Priority = parsePriority(newTask.TaskName, newTask.TaskDescription)
+	}
+	newTask.TaskName = stripMarkers(newTask.TaskName)
+	return nil
+}
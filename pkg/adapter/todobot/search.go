@@ -0,0 +1,16 @@
+package todobot
+
+import (
+	"context"
+	"strings"
+	"telegramBot/pkg/model/task"
+)
+
+func (s *TodoBot) SearchTasks(ctx context.Context, userID int64, query string,
+	limit, offset int) (task.SearchResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return task.SearchResult{}, nil
+	}
+	return s.storage.SearchTasks(ctx, userID, query, limit, offset)
+}
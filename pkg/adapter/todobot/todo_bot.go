@@ -1,95 +1,205 @@
 package todobot
 
 import (
+	"context"
+	"fmt"
+	"go.uber.org/zap"
+	"telegramBot/pkg/i18n"
+	"telegramBot/pkg/model/profile"
+	"telegramBot/pkg/model/state/user"
 	"telegramBot/pkg/model/task"
 	"telegramBot/pkg/model/task/status"
+	"time"
 )
 
 type Storage interface {
-	SetUserState(userID int64, state int) error
-	GetUserState(userID int64) (int, error)
-	CreateNewTask(userID int64) (taskID int64, err error)
-	SetTaskName(taskID int64, taskName string) (err error)
-	GetTaskName(taskID int64) (string, error)
-	SetTaskDescription(taskID int64, taskDescription string) error
-	GetTaskDescription(taskID int64) (string, error)
-	SetTaskStatus(taskID int64, taskStatus int) error
-	GetTaskIDInCreationStatus(userID int64) (int64, error)
-	DeleteTask(taskName string) (string, error)
-	DeleteNotFinishedTask(chatId int64) error
-	GetListOfTasks(userID int64) ([]task.Task, error)
+	SetUserState(ctx context.Context, userID int64, state int) error
+	GetUserState(ctx context.Context, userID int64) (int, error)
+	CountActiveConversations(ctx context.Context) (int, error)
+	SaveTask(ctx context.Context, newTask task.Task) (taskID int64, err error)
+	SaveTasks(ctx context.Context, newTasks []task.Task) (taskIDs []int64, err error)
+	SetTaskStatus(ctx context.Context, userID, taskID int64, taskStatus int) error
+	GetTask(ctx context.Context, userID, taskID int64) (task.Task, error)
+	UpdateTask(ctx context.Context, userID int64, t task.Task) error
+	DeleteTask(ctx context.Context, userID int64, taskName string) (taskID int64, err error)
+	DeleteTaskByID(ctx context.Context, userID, taskID int64) error
+	DeleteCreatingTasks(ctx context.Context, olderThan time.Time) (int64, error)
+	GetListOfTasks(ctx context.Context, userID int64) ([]task.Task, error)
+	SearchTasks(ctx context.Context, userID int64, query string, limit, offset int) (task.SearchResult, error)
+	GetTasksPage(ctx context.Context, userID int64, limit, offset int) ([]task.Task, int, error)
+	SetPageSize(ctx context.Context, userID int64, pageSize int) error
+	GetPageSize(ctx context.Context, userID int64) (int, error)
+	SetUserLanguage(ctx context.Context, userID int64, language string) error
+	GetUserLanguage(ctx context.Context, userID int64) (string, error)
+	GetProfile(ctx context.Context, userID int64) (profile.Profile, error)
+	SaveProfile(ctx context.Context, p profile.Profile) error
+	ListDigestProfiles(ctx context.Context) ([]profile.Profile, error)
+	SetDigestSent(ctx context.Context, userID int64, day string) error
+	GetCalendarToken(ctx context.Context, userID int64) (string, error)
+	SetCalendarToken(ctx context.Context, userID int64, token string) error
+	GetCalendarUser(ctx context.Context, token string) (int64, error)
+	AddAppPassword(ctx context.Context, userID int64, hash string) error
+	CountAppPasswords(ctx context.Context, userID int64) (int, error)
+	DeleteAppPasswords(ctx context.Context, userID int64) error
+	GetAppPasswordUser(ctx context.Context, hash string) (int64, error)
+	GetAttachments(ctx context.Context, userID, taskID int64) ([]task.Attachment, error)
+	CountAttachments(ctx context.Context, userID int64, taskIDs []int64) (map[int64]int, error)
+	AddEvent(ctx context.Context, e task.Event) error
+	GetEvents(ctx context.Context, userID, taskID int64) ([]task.Event, error)
+	AddComment(ctx context.Context, userID int64, c task.Comment) (commentID int64, err error)
+	GetComments(ctx context.Context, userID, taskID int64) ([]task.Comment, error)
+	GetDeletedTasks(ctx context.Context, userID int64) ([]task.Task, error)
+	RestoreTask(ctx context.Context, userID, taskID int64, deletedSince time.Time) error
+	PurgeTask(ctx context.Context, userID, taskID int64) error
+	EmptyTrash(ctx context.Context, userID int64) (purged int64, err error)
+	PurgeDeletedTasks(ctx context.Context, deletedBefore time.Time) (purged int64, err error)
+	SearchArchive(ctx context.Context, userID int64, query string, limit, offset int) (task.SearchResult, error)
+	GetArchivedTasks(ctx context.Context, userID int64) ([]task.Task, error)
+	ArchiveCompletedTasks(ctx context.Context, now time.Time) (archived []task.Task, err error)
+}
+
+type Session interface {
+	SetDraft(ctx context.Context, userID int64, draft task.Task) error
+	GetDraft(ctx context.Context, userID int64) (task.Task, error)
+	DeleteDraft(ctx context.Context, userID int64) error
+	SetPending(ctx context.Context, userID int64, kind PendingKind, tasks []task.Task) error
+	GetPending(ctx context.Context, userID int64, kind PendingKind) ([]task.Task, error)
+	DeletePending(ctx context.Context, userID int64, kind PendingKind) error
 }
 
 type TodoBot struct {
 	storage Storage
+	session Session
+	log     *zap.Logger
 }
 
-func New(database Storage) *TodoBot {
+func New(database Storage, session Session, log *zap.Logger) *TodoBot {
 	return &TodoBot{
 		storage: database,
+		session: session,
+		log:     log,
 	}
 }
 
-func (s *TodoBot) SetUserState(userID int64, state int) error {
-	return s.storage.SetUserState(userID, state)
+func (s *TodoBot) SetUserState(ctx context.Context, userID int64, state int) error {
+	return s.storage.SetUserState(ctx, userID, state)
 }
 
-func (s *TodoBot) GetUserState(userID int64) (int, error) {
-	return s.storage.GetUserState(userID)
+func (s *TodoBot) GetUserState(ctx context.Context, userID int64) (int, error) {
+	return s.storage.GetUserState(ctx, userID)
 }
 
-func (s *TodoBot) CreateNewTask(userID int64) (taskID int64, err error) {
-	taskID, err = s.storage.CreateNewTask(userID)
+// ResetConversation drops whatever the user was in the middle of: the draft
+// and the tasks waiting for confirmation go with the state, so that no stale
+// button can bring them back.
+func (s *TodoBot) ResetConversation(ctx context.Context, userID int64) error {
+	err := s.session.DeleteDraft(ctx, userID)
 	if err != nil {
-		return 0, err
+		return err
 	}
-	err = s.storage.SetTaskStatus(taskID, status.Creating)
-	if err != nil {
-		return 0, err
+	for _, kind := range pendingKinds {
+		err = s.session.DeletePending(ctx, userID, kind)
+		if err != nil {
+			return err
+		}
 	}
-	return taskID, nil
+	return s.storage.SetUserState(ctx, userID, user.Default)
 }
 
-func (s *TodoBot) SetTaskName(taskID int64, taskName string) (err error) {
-	return s.storage.SetTaskName(taskID, taskName)
+// CountActiveConversations returns how many users are in the middle of a
+// multi-step flow.
+func (s *TodoBot) CountActiveConversations(ctx context.Context) (int, error) {
+	return s.storage.CountActiveConversations(ctx)
 }
 
-func (s *TodoBot) GetTaskName(taskID int64) (string, error) {
-	return s.storage.GetTaskName(taskID)
+func (s *TodoBot) SetUserLanguage(ctx context.Context, userID int64, language string) error {
+	if !i18n.Supported(language) {
+		return fmt.Errorf("%w: %q", ErrUnsupportedLanguage, language)
+	}
+	return s.storage.SetUserLanguage(ctx, userID, language)
 }
 
-func (s *TodoBot) SetTaskDescription(taskID int64, taskDescription string) error {
-	err := s.storage.SetTaskDescription(taskID, taskDescription)
+// GetUserLanguage returns the language the user chose, or the closest
+// supported match of the client's languageCode when they never did.
+func (s *TodoBot) GetUserLanguage(ctx context.Context, userID int64, languageCode string) (string, error) {
+	language, err := s.storage.GetUserLanguage(ctx, userID)
 	if err != nil {
-		return err
+		return "", err
+	}
+	if language == "" {
+		return i18n.Match(languageCode), nil
 	}
-	err = s.storage.SetTaskStatus(taskID, status.Created)
+	return language, nil
+}
+
+// DeleteTask moves the user's only task named taskName to the trash and
+// returns its ID, which UndoDelete takes.
+func (s *TodoBot) DeleteTask(ctx context.Context, userID int64, taskName string) (int64, error) {
+	taskID, err := s.storage.DeleteTask(ctx, userID, taskName)
 	if err != nil {
-		return err
+		return 0, err
 	}
-	return nil
+	s.record(ctx, userID, task.Task{ID: int(taskID), ChatId: userID}, task.EventDeleted, "")
+	return taskID, nil
 }
 
-func (s *TodoBot) GetTaskDescription(taskID int64) (string, error) {
-	return s.storage.GetTaskDescription(taskID)
+func (s *TodoBot) DeleteTaskByID(ctx context.Context, userID, taskID int64) error {
+	err := s.storage.DeleteTaskByID(ctx, userID, taskID)
+	if err != nil {
+		return err
+	}
+	s.record(ctx, userID, task.Task{ID: int(taskID), ChatId: userID}, task.EventDeleted, "")
+	return nil
 }
 
-func (s *TodoBot) GetTaskIDInCreationStatus(userID int64) (int64, error) {
-	return s.storage.GetTaskIDInCreationStatus(userID)
+func (s *TodoBot) GetTask(ctx context.Context, userID, taskID int64) (task.Task, error) {
+	return s.storage.GetTask(ctx, userID, taskID)
 }
 
-func (s *TodoBot) DeleteTask(taskName string) (string, error) {
-	return s.storage.DeleteTask(taskName)
+func (s *TodoBot) GetListOfTasks(ctx context.Context, userID int64) ([]task.Task, error) {
+	return s.storage.GetListOfTasks(ctx, userID)
 }
 
-func (s *TodoBot) DeleteNotFinishedTask(chatId int64) error {
-	return s.storage.DeleteNotFinishedTask(chatId)
+// CompleteTask marks a task of the user done.
+func (s *TodoBot) CompleteTask(ctx context.Context, userID, taskID int64) error {
+	t, err := s.storage.GetTask(ctx, userID, taskID)
+	if err != nil {
+		return err
+	}
+	err = s.storage.SetTaskStatus(ctx, userID, taskID, status.Done)
+	if err != nil {
+		return err
+	}
+	if t.Status != status.Done {
+		s.record(ctx, userID, t, task.EventCompleted, "")
+	}
+	return nil
 }
 
-func (s *TodoBot) GetListOfTasks(userID int64) ([]task.Task, error) {
-	return s.storage.GetListOfTasks(userID)
+// ReopenTask puts a done task of the user back on the list.
+func (s *TodoBot) ReopenTask(ctx context.Context, userID, taskID int64) error {
+	t, err := s.storage.GetTask(ctx, userID, taskID)
+	if err != nil {
+		return err
+	}
+	err = s.storage.SetTaskStatus(ctx, userID, taskID, status.Created)
+	if err != nil {
+		return err
+	}
+	if t.Status != status.Created {
+		s.record(ctx, userID, t, task.EventReopened, "")
+	}
+	return nil
 }
 
-func (s *TodoBot) SetTaskStatus(taskID int64, taskStatus int) error {
-	return s.storage.SetTaskStatus(taskID, taskStatus)
+// PurgeStaleDrafts removes rows left in the Creating status by the old
+// row-per-draft flow. Nothing writes such rows anymore, so they are only
+// ever orphans from crashes or cancelled creations.
+func (s *TodoBot) PurgeStaleDrafts(ctx context.Context, olderThan time.Duration) (int64, error) {
+	purged, err := s.storage.DeleteCreatingTasks(ctx, time.Now().Add(-olderThan))
+	if err != nil {
+		return 0, err
+	}
+	s.log.Info("purged stale draft tasks", zap.Int64("count", purged))
+	return purged, nil
 }
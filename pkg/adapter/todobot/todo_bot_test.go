@@ -0,0 +1,235 @@
+package todobot_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"path/filepath"
+	"strings"
+	"telegramBot/internal/config"
+	"telegramBot/pkg/adapter/storage/sqlite"
+	"telegramBot/pkg/adapter/todobot"
+	"telegramBot/pkg/model/task"
+	"telegramBot/pkg/model/task/priority"
+	"telegramBot/pkg/model/task/repeat"
+	"telegramBot/pkg/model/task/status"
+	"testing"
+)
+
+// newTestBot runs on a fresh SQLite store.
+func newTestBot(t *testing.T, session todobot.Session) *todobot.TodoBot {
+	t.Helper()
+	db := sqlite.New(config.Config{DatabasePath: filepath.Join(t.TempDir(), "tasks.db")}, zap.NewNop())
+	return todobot.New(db, session, zap.NewNop())
+}
+
+// memorySession is the Redis session kept in maps, without expiry.
+type memorySession struct {
+	drafts  map[int64]task.Task
+	pending map[string][]task.Task
+}
+
+func newMemorySession() *memorySession {
+	return &memorySession{drafts: map[int64]task.Task{}, pending: map[string][]task.Task{}}
+}
+
+func pendingKey(userID int64, kind todobot.PendingKind) string {
+	return fmt.Sprintf("%s:%d", kind, userID)
+}
+
+func (m *memorySession) SetDraft(ctx context.Context, userID int64, draft task.Task) error {
+	m.drafts[userID] = draft
+	return nil
+}
+
+func (m *memorySession) GetDraft(ctx context.Context, userID int64) (task.Task, error) {
+	draft, ok := m.drafts[userID]
+	if !ok {
+		return task.Task{}, todobot.ErrDraftNotFound
+	}
+	return draft, nil
+}
+
+func (m *memorySession) DeleteDraft(ctx context.Context, userID int64) error {
+	delete(m.drafts, userID)
+	return nil
+}
+
+func (m *memorySession) SetPending(ctx context.Context, userID int64, kind todobot.PendingKind,
+	tasks []task.Task) error {
+	m.pending[pendingKey(userID, kind)] = tasks
+	return nil
+}
+
+func (m *memorySession) GetPending(ctx context.Context, userID int64, kind todobot.PendingKind) ([]task.Task, error) {
+	tasks, ok := m.pending[pendingKey(userID, kind)]
+	if !ok {
+		return nil, todobot.ErrPendingNotFound
+	}
+	return tasks, nil
+}
+
+func (m *memorySession) DeletePending(ctx context.Context, userID int64, kind todobot.PendingKind) error {
+	delete(m.pending, pendingKey(userID, kind))
+	return nil
+}
+
+func TestCompleteAndReopenTask(t *testing.T) {
+	ctx := context.Background()
+	bot := newTestBot(t, nil)
+	created, err := bot.SyncTask(ctx, 42, task.Task{TaskName: "Pay rent"})
+	if err != nil {
+		t.Fatalf("SyncTask() error = %v", err)
+	}
+	taskID := int64(created.ID)
+
+	steps := []struct {
+		name   string
+		change func(ctx context.Context, userID, taskID int64) error
+		want   int
+	}{
+		{"complete", bot.CompleteTask, status.Done},
+		{"complete again", bot.CompleteTask, status.Done},
+		{"reopen", bot.ReopenTask, status.Created},
+		{"reopen again", bot.ReopenTask, status.Created},
+	}
+	for _, step := range steps {
+		err := step.change(ctx, 42, taskID)
+		if err != nil {
+			t.Fatalf("%s: error = %v", step.name, err)
+		}
+		got, err := bot.GetTask(ctx, 42, taskID)
+		if err != nil {
+			t.Fatalf("GetTask() error = %v", err)
+		}
+		if got.Status != step.want {
+			t.Errorf("%s: status = %d, want %d", step.name, got.Status, step.want)
+		}
+	}
+
+	events, err := bot.GetTaskHistory(ctx, 42, taskID)
+	if err != nil {
+		t.Fatalf("GetTaskHistory() error = %v", err)
+	}
+	var kinds []string
+	for _, event := range events {
+		kinds = append(kinds, event.Kind)
+	}
+	want := []string{task.EventCreated, task.EventCompleted, task.EventReopened}
+	if strings.Join(kinds, " ") != strings.Join(want, " ") {
+		t.Errorf("history = %v, want %v", kinds, want)
+	}
+
+	for _, change := range []func(ctx context.Context, userID, taskID int64) error{bot.CompleteTask, bot.ReopenTask} {
+		if err := change(ctx, 43, taskID); !errors.Is(err, todobot.ErrNotOwner) {
+			t.Errorf("another user's task: error = %v, want ErrNotOwner", err)
+		}
+	}
+}
+
+func TestConfirmPendingByKind(t *testing.T) {
+	ctx := context.Background()
+	bot := newTestBot(t, newMemorySession())
+	_, err := bot.PrepareBulk(ctx, 42, "Pay rent\nCall mum")
+	if err != nil {
+		t.Fatalf("PrepareBulk() error = %v", err)
+	}
+	_, err = bot.PrepareForward(ctx, 42, todobot.Forward{Text: "Buy milk"})
+	if err != nil {
+		t.Fatalf("PrepareForward() error = %v", err)
+	}
+
+	// A stale import button finds no import, whatever else is waiting.
+	_, err = bot.ConfirmPending(ctx, 42, todobot.PendingImport)
+	if !errors.Is(err, todobot.ErrPendingNotFound) {
+		t.Errorf("ConfirmPending(import) error = %v, want ErrPendingNotFound", err)
+	}
+	saved, err := bot.ConfirmPending(ctx, 42, todobot.PendingBulk)
+	if err != nil || saved != 2 {
+		t.Errorf("ConfirmPending(bulk) = %d, %v, want 2", saved, err)
+	}
+	_, err = bot.ConfirmPending(ctx, 42, todobot.PendingBulk)
+	if !errors.Is(err, todobot.ErrPendingNotFound) {
+		t.Errorf("ConfirmPending(bulk) twice: error = %v, want ErrPendingNotFound", err)
+	}
+
+	err = bot.ResetConversation(ctx, 42)
+	if err != nil {
+		t.Fatalf("ResetConversation() error = %v", err)
+	}
+	_, err = bot.ConfirmPending(ctx, 42, todobot.PendingForward)
+	if !errors.Is(err, todobot.ErrPendingNotFound) {
+		t.Errorf("ConfirmPending(forward) after a reset: error = %v, want ErrPendingNotFound", err)
+	}
+	tasks, err := bot.GetListOfTasks(ctx, 42)
+	if err != nil {
+		t.Fatalf("GetListOfTasks() error = %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Errorf("tasks = %+v, want the two of the bulk", tasks)
+	}
+}
+
+func TestNewTaskMarkers(t *testing.T) {
+	ctx := context.Background()
+	bot := newTestBot(t, newMemorySession())
+	ways := []struct {
+		name   string
+		create func(name string) (task.Task, error)
+	}{
+		{"create", func(name string) (task.Task, error) {
+			taskID, err := bot.CreateTask(ctx, 42, name, "")
+			if err != nil {
+				return task.Task{}, err
+			}
+			return bot.GetTask(ctx, 42, taskID)
+		}},
+		{"draft", func(name string) (task.Task, error) {
+			err := bot.StartDraft(ctx, 42)
+			if err == nil {
+				err = bot.SetDraftName(ctx, 42, name)
+			}
+			if err != nil {
+				return task.Task{}, err
+			}
+			taskID, err := bot.CommitDraft(ctx, 42)
+			if err != nil {
+				return task.Task{}, err
+			}
+			return bot.GetTask(ctx, 42, taskID)
+		}},
+		{"bulk", func(name string) (task.Task, error) {
+			tasks, err := bot.PrepareBulk(ctx, 42, name)
+			if err != nil {
+				return task.Task{}, err
+			}
+			return tasks[0], nil
+		}},
+	}
+	tests := []struct {
+		name     string
+		want     string
+		priority int
+		repeat   string
+		due      bool
+	}{
+		{"Pay rent", "Pay rent", priority.None, repeat.None, false},
+		{"Pay rent @tomorrow !high #bills", "Pay rent #bills", priority.High, repeat.None, true},
+		{"Pay rent @monthly", "Pay rent", priority.None, repeat.Monthly, true},
+		{"!high @tomorrow", "!high @tomorrow", priority.High, repeat.None, true},
+	}
+	for _, way := range ways {
+		for _, tt := range tests {
+			got, err := way.create(tt.name)
+			if err != nil {
+				t.Fatalf("%s %q: error = %v", way.name, tt.name, err)
+			}
+			if got.TaskName != tt.want || got.Priority != tt.priority || got.Repeat != tt.repeat ||
+				got.Due.IsZero() == tt.due {
+				t.Errorf("%s %q = %q, priority %d, repeat %q, due %v, want %q, %d, %q, due set %t", way.name,
+					tt.name, got.TaskName, got.Priority, got.Repeat, got.Due, tt.want, tt.priority, tt.repeat, tt.due)
+			}
+		}
+	}
+}
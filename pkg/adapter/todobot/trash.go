@@ -0,0 +1,93 @@
+package todobot
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"telegramBot/pkg/clock"
+	"telegramBot/pkg/model/task"
+	"time"
+)
+
+// UndoWindow is how long after a deletion its Undo button still works.
+// Later, the task can only be restored from the trash.
+const UndoWindow = 5 * time.Minute
+
+// TrashPurgeInterval is how often TrashPurger looks for expired tasks.
+const TrashPurgeInterval = time.Hour
+
+// UndoDelete restores a task deleted less than UndoWindow ago.
+func (s *TodoBot) UndoDelete(ctx context.Context, userID, taskID int64) error {
+	return s.restoreTask(ctx, userID, taskID, time.Now().Add(-UndoWindow))
+}
+
+// RestoreTask takes a task out of the trash, however long it has been there.
+func (s *TodoBot) RestoreTask(ctx context.Context, userID, taskID int64) error {
+	return s.restoreTask(ctx, userID, taskID, time.Time{})
+}
+
+func (s *TodoBot) restoreTask(ctx context.Context, userID, taskID int64, deletedSince time.Time) error {
+	err := s.storage.RestoreTask(ctx, userID, taskID, deletedSince)
+	if err != nil {
+		return err
+	}
+	s.record(ctx, userID, task.Task{ID: int(taskID), ChatId: userID}, task.EventRestored, "")
+	return nil
+}
+
+// GetTrash returns the user's deleted tasks, most recently deleted first.
+func (s *TodoBot) GetTrash(ctx context.Context, userID int64) ([]task.Task, error) {
+	return s.storage.GetDeletedTasks(ctx, userID)
+}
+
+func (s *TodoBot) PurgeTask(ctx context.Context, userID, taskID int64) error {
+	return s.storage.PurgeTask(ctx, userID, taskID)
+}
+
+func (s *TodoBot) EmptyTrash(ctx context.Context, userID int64) (int64, error) {
+	return s.storage.EmptyTrash(ctx, userID)
+}
+
+// TrashPurger deletes for good the tasks that have been in the trash for
+// longer than the retention period, checking every interval of its clock.
+type TrashPurger struct {
+	todoBot   *TodoBot
+	clock     clock.Clock
+	interval  time.Duration
+	retention time.Duration
+}
+
+func NewTrashPurger(todoBot *TodoBot, clock clock.Clock, retention time.Duration) *TrashPurger {
+	return &TrashPurger{
+		todoBot:   todoBot,
+		clock:     clock,
+		interval:  TrashPurgeInterval,
+		retention: retention,
+	}
+}
+
+func (p *TrashPurger) Run(ctx context.Context) {
+	for {
+		err := p.Tick(ctx)
+		if err != nil {
+			p.todoBot.log.Error("TrashPurger.Run() -> p.Tick()", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.clock.After(p.interval):
+		}
+	}
+}
+
+// Tick purges the tasks whose retention has run out at the clock's current
+// time.
+func (p *TrashPurger) Tick(ctx context.Context) error {
+	purged, err := p.todoBot.storage.PurgeDeletedTasks(ctx, p.clock.Now().Add(-p.retention))
+	if err != nil {
+		return err
+	}
+	if purged > 0 {
+		p.todoBot.log.Info("purged tasks from the trash", zap.Int64("count", purged))
+	}
+	return nil
+}
@@ -0,0 +1,281 @@
+package tracing
+
+import (
+	"context"
+	"telegramBot/pkg/adapter/todobot"
+	"telegramBot/pkg/model/profile"
+	"telegramBot/pkg/model/task"
+	"time"
+)
+
+// Storage opens a child span for every call into the wrapped
+// todobot.Storage.
+type Storage struct {
+	next todobot.Storage
+}
+
+func NewStorage(next todobot.Storage) *Storage {
+	return &Storage{next: next}
+}
+
+func (s *Storage) SetUserState(ctx context.Context, userID int64, state int) (err error) {
+	ctx, span := Start(ctx, "Storage.SetUserState")
+	defer func() { End(span, err) }()
+	return s.next.SetUserState(ctx, userID, state)
+}
+
+func (s *Storage) GetUserState(ctx context.Context, userID int64) (state int, err error) {
+	ctx, span := Start(ctx, "Storage.GetUserState")
+	defer func() { End(span, err) }()
+	return s.next.GetUserState(ctx, userID)
+}
+
+func (s *Storage) CountActiveConversations(ctx context.Context) (count int, err error) {
+	ctx, span := Start(ctx, "Storage.CountActiveConversations")
+	defer func() { End(span, err) }()
+	return s.next.CountActiveConversations(ctx)
+}
+
+func (s *Storage) SaveTask(ctx context.Context, newTask task.Task) (taskID int64, err error) {
+	ctx, span := Start(ctx, "Storage.SaveTask")
+	defer func() { End(span, err) }()
+	return s.next.SaveTask(ctx, newTask)
+}
+
+func (s *Storage) SaveTasks(ctx context.Context, newTasks []task.Task) (taskIDs []int64, err error) {
+	ctx, span := Start(ctx, "Storage.SaveTasks")
+	defer func() { End(span, err) }()
+	return s.next.SaveTasks(ctx, newTasks)
+}
+
+func (s *Storage) SetTaskStatus(ctx context.Context, userID, taskID int64, taskStatus int) (err error) {
+	ctx, span := Start(ctx, "Storage.SetTaskStatus")
+	defer func() { End(span, err) }()
+	return s.next.SetTaskStatus(ctx, userID, taskID, taskStatus)
+}
+
+func (s *Storage) GetTask(ctx context.Context, userID, taskID int64) (t task.Task, err error) {
+	ctx, span := Start(ctx, "Storage.GetTask")
+	defer func() { End(span, err) }()
+	return s.next.GetTask(ctx, userID, taskID)
+}
+
+func (s *Storage) DeleteTask(ctx context.Context, userID int64, taskName string) (taskID int64, err error) {
+	ctx, span := Start(ctx, "Storage.DeleteTask")
+	defer func() { End(span, err) }()
+	return s.next.DeleteTask(ctx, userID, taskName)
+}
+
+func (s *Storage) DeleteTaskByID(ctx context.Context, userID, taskID int64) (err error) {
+	ctx, span := Start(ctx, "Storage.DeleteTaskByID")
+	defer func() { End(span, err) }()
+	return s.next.DeleteTaskByID(ctx, userID, taskID)
+}
+
+func (s *Storage) DeleteCreatingTasks(ctx context.Context, olderThan time.Time) (deleted int64, err error) {
+	ctx, span := Start(ctx, "Storage.DeleteCreatingTasks")
+	defer func() { End(span, err) }()
+	return s.next.DeleteCreatingTasks(ctx, olderThan)
+}
+
+func (s *Storage) GetListOfTasks(ctx context.Context, userID int64) (tasks []task.Task, err error) {
+	ctx, span := Start(ctx, "Storage.GetListOfTasks")
+	defer func() { End(span, err) }()
+	return s.next.GetListOfTasks(ctx, userID)
+}
+
+func (s *Storage) SearchTasks(ctx context.Context, userID int64, query string,
+	limit, offset int) (result task.SearchResult, err error) {
+	ctx, span := Start(ctx, "Storage.SearchTasks")
+	defer func() { End(span, err) }()
+	return s.next.SearchTasks(ctx, userID, query, limit, offset)
+}
+
+func (s *Storage) GetTasksPage(ctx context.Context, userID int64,
+	limit, offset int) (tasks []task.Task, total int, err error) {
+	ctx, span := Start(ctx, "Storage.GetTasksPage")
+	defer func() { End(span, err) }()
+	return s.next.GetTasksPage(ctx, userID, limit, offset)
+}
+
+func (s *Storage) SetPageSize(ctx context.Context, userID int64, pageSize int) (err error) {
+	ctx, span := Start(ctx, "Storage.SetPageSize")
+	defer func() { End(span, err) }()
+	return s.next.SetPageSize(ctx, userID, pageSize)
+}
+
+func (s *Storage) GetPageSize(ctx context.Context, userID int64) (pageSize int, err error) {
+	ctx, span := Start(ctx, "Storage.GetPageSize")
+	defer func() { End(span, err) }()
+	return s.next.GetPageSize(ctx, userID)
+}
+
+func (s *Storage) SetUserLanguage(ctx context.Context, userID int64, language string) (err error) {
+	ctx, span := Start(ctx, "Storage.SetUserLanguage")
+	defer func() { End(span, err) }()
+	return s.next.SetUserLanguage(ctx, userID, language)
+}
+
+func (s *Storage) GetUserLanguage(ctx context.Context, userID int64) (language string, err error) {
+	ctx, span := Start(ctx, "Storage.GetUserLanguage")
+	defer func() { End(span, err) }()
+	return s.next.GetUserLanguage(ctx, userID)
+}
+
+func (s *Storage) GetProfile(ctx context.Context, userID int64) (p profile.Profile, err error) {
+	ctx, span := Start(ctx, "Storage.GetProfile")
+	defer func() { End(span, err) }()
+	return s.next.GetProfile(ctx, userID)
+}
+
+func (s *Storage) SaveProfile(ctx context.Context, p profile.Profile) (err error) {
+	ctx, span := Start(ctx, "Storage.SaveProfile")
+	defer func() { End(span, err) }()
+	return s.next.SaveProfile(ctx, p)
+}
+
+func (s *Storage) ListDigestProfiles(ctx context.Context) (profiles []profile.Profile, err error) {
+	ctx, span := Start(ctx, "Storage.ListDigestProfiles")
+	defer func() { End(span, err) }()
+	return s.next.ListDigestProfiles(ctx)
+}
+
+func (s *Storage) SetDigestSent(ctx context.Context, userID int64, day string) (err error) {
+	ctx, span := Start(ctx, "Storage.SetDigestSent")
+	defer func() { End(span, err) }()
+	return s.next.SetDigestSent(ctx, userID, day)
+}
+
+func (s *Storage) GetCalendarToken(ctx context.Context, userID int64) (token string, err error) {
+	ctx, span := Start(ctx, "Storage.GetCalendarToken")
+	defer func() { End(span, err) }()
+	return s.next.GetCalendarToken(ctx, userID)
+}
+
+func (s *Storage) SetCalendarToken(ctx context.Context, userID int64, token string) (err error) {
+	ctx, span := Start(ctx, "Storage.SetCalendarToken")
+	defer func() { End(span, err) }()
+	return s.next.SetCalendarToken(ctx, userID, token)
+}
+
+func (s *Storage) GetCalendarUser(ctx context.Context, token string) (userID int64, err error) {
+	ctx, span := Start(ctx, "Storage.GetCalendarUser")
+	defer func() { End(span, err) }()
+	return s.next.GetCalendarUser(ctx, token)
+}
+
+func (s *Storage) UpdateTask(ctx context.Context, userID int64, t task.Task) (err error) {
+	ctx, span := Start(ctx, "Storage.UpdateTask")
+	defer func() { End(span, err) }()
+	return s.next.UpdateTask(ctx, userID, t)
+}
+
+func (s *Storage) AddAppPassword(ctx context.Context, userID int64, hash string) (err error) {
+	ctx, span := Start(ctx, "Storage.AddAppPassword")
+	defer func() { End(span, err) }()
+	return s.next.AddAppPassword(ctx, userID, hash)
+}
+
+func (s *Storage) CountAppPasswords(ctx context.Context, userID int64) (count int, err error) {
+	ctx, span := Start(ctx, "Storage.CountAppPasswords")
+	defer func() { End(span, err) }()
+	return s.next.CountAppPasswords(ctx, userID)
+}
+
+func (s *Storage) DeleteAppPasswords(ctx context.Context, userID int64) (err error) {
+	ctx, span := Start(ctx, "Storage.DeleteAppPasswords")
+	defer func() { End(span, err) }()
+	return s.next.DeleteAppPasswords(ctx, userID)
+}
+
+func (s *Storage) GetAppPasswordUser(ctx context.Context, hash string) (userID int64, err error) {
+	ctx, span := Start(ctx, "Storage.GetAppPasswordUser")
+	defer func() { End(span, err) }()
+	return s.next.GetAppPasswordUser(ctx, hash)
+}
+
+func (s *Storage) GetAttachments(ctx context.Context, userID, taskID int64) (attachments []task.Attachment, err error) {
+	ctx, span := Start(ctx, "Storage.GetAttachments")
+	defer func() { End(span, err) }()
+	return s.next.GetAttachments(ctx, userID, taskID)
+}
+
+func (s *Storage) CountAttachments(ctx context.Context, userID int64,
+	taskIDs []int64) (counts map[int64]int, err error) {
+	ctx, span := Start(ctx, "Storage.CountAttachments")
+	defer func() { End(span, err) }()
+	return s.next.CountAttachments(ctx, userID, taskIDs)
+}
+
+func (s *Storage) AddEvent(ctx context.Context, e task.Event) (err error) {
+	ctx, span := Start(ctx, "Storage.AddEvent")
+	defer func() { End(span, err) }()
+	return s.next.AddEvent(ctx, e)
+}
+
+func (s *Storage) GetEvents(ctx context.Context, userID, taskID int64) (events []task.Event, err error) {
+	ctx, span := Start(ctx, "Storage.GetEvents")
+	defer func() { End(span, err) }()
+	return s.next.GetEvents(ctx, userID, taskID)
+}
+
+func (s *Storage) AddComment(ctx context.Context, userID int64, c task.Comment) (commentID int64, err error) {
+	ctx, span := Start(ctx, "Storage.AddComment")
+	defer func() { End(span, err) }()
+	return s.next.AddComment(ctx, userID, c)
+}
+
+func (s *Storage) GetComments(ctx context.Context, userID, taskID int64) (comments []task.Comment, err error) {
+	ctx, span := Start(ctx, "Storage.GetComments")
+	defer func() { End(span, err) }()
+	return s.next.GetComments(ctx, userID, taskID)
+}
+
+func (s *Storage) GetDeletedTasks(ctx context.Context, userID int64) (tasks []task.Task, err error) {
+	ctx, span := Start(ctx, "Storage.GetDeletedTasks")
+	defer func() { End(span, err) }()
+	return s.next.GetDeletedTasks(ctx, userID)
+}
+
+func (s *Storage) RestoreTask(ctx context.Context, userID, taskID int64, deletedSince time.Time) (err error) {
+	ctx, span := Start(ctx, "Storage.RestoreTask")
+	defer func() { End(span, err) }()
+	return s.next.RestoreTask(ctx, userID, taskID, deletedSince)
+}
+
+func (s *Storage) PurgeTask(ctx context.Context, userID, taskID int64) (err error) {
+	ctx, span := Start(ctx, "Storage.PurgeTask")
+	defer func() { End(span, err) }()
+	return s.next.PurgeTask(ctx, userID, taskID)
+}
+
+func (s *Storage) EmptyTrash(ctx context.Context, userID int64) (purged int64, err error) {
+	ctx, span := Start(ctx, "Storage.EmptyTrash")
+	defer func() { End(span, err) }()
+	return s.next.EmptyTrash(ctx, userID)
+}
+
+func (s *Storage) PurgeDeletedTasks(ctx context.Context, deletedBefore time.Time) (purged int64, err error) {
+	ctx, span := Start(ctx, "Storage.PurgeDeletedTasks")
+	defer func() { End(span, err) }()
+	return s.next.PurgeDeletedTasks(ctx, deletedBefore)
+}
+
+func (s *Storage) SearchArchive(ctx context.Context, userID int64, query string,
+	limit, offset int) (result task.SearchResult, err error) {
+	ctx, span := Start(ctx, "Storage.SearchArchive")
+	defer func() { End(span, err) }()
+	return s.next.SearchArchive(ctx, userID, query, limit, offset)
+}
+
+func (s *Storage) GetArchivedTasks(ctx context.Context, userID int64) (tasks []task.Task, err error) {
+	ctx, span := Start(ctx, "Storage.GetArchivedTasks")
+	defer func() { End(span, err) }()
+	return s.next.GetArchivedTasks(ctx, userID)
+}
+
+func (s *Storage) ArchiveCompletedTasks(ctx context.Context, now time.Time) (archived []task.Task, err error) {
+	ctx, span := Start(ctx, "Storage.ArchiveCompletedTasks")
+	defer func() { End(span, err) }()
+	return s.next.ArchiveCompletedTasks(ctx, now)
+}
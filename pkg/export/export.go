@@ -0,0 +1,183 @@
+// Package export writes tasks as files people can keep or load elsewhere. It
+// only depends on the task model, so every frontend can offer the same
+// formats.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"telegramBot/pkg/model/task"
+	"telegramBot/pkg/model/task/priority"
+	"telegramBot/pkg/model/task/status"
+	"time"
+)
+
+type Format string
+
+const (
+	JSON     Format = "json"
+	CSV      Format = "csv"
+	Markdown Format = "md"
+)
+
+var Formats = []Format{JSON, CSV, Markdown}
+
+// Version is bumped whenever Document changes incompatibly.
+const Version = 1
+
+// Document is the JSON export, meant to be read back by an import.
+type Document struct {
+	Version  int       `json:"version"`
+	Exported time.Time `json:"exported"`
+	Tasks    []Task    `json:"tasks"`
+}
+
+type Task struct {
+	ID          int      `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Status      string   `json:"status"`
+	Priority    string   `json:"priority,omitempty"`
+	List        string   `json:"list,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Due         string   `json:"due,omitempty"`
+	Repeat      string   `json:"repeat,omitempty"`
+	Created     string   `json:"created,omitempty"`
+}
+
+// CSVHeader names the CSV columns, in order.
+var CSVHeader = []string{"id", "name", "description", "status", "priority", "list", "tags", "due", "repeat", "created"}
+
+// Status names a task status in every format.
+func Status(taskStatus int) string {
+	if taskStatus == status.Done || taskStatus == status.Archived {
+		return "done"
+	}
+	return "open"
+}
+
+func Supported(format Format) bool {
+	for _, supported := range Formats {
+		if supported == format {
+			return true
+		}
+	}
+	return false
+}
+
+func (f Format) FileName() string {
+	return "tasks." + string(f)
+}
+
+// Write writes tasks in format to w. Tasks still being created are left out.
+func Write(w io.Writer, format Format, tasks []task.Task, now time.Time) error {
+	records := make([]Task, 0, len(tasks))
+	for _, t := range tasks {
+		if t.Status == status.Creating {
+			continue
+		}
+		records = append(records, record(t))
+	}
+	switch format {
+	case JSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(Document{Version: Version, Exported: now.UTC(), Tasks: records})
+	case CSV:
+		return writeCSV(w, records)
+	case Markdown:
+		return writeMarkdown(w, records)
+	}
+	return fmt.Errorf("unsupported format %q", format)
+}
+
+func record(t task.Task) Task {
+	r := Task{
+		ID:          t.ID,
+		Name:        t.TaskName,
+		Description: t.TaskDescription,
+		Status:      Status(t.Status),
+		Priority:    priority.Name(t.Priority),
+		List:        t.List,
+		Tags:        t.Tags,
+		Repeat:      t.Repeat,
+	}
+	if !t.Due.IsZero() {
+		r.Due = t.Due.Format(task.DateLayout)
+	}
+	if !t.CreatedAt.IsZero() {
+		r.Created = t.CreatedAt.UTC().Format(time.RFC3339)
+	}
+	return r
+}
+
+func writeCSV(w io.Writer, records []Task) error {
+	writer := csv.NewWriter(w)
+	err := writer.Write(CSVHeader)
+	if err != nil {
+		return err
+	}
+	for _, r := range records {
+		err := writer.Write([]string{strconv.Itoa(r.ID), r.Name, r.Description, r.Status, r.Priority, r.List,
+			strings.Join(r.Tags, " "), r.Due, r.Repeat, r.Created})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeMarkdown writes a checklist per list, tasks without a list first.
+func writeMarkdown(w io.Writer, records []Task) error {
+	lists := make(map[string][]Task)
+	var names []string
+	for _, r := range records {
+		if _, ok := lists[r.List]; !ok {
+			names = append(names, r.List)
+		}
+		lists[r.List] = append(lists[r.List], r)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("# Tasks\n")
+	for _, name := range names {
+		if name != "" {
+			fmt.Fprintf(&b, "\n## %s\n", name)
+		}
+		b.WriteString("\n")
+		for _, r := range lists[name] {
+			check := " "
+			if r.Status == "done" {
+				check = "x"
+			}
+			fmt.Fprintf(&b, "- [%s] %s", check, markdownLine(r.Name))
+			if r.Due != "" {
+				fmt.Fprintf(&b, " (due %s)", r.Due)
+			}
+			if r.Repeat != "" {
+				fmt.Fprintf(&b, " (%s)", r.Repeat)
+			}
+			b.WriteString("\n")
+			for _, line := range strings.Split(strings.TrimSpace(r.Description), "\n") {
+				if line != "" {
+					fmt.Fprintf(&b, "  %s\n", markdownLine(line))
+				}
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownLine keeps text from turning into markup it was not meant to be.
+func markdownLine(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`, "*", `\*`, "_", `\_`, "`", "\\`").
+		Replace(strings.TrimSpace(s))
+}
@@ -0,0 +1,140 @@
+package export
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"telegramBot/pkg/model/task"
+	"telegramBot/pkg/model/task/priority"
+	"telegramBot/pkg/model/task/repeat"
+	"telegramBot/pkg/model/task/status"
+	"testing"
+	"time"
+)
+
+var (
+	testNow   = time.Date(2026, 3, 2, 9, 30, 0, 0, time.FixedZone("EET", 2*60*60))
+	testTasks = []task.Task{
+		{ID: 1, TaskName: "Pay rent", TaskDescription: "Bank, \"main\" account", Status: status.Created,
+			Priority: priority.High, List: "home", Tags: []string{"bills", "money"},
+			Due: time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC), Repeat: repeat.Monthly,
+			CreatedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.FixedZone("EET", 2*60*60))},
+		{ID: 2, TaskName: "Read *Dune*", TaskDescription: "\nchapter [1]\n\nnotes_2\n", Status: status.Done},
+		{ID: 3, TaskName: "Half written", Status: status.Creating},
+		{ID: 4, TaskName: "Buy milk", Status: status.Archived, List: "shop"},
+	}
+)
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		format Format
+		want   string
+	}{
+		{CSV, `id,name,description,status,priority,list,tags,due,repeat,created
+1,Pay rent,"Bank, ""main"" account",open,high,home,bills money,2026-03-05,monthly,2026-03-01T10:00:00Z
+2,Read *Dune*,"
+chapter [1]
+
+notes_2
+",done,,,,,,
+4,Buy milk,,done,,shop,,,,
+`},
+		{Markdown, `# Tasks
+
+- [x] Read \*Dune\*
+  chapter \[1\]
+  notes\_2
+
+## home
+
+- [ ] Pay rent (due 2026-03-05) (monthly)
+  Bank, "main" account
+
+## shop
+
+- [x] Buy milk
+`},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var out strings.Builder
+			err := Write(&out, tt.format, testTasks, testNow)
+			if err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("Write() =\n%s\nwant\n%s", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var out strings.Builder
+	err := Write(&out, JSON, testTasks, testNow)
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	var got Document
+	err = json.Unmarshal([]byte(out.String()), &got)
+	if err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	want := Document{
+		Version:  Version,
+		Exported: testNow.UTC(),
+		Tasks: []Task{
+			{ID: 1, Name: "Pay rent", Description: "Bank, \"main\" account", Status: "open", Priority: "high",
+				List: "home", Tags: []string{"bills", "money"}, Due: "2026-03-05", Repeat: "monthly",
+				Created: "2026-03-01T10:00:00Z"},
+			{ID: 2, Name: "Read *Dune*", Description: "\nchapter [1]\n\nnotes_2\n", Status: "done"},
+			{ID: 4, Name: "Buy milk", Status: "done", List: "shop"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Write() = %+v, want %+v", got, want)
+	}
+}
+
+func TestWriteEmpty(t *testing.T) {
+	tests := []struct {
+		format Format
+		want   string
+	}{
+		{JSON, `"tasks": []`},
+		{CSV, strings.Join(CSVHeader, ",") + "\n"},
+		{Markdown, "# Tasks\n"},
+	}
+	for _, tt := range tests {
+		var out strings.Builder
+		err := Write(&out, tt.format, nil, testNow)
+		if err != nil || !strings.Contains(out.String(), tt.want) {
+			t.Errorf("Write(%s) = %q, %v, want %q", tt.format, out.String(), err, tt.want)
+		}
+	}
+}
+
+func TestSupported(t *testing.T) {
+	tests := []struct {
+		format Format
+		want   bool
+	}{
+		{JSON, true},
+		{CSV, true},
+		{Markdown, true},
+		{"xml", false},
+		{"JSON", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := Supported(tt.format); got != tt.want {
+			t.Errorf("Supported(%q) = %t, want %t", tt.format, got, tt.want)
+		}
+		if !tt.want {
+			err := Write(&strings.Builder{}, tt.format, testTasks, testNow)
+			if err == nil {
+				t.Errorf("Write(%q) error = nil, want an unsupported format", tt.format)
+			}
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package i18n
+
+var en = translation{
+	plural: func(n int) Form {
+		if n == 1 {
+			return One
+		}
+		return Other
+	},
+	messages: map[string]string{
+		Menu:                "Menu:",
+		Hello:               "Hello %s!",
+		SendTaskName:        "Send task name. Photos, files and voice messages sent now are attached to the task",
+		SendTaskDescription: "Send task description",
+		CancelTaskCreation:  "Cancel task creation",
+		CancelTaskDeletion:  "Cancel task deletion",
+		Cancel:              "Cancel",
+		FinishLastAction:    "Finish your last action or /cancel",
+		LastActionCanceled:  "Last action canceled",
+		NothingToCancel:     "There is nothing to cancel",
+		TaskCreated:         "Task created",
+		TaskDeleted:         "Task deleted successfully",
+		TaskNotFound:        "There is no such Task",
+		ButtonNewTask:       "➕ New task",
+		ButtonListOfTasks:   "📋 List of tasks",
+		ButtonDeleteTask:    "🗑 Delete task",
+		ButtonDeleteThis:    "Delete this task",
+		ButtonComplete:      "✅ Done",
+		ButtonReopen:        "↩️ Reopen",
+		TaskStatusDone:      "Done",
+		ButtonBack:          "◀ Back",
+		NoTasks:             "You have no tasks",
+		SearchUsage:         "Send /search followed by what to look for",
+		SearchNothing:       "Nothing found for \"%s\"",
+		SearchExpired:       "This search has expired. Send /search followed by what to look for",
+		TasksPerPage:        "Tasks per page:",
+		TasksPerPageSet:     "Tasks per page: %d",
+		ChooseLanguage:      "Choose your language:",
+		LanguageSet:         "Language: English",
+		AmbiguousTaskName:   "Several tasks have this name, delete the right one from the list",
+		NotYourTask:         "This task belongs to someone else",
+		DraftExpired:        "The task you were creating has expired, please start again",
+		EmptyTaskName:       "The task name can't be empty",
+		InvalidPageSize:     "This page size is not supported",
+		UnsupportedLanguage: "This language is not supported",
+		SomethingWentWrong:  "Something went wrong, please try again (ref. %s)",
+		ButtonRetry:         "🔁 Retry",
+		SettingsHeader:      "⚙️ Settings",
+		SettingTimezone:     "🌍 Timezone: %s, now %s",
+		SettingLanguage:     "🗣 Language: %s",
+		SettingDateFormat:   "📅 Date format: %s",
+		SettingDefaultList:  "📂 Default list: %s",
+		SettingQuietHours:   "🌙 Quiet hours: %s",
+		SettingPageSize:     "📄 Tasks per page: %d",
+		SettingOff:          "off",
+		SettingNoList:       "none",
+		SettingSaved:        "Saved",
+		ButtonTimezone:      "🌍 Timezone",
+		ButtonLanguage:      "🗣 Language",
+		ButtonDateFormat:    "📅 Date format",
+		ButtonDefaultList:   "📂 Default list",
+		ButtonQuietHours:    "🌙 Quiet hours",
+		ButtonPageSize:      "📄 Page size",
+		ButtonNoList:        "No list",
+		ButtonTurnOff:       "Turn off",
+		SendTimezone:        "Send your timezone as a name like Europe/Kyiv, or pick one:",
+		ChooseDateFormat:    "Choose a date format:",
+		SendDefaultList:     "Send the one-word name of the list new tasks go to",
+		SendQuietHours:      "Send the hours the bot stays silent, like 22:00-07:00",
+		InvalidTimezone:     "Unknown timezone",
+		InvalidDateFormat:   "This date format is not supported",
+		InvalidListName:     "A list name is one word of up to 32 characters",
+		InvalidQuietHours:   "Quiet hours look like 22:00-07:00",
+		SettingDigest:       "☀️ Daily digest: %s",
+		ButtonDigest:        "☀️ Daily digest",
+		SendDigestTime:      "Send the local time for your daily digest, like 08:00",
+		InvalidDigestTime:   "A time looks like 08:00",
+		DigestHeader:        "☀️ Your day, %s",
+		DigestOverdue:       "⏰ Overdue: %d",
+		DigestDueToday:      "📅 Due today: %d",
+		DigestNothingDue:    "Nothing is due today",
+		DigestLists:         "📂 Open tasks: %s",
+		DigestNoList:        "no list",
+		DigestMore:          "…and %d more",
+		TaskDone:            "Task done",
+		ChooseExportFormat:  "Choose the file format for your tasks:",
+		ExportCaption:       "Your tasks, %s",
+		UnsupportedFormat:   "This file format is not supported",
+		SendImportFile:      "Send a JSON, CSV or todo.txt file with your tasks",
+		SendFileAsDocument:  "Please send the tasks as a file",
+		UnreadableFile:      "This file could not be read",
+		ImportTooLarge:      "This file is too large, the limit is 1 MB",
+		PendingExpired:      "The tasks waiting for confirmation have expired, please send them again",
+		ImportPreview:       "📥 %s: %d tasks to import, %d open and %d done",
+		ImportFailed:        "⚠️ Rows that could not be read: %d",
+		ImportFailure:       "line %d: %s",
+		ImportMore:          "…and %d more",
+		NothingToImport:     "No tasks found in this file",
+		ButtonImport:        "📥 Import %d",
+		TasksImported:       "Tasks imported: %d",
+		SendBulkTasks: "Send your tasks, one per line. A line may have #tags, a priority such as !high " +
+			"and a due date such as @tomorrow",
+		BulkPreview:       "📝 Tasks to create: %d",
+		BulkMore:          "…and %d more",
+		TooManyTasks:      "Up to %d tasks at once, please split the list",
+		ButtonCreateTasks: "✅ Create %d",
+		TasksCreated:      "Tasks created: %d",
+		CalendarFile:      "📅 Your tasks with due dates. Open the file to add them to your calendar app",
+		CalendarLink: "📅 Your tasks with due dates. Subscribe to this link in your calendar app to keep them " +
+			"up to date:\n%s\n\nAnyone who has the link can see these tasks",
+		ButtonResetCalendar: "🔄 New calendar link",
+		CalendarReset:       "The old calendar link no longer works",
+		CalDAVInfo: "🔄 Sync your tasks with a CalDAV app such as Apple Reminders, Thunderbird or DAVx⁵ " +
+			"(https://www.davx5.com).\n\nServer: <code>%s</code>\nUser name: <code>%d</code>\nApp passwords: %d\n\n" +
+			"Each list is a calendar, tasks without a list are in the inbox",
+		ButtonNewAppPassword:     "🔑 New app password",
+		ButtonRevokeAppPasswords: "🚫 Revoke all",
+		AppPasswordCreated:       "Your new app password, shown only this once:\n<code>%s</code>",
+		AppPasswordsRevoked:      "All app passwords were revoked, apps that used them are signed out",
+		ForwardPreview:           "📨 Create a task from the forwarded message?",
+		ForwardFrom:              "From %s",
+		ForwardNoText:            "The forwarded message has no text to make a task from",
+		ButtonCreateFromForward:  "✅ Create task from this",
+		Attached:                 "📎 Attached: %d",
+		ButtonAttachments:        "📎 Attachments (%d)",
+		TooManyAttachments:       "Up to 10 attachments per task",
+		ButtonComments:           "💬 Comments (%d)",
+		ButtonAddComment:         "✍️ Add comment",
+		HistoryTitle:             "History",
+		CommentsTitle:            "Comments: %d",
+		NoComments:               "No comments yet",
+		SendComment:              "Send your comment",
+		CommentAdded:             "Comment added",
+		CommentExpired:           "The comment took too long, please open the task again",
+		EmptyComment:             "The comment can't be empty",
+		CommentTooLong:           "Up to 500 characters per comment, please shorten it",
+		EventCreated:             "created",
+		EventRenamed:             "renamed to “%s”",
+		EventCompleted:           "completed",
+		EventReopened:            "reopened",
+		EventAssigned:            "moved to the list %s",
+		EventUnassigned:          "moved out of its list",
+		EventDeleted:             "deleted",
+		EventRestored:            "restored from the trash",
+		ButtonUndo:               "↩️ Undo",
+		TaskRestored:             "Task restored",
+		UndoExpired:              "It is too late to undo, the task can still be restored from /trash",
+		TrashTitle:               "🗑 Trash: %d",
+		TrashEmpty:               "The trash is empty",
+		DeletedOn:                "deleted %s",
+		ButtonEmptyTrash:         "🔥 Empty trash",
+		ConfirmEmptyTrash:        "Delete all %d tasks in the trash for good? This cannot be undone",
+		ButtonConfirmEmptyTrash:  "🔥 Delete for good",
+		TaskPurged:               "Task deleted for good",
+		TrashEmptied:             "Tasks deleted for good: %d",
+		EventArchived:            "archived",
+		EventUnarchived:          "moved back from the archive",
+		SettingArchive:           "🗄 Auto-archive: %s",
+		ButtonArchive:            "🗄 Auto-archive",
+		SendArchiveAfter:         "Send after how many days done tasks move to the /archive, from 1 to 365",
+		InvalidArchiveAfter:      "Send a number of days from 1 to 365, or off",
+		ArchiveEmpty:             "The archive is empty. Done tasks move here after the days set in /settings",
+		ArchiveNothing:           "Nothing in the archive matches \"%s\"",
+		ArchiveHeader:            "🗄 Archive: %d–%d of %d",
+		ArchiveSearchHeader:      "🗄 \"%s\" in the archive: %d–%d of %d",
+		ArchiveHint:              "📤 moves a task back to the list. Search with /archive followed by what to look for",
+		TaskUnarchived:           "Task moved back to the list",
+		NotArchived:              "This task is no longer in the archive",
+
+		CommandDescription("newtask"):    "Create a task, or /newtask name | description at once",
+		CommandDescription("add"):        "Add a task at once: /add name | description",
+		CommandDescription("list"):       "Show your tasks",
+		CommandDescription("search"):     "Search tasks: /search query",
+		CommandDescription("deletetask"): "Delete a task, or /deletetask name at once",
+		CommandDescription("pagesize"):   "Choose how many tasks a list page shows",
+		CommandDescription("language"):   "Change the bot language",
+		CommandDescription("settings"):   "Timezone, language and other preferences",
+		CommandDescription("export"):     "Download your tasks as JSON, CSV or Markdown",
+		CommandDescription("import"):     "Import tasks from a JSON, CSV or todo.txt file",
+		CommandDescription("bulk"):       "Create several tasks at once, one per line",
+		CommandDescription("ics"):        "Get your tasks with due dates as a calendar",
+		CommandDescription("caldav"):     "Sync tasks with CalDAV apps using app passwords",
+		CommandDescription("trash"):      "Restore or purge deleted tasks",
+		CommandDescription("archive"):    "Browse and search archived tasks",
+		CommandDescription("cancel"):     "Cancel the current action",
+		CommandDescription("start"):      "Restart the bot",
+	},
+	plurals: map[string]map[Form]string{
+		TrashPurgeAfter: {
+			One:   "Deleted tasks are purged for good after %d day",
+			Other: "Deleted tasks are purged for good after %d days",
+		},
+		ArchiveAfterDays: {
+			One:   "after %d day",
+			Other: "after %d days",
+		},
+		ListHeader: {
+			One:   "📋 %[1]d–%[2]d of %[3]d task · page %[4]d/%[5]d",
+			Other: "📋 %[1]d–%[2]d of %[3]d tasks · page %[4]d/%[5]d",
+		},
+		SearchHeader: {
+			One:   "🔎 \"%[1]s\": %[2]d–%[3]d of %[4]d match",
+			Other: "🔎 \"%[1]s\": %[2]d–%[3]d of %[4]d matches",
+		},
+	},
+}
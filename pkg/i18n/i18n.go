@@ -0,0 +1,114 @@
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const DefaultLanguage = "en"
+
+type Language struct {
+	Code string
+	Name string
+}
+
+var Languages = []Language{
+	{Code: "en", Name: "English"},
+	{Code: "uk", Name: "Українська"},
+}
+
+// Form is a CLDR plural category.
+type Form int
+
+const (
+	One Form = iota
+	Few
+	Many
+	Other
+)
+
+type translation struct {
+	plural   func(n int) Form
+	messages map[string]string
+	plurals  map[string]map[Form]string
+}
+
+var translations = map[string]translation{
+	"en": en,
+	"uk": uk,
+}
+
+// Match picks the supported language for an IETF tag such as Telegram's
+// language_code, falling back to DefaultLanguage.
+func Match(tag string) string {
+	base, _, _ := strings.Cut(strings.ToLower(tag), "-")
+	if _, ok := translations[base]; ok {
+		return base
+	}
+	return DefaultLanguage
+}
+
+func Supported(language string) bool {
+	_, ok := translations[language]
+	return ok
+}
+
+type Localizer struct {
+	language string
+}
+
+func For(language string) Localizer {
+	return Localizer{language: Match(language)}
+}
+
+func (l Localizer) Language() string {
+	return l.language
+}
+
+// T returns the message for key, formatted with args when there are any.
+// Missing translations fall back to English and then to the key itself.
+func (l Localizer) T(key string, args ...any) string {
+	message, ok := translations[l.language].messages[key]
+	if !ok {
+		message, ok = translations[DefaultLanguage].messages[key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// N returns the plural form of key that agrees with n. The format receives
+// args, so n has to be among them when it should be printed.
+func (l Localizer) N(key string, n int, args ...any) string {
+	tr := translations[l.language]
+	forms, ok := tr.plurals[key]
+	if !ok {
+		tr = translations[DefaultLanguage]
+		forms, ok = tr.plurals[key]
+	}
+	if !ok {
+		return key
+	}
+	message, ok := forms[tr.plural(n)]
+	if !ok {
+		message = forms[Other]
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+type contextKey struct{}
+
+func WithLanguage(ctx context.Context, language string) context.Context {
+	return context.WithValue(ctx, contextKey{}, language)
+}
+
+// FromContext returns the localizer for the language stored by WithLanguage.
+func FromContext(ctx context.Context) Localizer {
+	language, _ := ctx.Value(contextKey{}).(string)
+	return For(language)
+}
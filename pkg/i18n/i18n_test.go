@@ -0,0 +1,158 @@
+package i18n
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{"en", "en"},
+		{"uk", "uk"},
+		{"uk-UA", "uk"},
+		{"UK", "uk"},
+		{"en-GB", "en"},
+		{"de", DefaultLanguage},
+		{"", DefaultLanguage},
+	}
+	for _, tt := range tests {
+		if got := Match(tt.tag); got != tt.want {
+			t.Errorf("Match(%q) = %q, want %q", tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestPluralForms(t *testing.T) {
+	tests := []struct {
+		language string
+		n        int
+		want     Form
+	}{
+		{"en", 0, Other},
+		{"en", 1, One},
+		{"en", 2, Other},
+		{"en", 21, Other},
+		{"uk", 0, Many},
+		{"uk", 1, One},
+		{"uk", 2, Few},
+		{"uk", 4, Few},
+		{"uk", 5, Many},
+		{"uk", 11, Many},
+		{"uk", 12, Many},
+		{"uk", 14, Many},
+		{"uk", 21, One},
+		{"uk", 22, Few},
+		{"uk", 25, Many},
+		{"uk", 101, One},
+		{"uk", 111, Many},
+		{"uk", 112, Many},
+		{"uk", 122, Few},
+	}
+	for _, tt := range tests {
+		if got := translations[tt.language].plural(tt.n); got != tt.want {
+			t.Errorf("%s plural(%d) = %d, want %d", tt.language, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestLocalizer(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"message", For("uk").T(Menu), "Меню:"},
+		{"formatted", For("en").T(Hello, "Ann"), "Hello Ann!"},
+		{"unknown language", For("de").T(Menu), "Menu:"},
+		{"unknown key", For("uk").T("noSuchKey"), "noSuchKey"},
+		{"english plural", For("en").N(ArchiveAfterDays, 1, 1), "after 1 day"},
+		{"english plural other", For("en").N(ArchiveAfterDays, 7, 7), "after 7 days"},
+		{"ukrainian one", For("uk").N(ArchiveAfterDays, 21, 21), "через 21 день"},
+		{"ukrainian few", For("uk").N(ArchiveAfterDays, 3, 3), "через 3 дні"},
+		{"ukrainian many", For("uk").N(ArchiveAfterDays, 11, 11), "через 11 днів"},
+		{"unknown plural", For("en").N("noSuchKey", 2, 2), "noSuchKey"},
+		{"from context", FromContext(WithLanguage(context.Background(), "uk")).T(Menu), "Меню:"},
+		{"empty context", FromContext(context.Background()).T(Menu), "Menu:"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+var verb = regexp.MustCompile(`%(\[(\d+)\])?[-+# 0-9.]*[a-zA-Z%]`)
+
+// verbs maps each argument a format reads to the verb it reads it with.
+func verbs(format string) map[int]string {
+	verbs := map[int]string{}
+	next := 1
+	for _, match := range verb.FindAllStringSubmatch(format, -1) {
+		v := match[0][len(match[0])-1:]
+		if v == "%" {
+			continue
+		}
+		if match[2] != "" {
+			next, _ = strconv.Atoi(match[2])
+		}
+		verbs[next] = v
+		next++
+	}
+	return verbs
+}
+
+// TestCatalog checks that every language has the messages English has,
+// reading the same arguments, and every plural form it needs.
+func TestCatalog(t *testing.T) {
+	for _, language := range Languages {
+		tr, ok := translations[language.Code]
+		if !ok {
+			t.Errorf("%s: no translation", language.Code)
+			continue
+		}
+		for key, message := range en.messages {
+			translated, ok := tr.messages[key]
+			if !ok {
+				t.Errorf("%s: %s is missing", language.Code, key)
+				continue
+			}
+			if !reflect.DeepEqual(verbs(translated), verbs(message)) {
+				t.Errorf("%s: %s reads %v, English reads %v", language.Code, key, verbs(translated), verbs(message))
+			}
+		}
+		for key := range tr.messages {
+			if _, ok := en.messages[key]; !ok {
+				t.Errorf("%s: %s is not in English", language.Code, key)
+			}
+		}
+
+		forms := map[Form]bool{}
+		for n := 0; n < 200; n++ {
+			forms[tr.plural(n)] = true
+		}
+		for key, english := range en.plurals {
+			translated, ok := tr.plurals[key]
+			if !ok {
+				t.Errorf("%s: plural %s is missing", language.Code, key)
+				continue
+			}
+			for form := range forms {
+				message, ok := translated[form]
+				if !ok {
+					t.Errorf("%s: plural %s has no form %d", language.Code, key, form)
+					continue
+				}
+				if !reflect.DeepEqual(verbs(message), verbs(english[Other])) {
+					t.Errorf("%s: plural %s form %d reads %v, English reads %v", language.Code, key, form,
+						verbs(message), verbs(english[Other]))
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package i18n
+
+const (
+	Menu                     = "menu"
+	Hello                    = "hello"
+	SendTaskName             = "send_task_name"
+	SendTaskDescription      = "send_task_description"
+	CancelTaskCreation       = "cancel_task_creation"
+	CancelTaskDeletion       = "cancel_task_deletion"
+	Cancel                   = "cancel"
+	FinishLastAction         = "finish_last_action"
+	LastActionCanceled       = "last_action_canceled"
+	NothingToCancel          = "nothing_to_cancel"
+	TaskCreated              = "task_created"
+	TaskDeleted              = "task_deleted"
+	TaskNotFound             = "task_not_found"
+	ButtonNewTask            = "button_new_task"
+	ButtonListOfTasks        = "button_list_of_tasks"
+	ButtonDeleteTask         = "button_delete_task"
+	ButtonDeleteThis         = "button_delete_this"
+	ButtonComplete           = "button_complete"
+	ButtonReopen             = "button_reopen"
+	TaskStatusDone           = "task_status_done"
+	ButtonBack               = "button_back"
+	NoTasks                  = "no_tasks"
+	ListHeader               = "list_header"
+	SearchUsage              = "search_usage"
+	SearchNothing            = "search_nothing"
+	SearchExpired            = "search_expired"
+	SearchHeader             = "search_header"
+	TasksPerPage             = "tasks_per_page"
+	TasksPerPageSet          = "tasks_per_page_set"
+	ChooseLanguage           = "choose_language"
+	LanguageSet              = "language_set"
+	AmbiguousTaskName        = "ambiguous_task_name"
+	NotYourTask              = "not_your_task"
+	DraftExpired             = "draft_expired"
+	EmptyTaskName            = "empty_task_name"
+	InvalidPageSize          = "invalid_page_size"
+	UnsupportedLanguage      = "unsupported_language"
+	SomethingWentWrong       = "something_went_wrong"
+	ButtonRetry              = "button_retry"
+	SettingsHeader           = "settings_header"
+	SettingTimezone          = "setting_timezone"
+	SettingLanguage          = "setting_language"
+	SettingDateFormat        = "setting_date_format"
+	SettingDefaultList       = "setting_default_list"
+	SettingQuietHours        = "setting_quiet_hours"
+	SettingPageSize          = "setting_page_size"
+	SettingOff               = "setting_off"
+	SettingNoList            = "setting_no_list"
+	SettingSaved             = "setting_saved"
+	ButtonTimezone           = "button_timezone"
+	ButtonLanguage           = "button_language"
+	ButtonDateFormat         = "button_date_format"
+	ButtonDefaultList        = "button_default_list"
+	ButtonQuietHours         = "button_quiet_hours"
+	ButtonPageSize           = "button_page_size"
+	ButtonNoList             = "button_no_list"
+	ButtonTurnOff            = "button_turn_off"
+	SendTimezone             = "send_timezone"
+	ChooseDateFormat         = "choose_date_format"
+	SendDefaultList          = "send_default_list"
+	SendQuietHours           = "send_quiet_hours"
+	InvalidTimezone          = "invalid_timezone"
+	InvalidDateFormat        = "invalid_date_format"
+	InvalidListName          = "invalid_list_name"
+	InvalidQuietHours        = "invalid_quiet_hours"
+	SettingDigest            = "setting_digest"
+	ButtonDigest             = "button_digest"
+	SendDigestTime           = "send_digest_time"
+	InvalidDigestTime        = "invalid_digest_time"
+	DigestHeader             = "digest_header"
+	DigestOverdue            = "digest_overdue"
+	DigestDueToday           = "digest_due_today"
+	DigestNothingDue         = "digest_nothing_due"
+	DigestLists              = "digest_lists"
+	DigestNoList             = "digest_no_list"
+	DigestMore               = "digest_more"
+	TaskDone                 = "task_done"
+	ChooseExportFormat       = "choose_export_format"
+	ExportCaption            = "export_caption"
+	UnsupportedFormat        = "unsupported_format"
+	SendImportFile           = "send_import_file"
+	SendFileAsDocument       = "send_file_as_document"
+	UnreadableFile           = "unreadable_file"
+	ImportTooLarge           = "import_too_large"
+	PendingExpired           = "pending_expired"
+	ImportPreview            = "import_preview"
+	ImportFailed             = "import_failed"
+	ImportFailure            = "import_failure"
+	ImportMore               = "import_more"
+	NothingToImport          = "nothing_to_import"
+	ButtonImport             = "button_import"
+	TasksImported            = "tasks_imported"
+	SendBulkTasks            = "send_bulk_tasks"
+	BulkPreview              = "bulk_preview"
+	BulkMore                 = "bulk_more"
+	TooManyTasks             = "too_many_tasks"
+	ButtonCreateTasks        = "button_create_tasks"
+	TasksCreated             = "tasks_created"
+	CalendarFile             = "calendar_file"
+	CalendarLink             = "calendar_link"
+	ButtonResetCalendar      = "button_reset_calendar"
+	CalendarReset            = "calendar_reset"
+	CalDAVInfo               = "caldav_info"
+	ButtonNewAppPassword     = "button_new_app_password"
+	ButtonRevokeAppPasswords = "button_revoke_app_passwords"
+	AppPasswordCreated       = "app_password_created"
+	AppPasswordsRevoked      = "app_passwords_revoked"
+	ForwardPreview           = "forward_preview"
+	ForwardFrom              = "forward_from"
+	ForwardNoText            = "forward_no_text"
+	ButtonCreateFromForward  = "button_create_from_forward"
+	Attached                 = "attached"
+	ButtonAttachments        = "button_attachments"
+	TooManyAttachments       = "too_many_attachments"
+	ButtonComments           = "button_comments"
+	ButtonAddComment         = "button_add_comment"
+	HistoryTitle             = "history_title"
+	CommentsTitle            = "comments_title"
+	NoComments               = "no_comments"
+	SendComment              = "send_comment"
+	CommentAdded             = "comment_added"
+	CommentExpired           = "comment_expired"
+	EmptyComment             = "empty_comment"
+	CommentTooLong           = "comment_too_long"
+	EventCreated             = "event_created"
+	EventRenamed             = "event_renamed"
+	EventCompleted           = "event_completed"
+	EventReopened            = "event_reopened"
+	EventAssigned            = "event_assigned"
+	EventUnassigned          = "event_unassigned"
+	EventDeleted             = "event_deleted"
+	EventRestored            = "event_restored"
+	ButtonUndo               = "button_undo"
+	TaskRestored             = "task_restored"
+	UndoExpired              = "undo_expired"
+	TrashTitle               = "trash_title"
+	TrashEmpty               = "trash_empty"
+	TrashPurgeAfter          = "trash_purge_after"
+	DeletedOn                = "deleted_on"
+	ButtonEmptyTrash         = "button_empty_trash"
+	ConfirmEmptyTrash        = "confirm_empty_trash"
+	ButtonConfirmEmptyTrash  = "button_confirm_empty_trash"
+	TaskPurged               = "task_purged"
+	TrashEmptied             = "trash_emptied"
+	EventArchived            = "event_archived"
+	EventUnarchived          = "event_unarchived"
+	SettingArchive           = "setting_archive"
+	ButtonArchive            = "button_archive"
+	SendArchiveAfter         = "send_archive_after"
+	InvalidArchiveAfter      = "invalid_archive_after"
+	ArchiveAfterDays         = "archive_after_days"
+	ArchiveEmpty             = "archive_empty"
+	ArchiveNothing           = "archive_nothing"
+	ArchiveHeader            = "archive_header"
+	ArchiveSearchHeader      = "archive_search_header"
+	ArchiveHint              = "archive_hint"
+	TaskUnarchived           = "task_unarchived"
+	NotArchived              = "not_archived"
+)
+
+// CommandDescription is the key of the command menu entry for a command
+// name without its slash.
+func CommandDescription(command string) string {
+	return "command_" + command
+}
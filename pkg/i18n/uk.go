@@ -0,0 +1,215 @@
+package i18n
+
+var uk = translation{
+	plural: func(n int) Form {
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return One
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return Few
+		default:
+			return Many
+		}
+	},
+	messages: map[string]string{
+		Menu:                "Меню:",
+		Hello:               "Привіт, %s!",
+		SendTaskName:        "Надішліть назву задачі. Фото, файли й голосові, надіслані зараз, буде прикріплено до задачі",
+		SendTaskDescription: "Надішліть опис задачі",
+		CancelTaskCreation:  "Скасувати створення",
+		CancelTaskDeletion:  "Скасувати видалення",
+		Cancel:              "Скасувати",
+		FinishLastAction:    "Завершіть попередню дію або /cancel",
+		LastActionCanceled:  "Попередню дію скасовано",
+		NothingToCancel:     "Немає чого скасовувати",
+		TaskCreated:         "Задачу створено",
+		TaskDeleted:         "Задачу видалено",
+		TaskNotFound:        "Такої задачі немає",
+		ButtonNewTask:       "➕ Нова задача",
+		ButtonListOfTasks:   "📋 Список задач",
+		ButtonDeleteTask:    "🗑 Видалити задачу",
+		ButtonDeleteThis:    "Видалити цю задачу",
+		ButtonComplete:      "✅ Виконано",
+		ButtonReopen:        "↩️ Відкрити знову",
+		TaskStatusDone:      "Виконано",
+		ButtonBack:          "◀ Назад",
+		NoTasks:             "У вас немає задач",
+		SearchUsage:         "Надішліть /search і те, що шукаєте",
+		SearchNothing:       "За запитом \"%s\" нічого не знайдено",
+		SearchExpired:       "Цей пошук застарів. Надішліть /search і те, що шукаєте",
+		TasksPerPage:        "Задач на сторінці:",
+		TasksPerPageSet:     "Задач на сторінці: %d",
+		ChooseLanguage:      "Оберіть мову:",
+		LanguageSet:         "Мова: українська",
+		AmbiguousTaskName:   "Кілька задач мають таку назву, видаліть потрібну зі списку",
+		NotYourTask:         "Ця задача належить іншому користувачу",
+		DraftExpired:        "Час на створення задачі минув, почніть спочатку",
+		EmptyTaskName:       "Назва задачі не може бути порожньою",
+		InvalidPageSize:     "Такий розмір сторінки не підтримується",
+		UnsupportedLanguage: "Ця мова не підтримується",
+		SomethingWentWrong:  "Щось пішло не так, спробуйте ще раз (код %s)",
+		ButtonRetry:         "🔁 Повторити",
+		SettingsHeader:      "⚙️ Налаштування",
+		SettingTimezone:     "🌍 Часовий пояс: %s, зараз %s",
+		SettingLanguage:     "🗣 Мова: %s",
+		SettingDateFormat:   "📅 Формат дати: %s",
+		SettingDefaultList:  "📂 Список за замовчуванням: %s",
+		SettingQuietHours:   "🌙 Тихі години: %s",
+		SettingPageSize:     "📄 Задач на сторінці: %d",
+		SettingOff:          "вимкнено",
+		SettingNoList:       "немає",
+		SettingSaved:        "Збережено",
+		ButtonTimezone:      "🌍 Часовий пояс",
+		ButtonLanguage:      "🗣 Мова",
+		ButtonDateFormat:    "📅 Формат дати",
+		ButtonDefaultList:   "📂 Список",
+		ButtonQuietHours:    "🌙 Тихі години",
+		ButtonPageSize:      "📄 Розмір сторінки",
+		ButtonNoList:        "Без списку",
+		ButtonTurnOff:       "Вимкнути",
+		SendTimezone:        "Надішліть часовий пояс, наприклад Europe/Kyiv, або оберіть:",
+		ChooseDateFormat:    "Оберіть формат дати:",
+		SendDefaultList:     "Надішліть назву списку для нових задач, одним словом",
+		SendQuietHours:      "Надішліть години, коли бот мовчить, наприклад 22:00-07:00",
+		InvalidTimezone:     "Невідомий часовий пояс",
+		InvalidDateFormat:   "Такий формат дати не підтримується",
+		InvalidListName:     "Назва списку — одне слово до 32 символів",
+		InvalidQuietHours:   "Тихі години мають вигляд 22:00-07:00",
+		SettingDigest:       "☀️ Щоденний огляд: %s",
+		ButtonDigest:        "☀️ Огляд дня",
+		SendDigestTime:      "Надішліть місцевий час щоденного огляду, наприклад 08:00",
+		InvalidDigestTime:   "Час має вигляд 08:00",
+		DigestHeader:        "☀️ Ваш день, %s",
+		DigestOverdue:       "⏰ Прострочено: %d",
+		DigestDueToday:      "📅 На сьогодні: %d",
+		DigestNothingDue:    "На сьогодні нічого не заплановано",
+		DigestLists:         "📂 Відкриті задачі: %s",
+		DigestNoList:        "без списку",
+		DigestMore:          "…і ще %d",
+		TaskDone:            "Задачу виконано",
+		ChooseExportFormat:  "Оберіть формат файлу із задачами:",
+		ExportCaption:       "Ваші задачі, %s",
+		UnsupportedFormat:   "Такий формат файлу не підтримується",
+		SendImportFile:      "Надішліть файл JSON, CSV або todo.txt із задачами",
+		SendFileAsDocument:  "Надішліть задачі файлом",
+		UnreadableFile:      "Не вдалося прочитати файл",
+		ImportTooLarge:      "Файл завеликий, максимум 1 МБ",
+		PendingExpired:      "Час на підтвердження задач минув, надішліть їх ще раз",
+		ImportPreview:       "📥 %s: задач до імпорту — %d, відкритих %d, виконаних %d",
+		ImportFailed:        "⚠️ Рядків, які не вдалося прочитати: %d",
+		ImportFailure:       "рядок %d: %s",
+		ImportMore:          "…і ще %d",
+		NothingToImport:     "У файлі не знайдено задач",
+		ButtonImport:        "📥 Імпортувати %d",
+		TasksImported:       "Імпортовано задач: %d",
+		SendBulkTasks: "Надішліть задачі, по одній у рядку. Рядок може мати #теги, пріоритет, як-от !high, " +
+			"і термін, як-от @tomorrow",
+		BulkPreview:       "📝 Задач до створення: %d",
+		BulkMore:          "…і ще %d",
+		TooManyTasks:      "Не більше %d задач за раз, розділіть список",
+		ButtonCreateTasks: "✅ Створити %d",
+		TasksCreated:      "Створено задач: %d",
+		CalendarFile:      "📅 Ваші задачі з термінами. Відкрийте файл, щоб додати їх до календаря",
+		CalendarLink: "📅 Ваші задачі з термінами. Підпишіться на це посилання в календарі, щоб вони " +
+			"оновлювалися:\n%s\n\nКожен, хто має посилання, бачить ці задачі",
+		ButtonResetCalendar: "🔄 Нове посилання на календар",
+		CalendarReset:       "Старе посилання на календар більше не працює",
+		CalDAVInfo: "🔄 Синхронізуйте задачі із застосунком CalDAV, як-от Apple Reminders, Thunderbird або DAVx⁵ " +
+			"(https://www.davx5.com).\n\nСервер: <code>%s</code>\nІм'я користувача: <code>%d</code>\n" +
+			"Паролів застосунків: %d\n\n" +
+			"Кожен список — окремий календар, задачі без списку у вхідних",
+		ButtonNewAppPassword:     "🔑 Новий пароль застосунку",
+		ButtonRevokeAppPasswords: "🚫 Відкликати всі",
+		AppPasswordCreated:       "Ваш новий пароль застосунку, показаний лише раз:\n<code>%s</code>",
+		AppPasswordsRevoked:      "Усі паролі застосунків відкликано, застосунки, що їх використовували, вийшли",
+		ForwardPreview:           "📨 Створити задачу з пересланого повідомлення?",
+		ForwardFrom:              "Від %s",
+		ForwardNoText:            "У пересланому повідомленні немає тексту для задачі",
+		ButtonCreateFromForward:  "✅ Створити задачу з цього",
+		Attached:                 "📎 Прикріплено: %d",
+		ButtonAttachments:        "📎 Вкладення (%d)",
+		TooManyAttachments:       "Не більше 10 вкладень на задачу",
+		ButtonComments:           "💬 Коментарі (%d)",
+		ButtonAddComment:         "✍️ Додати коментар",
+		HistoryTitle:             "Історія",
+		CommentsTitle:            "Коментарі: %d",
+		NoComments:               "Коментарів ще немає",
+		SendComment:              "Надішліть коментар",
+		CommentAdded:             "Коментар додано",
+		CommentExpired:           "Час на коментар минув, відкрийте задачу знову",
+		EmptyComment:             "Коментар не може бути порожнім",
+		CommentTooLong:           "Не більше 500 символів у коментарі, скоротіть його",
+		EventCreated:             "створено",
+		EventRenamed:             "перейменовано на «%s»",
+		EventCompleted:           "виконано",
+		EventReopened:            "відкрито знову",
+		EventAssigned:            "переміщено до списку %s",
+		EventUnassigned:          "вилучено зі списку",
+		EventDeleted:             "видалено",
+		EventRestored:            "відновлено з кошика",
+		ButtonUndo:               "↩️ Скасувати",
+		TaskRestored:             "Задачу відновлено",
+		UndoExpired:              "Скасувати вже запізно, але задачу ще можна відновити з /trash",
+		TrashTitle:               "🗑 Кошик: %d",
+		TrashEmpty:               "Кошик порожній",
+		DeletedOn:                "видалено %s",
+		ButtonEmptyTrash:         "🔥 Очистити кошик",
+		ConfirmEmptyTrash:        "Остаточно видалити всі задачі з кошика (%d)? Це не можна скасувати",
+		ButtonConfirmEmptyTrash:  "🔥 Видалити остаточно",
+		TaskPurged:               "Задачу видалено остаточно",
+		TrashEmptied:             "Остаточно видалено задач: %d",
+		EventArchived:            "архівовано",
+		EventUnarchived:          "повернуто з архіву",
+		SettingArchive:           "🗄 Автоархів: %s",
+		ButtonArchive:            "🗄 Автоархів",
+		SendArchiveAfter:         "Надішліть, через скільки днів виконані задачі переходять в /archive, від 1 до 365",
+		InvalidArchiveAfter:      "Надішліть кількість днів від 1 до 365 або off",
+		ArchiveEmpty:             "Архів порожній. Виконані задачі потрапляють сюди через кількість днів із /settings",
+		ArchiveNothing:           "В архіві нічого не знайдено за запитом \"%s\"",
+		ArchiveHeader:            "🗄 Архів: %d–%d з %d",
+		ArchiveSearchHeader:      "🗄 \"%s\" в архіві: %d–%d з %d",
+		ArchiveHint:              "📤 повертає задачу до списку. Шукайте так: /archive і те, що шукаєте",
+		TaskUnarchived:           "Задачу повернуто до списку",
+		NotArchived:              "Цієї задачі вже немає в архіві",
+
+		CommandDescription("newtask"):    "Створити задачу, або одразу /newtask назва | опис",
+		CommandDescription("add"):        "Одразу додати задачу: /add назва | опис",
+		CommandDescription("list"):       "Показати задачі",
+		CommandDescription("search"):     "Пошук задач: /search запит",
+		CommandDescription("deletetask"): "Видалити задачу, або одразу /deletetask назва",
+		CommandDescription("pagesize"):   "Скільки задач показувати на сторінці",
+		CommandDescription("language"):   "Змінити мову бота",
+		CommandDescription("settings"):   "Часовий пояс, мова та інші налаштування",
+		CommandDescription("export"):     "Завантажити задачі як JSON, CSV або Markdown",
+		CommandDescription("import"):     "Імпортувати задачі з файлу JSON, CSV або todo.txt",
+		CommandDescription("bulk"):       "Створити кілька задач одразу, по одній у рядку",
+		CommandDescription("ics"):        "Отримати задачі з термінами як календар",
+		CommandDescription("caldav"):     "Синхронізувати задачі із застосунками CalDAV",
+		CommandDescription("trash"):      "Відновити або остаточно видалити задачі",
+		CommandDescription("archive"):    "Переглянути архів задач і шукати в ньому",
+		CommandDescription("cancel"):     "Скасувати поточну дію",
+		CommandDescription("start"):      "Перезапустити бота",
+	},
+	plurals: map[string]map[Form]string{
+		TrashPurgeAfter: {
+			One:  "Видалені задачі зникають остаточно через %d день",
+			Few:  "Видалені задачі зникають остаточно через %d дні",
+			Many: "Видалені задачі зникають остаточно через %d днів",
+		},
+		ArchiveAfterDays: {
+			One:  "через %d день",
+			Few:  "через %d дні",
+			Many: "через %d днів",
+		},
+		ListHeader: {
+			One:  "📋 %[3]d задача, показано %[1]d–%[2]d · сторінка %[4]d/%[5]d",
+			Few:  "📋 %[3]d задачі, показано %[1]d–%[2]d · сторінка %[4]d/%[5]d",
+			Many: "📋 %[3]d задач, показано %[1]d–%[2]d · сторінка %[4]d/%[5]d",
+		},
+		SearchHeader: {
+			One:  "🔎 \"%[1]s\": %[4]d збіг, показано %[2]d–%[3]d",
+			Few:  "🔎 \"%[1]s\": %[4]d збіги, показано %[2]d–%[3]d",
+			Many: "🔎 \"%[1]s\": %[4]d збігів, показано %[2]d–%[3]d",
+		},
+	},
+}
@@ -0,0 +1,189 @@
+// Package ical writes tasks as RFC 5545 iCalendar data for calendar and task
+// apps. Like export, it only depends on the task model.
+package ical
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"telegramBot/pkg/model/task"
+	"telegramBot/pkg/model/task/priority"
+	"telegramBot/pkg/model/task/repeat"
+	"telegramBot/pkg/model/task/status"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	ProdID      = "-//telegramBot//Tasks//EN"
+	ContentType = "text/calendar; charset=utf-8"
+	// uidDomain makes UIDs unique beyond this bot, as RFC 5545 asks.
+	uidDomain = "tasks.telegrambot"
+	// maxLine is the longest a content line may be, in octets, before it is
+	// folded.
+	maxLine = 75
+
+	dateLayout     = "20060102"
+	dateTimeLayout = "20060102T150405Z"
+)
+
+// UID identifies a task's VTODO in every calendar for as long as the task
+// exists: the one a CalDAV client gave it, or one derived from its ID.
+func UID(t task.Task) string {
+	if t.UID != "" {
+		return t.UID
+	}
+	return fmt.Sprintf("task-%d@%s", t.ID, uidDomain)
+}
+
+func eventUID(taskID int) string {
+	return fmt.Sprintf("task-%d-due@%s", taskID, uidDomain)
+}
+
+var frequencies = map[string]string{
+	repeat.Daily:   "DAILY",
+	repeat.Weekly:  "WEEKLY",
+	repeat.Monthly: "MONTHLY",
+	repeat.Yearly:  "YEARLY",
+}
+
+// priorities follow the RFC 5545 scale, where 1 is the highest and 0 means
+// undefined.
+var priorities = map[int]int{priority.High: 1, priority.Medium: 5, priority.Low: 9}
+
+// WriteFeed writes the calendar a user subscribes to. Every task with a due
+// date appears twice: as a VTODO for task apps, and as an all-day VEVENT for
+// calendar apps, most of which ignore VTODO.
+func WriteFeed(w io.Writer, name string, tasks []task.Task, now time.Time) error {
+	var c calendar
+	c.begin(name)
+	for _, t := range tasks {
+		if t.Status == status.Creating || t.Due.IsZero() {
+			continue
+		}
+		c.todo(t, now)
+		c.event(t, now)
+	}
+	c.property("END", "VCALENDAR")
+	_, err := io.WriteString(w, c.String())
+	return err
+}
+
+// WriteTodo writes a calendar object holding t alone, as CalDAV serves it.
+// The output only depends on t, so it can be hashed into an ETag: DTSTAMP is
+// the creation time rather than the current one.
+func WriteTodo(w io.Writer, t task.Task) error {
+	var c calendar
+	c.property("BEGIN", "VCALENDAR")
+	c.property("VERSION", "2.0")
+	c.property("PRODID", ProdID)
+	c.todo(t, t.CreatedAt)
+	c.property("END", "VCALENDAR")
+	_, err := io.WriteString(w, c.String())
+	return err
+}
+
+type calendar struct {
+	strings.Builder
+}
+
+func (c *calendar) begin(name string) {
+	c.property("BEGIN", "VCALENDAR")
+	c.property("VERSION", "2.0")
+	c.property("PRODID", ProdID)
+	c.property("CALSCALE", "GREGORIAN")
+	c.property("METHOD", "PUBLISH")
+	if name != "" {
+		c.property("X-WR-CALNAME", text(name))
+	}
+	c.property("REFRESH-INTERVAL;VALUE=DURATION", "PT1H")
+	c.property("X-PUBLISHED-TTL", "PT1H")
+}
+
+func (c *calendar) todo(t task.Task, now time.Time) {
+	c.property("BEGIN", "VTODO")
+	c.common(UID(t), t, now)
+	if !t.Due.IsZero() {
+		// A repeating VTODO needs a DTSTART to count from. It is the due
+		// day too, as a task has no start of its own.
+		if rule, ok := frequencies[t.Repeat]; ok {
+			c.property("DTSTART;VALUE=DATE", t.Due.Format(dateLayout))
+			c.property("RRULE", "FREQ="+rule)
+		}
+		c.property("DUE;VALUE=DATE", t.Due.Format(dateLayout))
+	}
+	if value, ok := priorities[t.Priority]; ok {
+		c.property("PRIORITY", fmt.Sprint(value))
+	}
+	if t.Status == status.Done {
+		c.property("STATUS", "COMPLETED")
+		c.property("PERCENT-COMPLETE", "100")
+	} else {
+		c.property("STATUS", "NEEDS-ACTION")
+	}
+	c.property("END", "VTODO")
+}
+
+func (c *calendar) event(t task.Task, now time.Time) {
+	c.property("BEGIN", "VEVENT")
+	if t.Status == status.Done {
+		t.TaskName = "✓ " + t.TaskName
+	}
+	c.common(eventUID(t.ID), t, now)
+	c.property("DTSTART;VALUE=DATE", t.Due.Format(dateLayout))
+	c.property("DTEND;VALUE=DATE", t.Due.AddDate(0, 0, 1).Format(dateLayout))
+	if rule, ok := frequencies[t.Repeat]; ok {
+		c.property("RRULE", "FREQ="+rule)
+	}
+	c.property("TRANSP", "TRANSPARENT")
+	c.property("END", "VEVENT")
+}
+
+// common writes the properties a VTODO and a VEVENT of a task share.
+func (c *calendar) common(uid string, t task.Task, now time.Time) {
+	c.property("UID", uid)
+	if now.IsZero() {
+		now = time.Unix(0, 0)
+	}
+	c.property("DTSTAMP", now.UTC().Format(dateTimeLayout))
+	if !t.CreatedAt.IsZero() {
+		c.property("CREATED", t.CreatedAt.UTC().Format(dateTimeLayout))
+	}
+	c.property("SUMMARY", text(t.TaskName))
+	if t.TaskDescription != "" {
+		c.property("DESCRIPTION", text(t.TaskDescription))
+	}
+	var categories []string
+	if t.List != "" {
+		categories = append(categories, text(t.List))
+	}
+	for _, tag := range t.Tags {
+		categories = append(categories, text(tag))
+	}
+	if len(categories) > 0 {
+		c.property("CATEGORIES", strings.Join(categories, ","))
+	}
+}
+
+// property writes a content line, folded so that no line exceeds maxLine
+// octets without splitting a character.
+func (c *calendar) property(name, value string) {
+	line := name + ":" + value
+	limit := maxLine
+	for len(line) > limit {
+		cut := limit
+		for !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		c.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		// The leading space of a continuation line counts too.
+		limit = maxLine - 1
+	}
+	c.WriteString(line + "\r\n")
+}
+
+// text escapes a TEXT value.
+func text(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", "").Replace(s)
+}
@@ -0,0 +1,251 @@
+package ical
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"telegramBot/pkg/model/task"
+	"telegramBot/pkg/model/task/priority"
+	"telegramBot/pkg/model/task/repeat"
+	"telegramBot/pkg/model/task/status"
+	"time"
+)
+
+var (
+	ErrNoTodo               = errors.New("no VTODO in the calendar object")
+	ErrUnsupportedComponent = errors.New("calendar object holds more than one task")
+)
+
+// property is a content line without its parameters, which no field of a
+// task depends on. The name is uppercased.
+type property struct {
+	name  string
+	value string
+}
+
+// ParseTodo reads the VTODO of a calendar object such as a CalDAV client
+// sends. The object must hold one task: VEVENTs, a second UID, and the
+// overrides of single occurrences are refused. Properties a task has no field
+// for are dropped, and so are recurrence rules it cannot express, and the
+// components nested in the VTODO, such as its VALARMs.
+func ParseTodo(data []byte) (task.Task, error) {
+	var t task.Task
+	var found, override bool
+	// depth counts the components open inside the VTODO, itself included, so
+	// that only its own properties are read.
+	depth := 0
+	for i, line := range unfold(string(data)) {
+		p, err := parseLine(line)
+		if err != nil {
+			return task.Task{}, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		switch {
+		case p.name == "BEGIN" && (strings.EqualFold(p.value, "VEVENT") || strings.EqualFold(p.value, "VJOURNAL")):
+			return task.Task{}, fmt.Errorf("%w: %s", ErrUnsupportedComponent, p.value)
+		case p.name == "BEGIN" && depth > 0:
+			depth++
+		case p.name == "END" && depth > 0:
+			depth--
+		case p.name == "BEGIN" && strings.EqualFold(p.value, "VTODO"):
+			if found {
+				return task.Task{}, ErrUnsupportedComponent
+			}
+			found, depth = true, 1
+			t = task.Task{Status: status.Created}
+		case depth == 1:
+			err = setProperty(&t, p)
+			if p.name == "RECURRENCE-ID" {
+				override = true
+			}
+		}
+		if err != nil {
+			return task.Task{}, fmt.Errorf("%s: %w", p.name, err)
+		}
+	}
+	if !found {
+		return task.Task{}, ErrNoTodo
+	}
+	if override {
+		return task.Task{}, fmt.Errorf("%w: RECURRENCE-ID", ErrUnsupportedComponent)
+	}
+	if strings.TrimSpace(t.TaskName) == "" {
+		return task.Task{}, errors.New("VTODO has no SUMMARY")
+	}
+	return t, nil
+}
+
+func setProperty(t *task.Task, p property) error {
+	switch p.name {
+	case "UID":
+		t.UID = p.value
+	case "SUMMARY":
+		t.TaskName = unescape(p.value)
+	case "DESCRIPTION":
+		t.TaskDescription = unescape(p.value)
+	case "DUE":
+		due, err := parseDay(p.value)
+		if err != nil {
+			return err
+		}
+		t.Due = due
+	case "DTSTART":
+		// A start date stands in for a due date only when there is none.
+		if t.Due.IsZero() {
+			due, err := parseDay(p.value)
+			if err != nil {
+				return err
+			}
+			t.Due = due
+		}
+	case "STATUS":
+		t.Status = status.Created
+		if strings.EqualFold(p.value, "COMPLETED") {
+			t.Status = status.Done
+		}
+	case "COMPLETED":
+		t.Status = status.Done
+	case "PRIORITY":
+		value, err := strconv.Atoi(p.value)
+		if err != nil || value < 0 || value > 9 {
+			return fmt.Errorf("%q is not between 0 and 9", p.value)
+		}
+		switch {
+		case value == 0:
+			t.Priority = priority.None
+		case value < 5:
+			t.Priority = priority.High
+		case value == 5:
+			t.Priority = priority.Medium
+		default:
+			t.Priority = priority.Low
+		}
+	case "RRULE":
+		t.Repeat = parseRule(p.value)
+	case "CATEGORIES":
+		for _, category := range splitList(p.value) {
+			tag := strings.ToLower(strings.Join(strings.Fields(unescape(category)), "-"))
+			if tag != "" {
+				t.Tags = append(t.Tags, tag)
+			}
+		}
+	}
+	return nil
+}
+
+// parseDay reads a DATE or DATE-TIME value as the calendar day it falls on
+// where it was written, which is what a due date is.
+func parseDay(value string) (time.Time, error) {
+	if len(value) < len(dateLayout) {
+		return time.Time{}, fmt.Errorf("%q is not a date", value)
+	}
+	day, err := time.Parse(dateLayout, value[:len(dateLayout)])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not a date", value)
+	}
+	return day, nil
+}
+
+// parseRule maps the recurrence rules a task can repeat by, a bare
+// frequency, to their repeat. Anything else reads as no repeat.
+func parseRule(rule string) string {
+	var frequency string
+	for _, part := range strings.Split(rule, ";") {
+		name, value, _ := strings.Cut(part, "=")
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			frequency = strings.ToUpper(value)
+		case "INTERVAL":
+			if value != "1" {
+				return repeat.None
+			}
+		case "WKST":
+		default:
+			return repeat.None
+		}
+	}
+	for name, rule := range frequencies {
+		if rule == frequency {
+			return name
+		}
+	}
+	return repeat.None
+}
+
+// unfold joins the continuation lines of data into their content lines.
+func unfold(data string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n") {
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func parseLine(line string) (property, error) {
+	// The value starts at the first colon outside a quoted parameter value.
+	quoted := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return property{}, fmt.Errorf("%q has no value", line)
+	}
+	name, _, _ := strings.Cut(line[:colon], ";")
+	return property{name: strings.ToUpper(name), value: line[colon+1:]}, nil
+}
+
+// splitList splits a list value at the commas that are not escaped.
+func splitList(value string) []string {
+	var items []string
+	var item strings.Builder
+	escaped := false
+	for _, r := range value {
+		switch {
+		case escaped:
+			item.WriteRune('\\')
+			item.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ',':
+			items = append(items, item.String())
+			item.Reset()
+		default:
+			item.WriteRune(r)
+		}
+	}
+	return append(items, item.String())
+}
+
+// unescape reverses text.
+func unescape(value string) string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range value {
+		switch {
+		case escaped && (r == 'n' || r == 'N'):
+			b.WriteRune('\n')
+		case escaped:
+			b.WriteRune(r)
+		case r == '\\':
+			escaped = true
+			continue
+		default:
+			b.WriteRune(r)
+		}
+		escaped = false
+	}
+	return b.String()
+}
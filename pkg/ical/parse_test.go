@@ -0,0 +1,162 @@
+package ical
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"telegramBot/pkg/model/task"
+	"telegramBot/pkg/model/task/priority"
+	"telegramBot/pkg/model/task/repeat"
+	"telegramBot/pkg/model/task/status"
+	"testing"
+	"time"
+)
+
+func calendarObject(lines ...string) []byte {
+	return []byte("BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" + strings.Join(lines, "\r\n") + "\r\nEND:VCALENDAR\r\n")
+}
+
+func TestParseTodoIgnoresNestedComponents(t *testing.T) {
+	data := calendarObject(
+		"BEGIN:VTODO",
+		"UID:task@example.com",
+		"SUMMARY:Pay rent",
+		"BEGIN:VALARM",
+		"ACTION:DISPLAY",
+		"UID:alarm@example.com",
+		"DESCRIPTION:Reminder",
+		"STATUS:COMPLETED",
+		"TRIGGER:-PT15M",
+		"END:VALARM",
+		"DESCRIPTION:Before the 5th",
+		"END:VTODO",
+	)
+	got, err := ParseTodo(data)
+	if err != nil {
+		t.Fatalf("ParseTodo() error = %v", err)
+	}
+	if got.UID != "task@example.com" {
+		t.Errorf("UID = %q, want the task's", got.UID)
+	}
+	if got.TaskDescription != "Before the 5th" {
+		t.Errorf("TaskDescription = %q, want the task's", got.TaskDescription)
+	}
+	if got.Status != status.Created {
+		t.Errorf("Status = %d, want %d", got.Status, status.Created)
+	}
+}
+
+func TestParseTodo(t *testing.T) {
+	due := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		lines []string
+		want  task.Task
+	}{
+		{
+			name: "every field",
+			lines: []string{"BEGIN:VTODO", "UID:rent@example.com", "SUMMARY:Pay rent\\, again",
+				"DESCRIPTION:Line one\\nLine two", "DUE;VALUE=DATE:20260105", "PRIORITY:1",
+				"RRULE:FREQ=MONTHLY", "CATEGORIES:Bills,Home Office", "END:VTODO"},
+			want: task.Task{UID: "rent@example.com", TaskName: "Pay rent, again",
+				TaskDescription: "Line one\nLine two", Due: due, Status: status.Created, Priority: priority.High,
+				Repeat: repeat.Monthly, Tags: []string{"bills", "home-office"}},
+		},
+		{
+			name:  "folded line",
+			lines: []string{"BEGIN:VTODO", "SUMMARY:Pay", " rent", "END:VTODO"},
+			want:  task.Task{TaskName: "Payrent", Status: status.Created},
+		},
+		{
+			name:  "completed",
+			lines: []string{"BEGIN:VTODO", "SUMMARY:Done", "STATUS:COMPLETED", "END:VTODO"},
+			want:  task.Task{TaskName: "Done", Status: status.Done},
+		},
+		{
+			name: "date-time due and start",
+			lines: []string{"BEGIN:VTODO", "SUMMARY:Call", "DTSTART:20260101T090000Z", "DUE:20260105T170000Z",
+				"END:VTODO"},
+			want: task.Task{TaskName: "Call", Status: status.Created, Due: due},
+		},
+		{
+			name: "start without due",
+			lines: []string{"BEGIN:VTODO", "SUMMARY:Call", "DTSTART;TZID=Europe/Kyiv:20260105T090000",
+				"END:VTODO"},
+			want: task.Task{TaskName: "Call", Status: status.Created, Due: due},
+		},
+		{
+			name:  "low priority",
+			lines: []string{"BEGIN:VTODO", "SUMMARY:Read", "PRIORITY:7", "END:VTODO"},
+			want:  task.Task{TaskName: "Read", Status: status.Created, Priority: priority.Low},
+		},
+		{
+			name:  "rule a task cannot repeat by",
+			lines: []string{"BEGIN:VTODO", "SUMMARY:Gym", "RRULE:FREQ=WEEKLY;BYDAY=MO,WE", "END:VTODO"},
+			want:  task.Task{TaskName: "Gym", Status: status.Created},
+		},
+		{
+			name: "timezone before the task",
+			lines: []string{"BEGIN:VTIMEZONE", "TZID:Europe/Kyiv", "BEGIN:STANDARD", "DTSTART:19701025T040000",
+				"END:STANDARD", "END:VTIMEZONE", "BEGIN:VTODO", "SUMMARY:Call", "END:VTODO"},
+			want: task.Task{TaskName: "Call", Status: status.Created},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTodo(calendarObject(tt.lines...))
+			if err != nil {
+				t.Fatalf("ParseTodo() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTodo() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTodoRefused(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		err   error
+	}{
+		{"no task", []string{"PRODID:-//Test//EN"}, ErrNoTodo},
+		{"event", []string{"BEGIN:VEVENT", "SUMMARY:Party", "END:VEVENT"}, ErrUnsupportedComponent},
+		{"two tasks", []string{"BEGIN:VTODO", "SUMMARY:A", "END:VTODO", "BEGIN:VTODO", "SUMMARY:B", "END:VTODO"},
+			ErrUnsupportedComponent},
+		{"override", []string{"BEGIN:VTODO", "SUMMARY:A", "RECURRENCE-ID:20260105", "END:VTODO"},
+			ErrUnsupportedComponent},
+		{"no summary", []string{"BEGIN:VTODO", "UID:a", "END:VTODO"}, nil},
+		{"bad priority", []string{"BEGIN:VTODO", "SUMMARY:A", "PRIORITY:high", "END:VTODO"}, nil},
+		{"bad due", []string{"BEGIN:VTODO", "SUMMARY:A", "DUE:soon", "END:VTODO"}, nil},
+		{"line without value", []string{"BEGIN:VTODO", "SUMMARY", "END:VTODO"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTodo(calendarObject(tt.lines...))
+			if err == nil || (tt.err != nil && !errors.Is(err, tt.err)) {
+				t.Errorf("ParseTodo() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestWriteTodoRoundTrip(t *testing.T) {
+	want := task.Task{ID: 7, TaskName: "Pay rent, again; twice", TaskDescription: "Line one\nLine two",
+		Status: status.Created, Priority: priority.Medium, Repeat: repeat.Weekly, Tags: []string{"bills"},
+		Due: time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC), CreatedAt: time.Date(2025, 12, 1, 10, 0, 0, 0, time.UTC)}
+	var data strings.Builder
+	err := WriteTodo(&data, want)
+	if err != nil {
+		t.Fatalf("WriteTodo() error = %v", err)
+	}
+	got, err := ParseTodo([]byte(data.String()))
+	if err != nil {
+		t.Fatalf("ParseTodo() error = %v", err)
+	}
+	if got.TaskName != want.TaskName || got.TaskDescription != want.TaskDescription || got.Status != want.Status ||
+		got.Priority != want.Priority || got.Repeat != want.Repeat || !got.Due.Equal(want.Due) ||
+		got.UID != UID(want) || !reflect.DeepEqual(got.Tags, want.Tags) {
+		t.Errorf("ParseTodo(WriteTodo()) =\n%+v\nwant\n%+v", got, want)
+	}
+}
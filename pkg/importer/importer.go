@@ -0,0 +1,357 @@
+// Package importer reads tasks from files made by this bot's export or by
+// other tools. Like export, it only depends on the task model.
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"telegramBot/pkg/export"
+	"telegramBot/pkg/model/task"
+	"telegramBot/pkg/model/task/priority"
+	"telegramBot/pkg/model/task/repeat"
+	"telegramBot/pkg/model/task/status"
+	"time"
+	"unicode/utf8"
+)
+
+type Format string
+
+const (
+	JSON    Format = "json"
+	CSV     Format = "csv"
+	TodoTxt Format = "todo.txt"
+)
+
+var ErrUnknownFormat = errors.New("unknown file format")
+
+// Failure is a row that could not be read. Line counts from 1; for JSON it
+// is the position of the task in the file.
+type Failure struct {
+	Line int
+	Text string
+	Err  error
+}
+
+type Result struct {
+	Format Format
+	Tasks  []task.Task
+	Failed []Failure
+}
+
+// Count returns how many tasks are open and done.
+func (r Result) Count() (open, done int) {
+	for _, t := range r.Tasks {
+		if t.Status == status.Done {
+			done++
+		} else {
+			open++
+		}
+	}
+	return open, done
+}
+
+// Detect guesses the format from the file name, then from the content.
+func Detect(name string, data []byte) (Format, error) {
+	name = strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(name, ".json"):
+		return JSON, nil
+	case strings.HasSuffix(name, ".csv"):
+		return CSV, nil
+	case path.Base(name) == "todo.txt" || path.Base(name) == "done.txt":
+		return TodoTxt, nil
+	}
+	if !utf8.Valid(data) {
+		return "", ErrUnknownFormat
+	}
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		return JSON, nil
+	}
+	firstLine, _, _ := strings.Cut(string(trimmed), "\n")
+	if header := strings.ToLower(firstLine); strings.Contains(header, ",") &&
+		(strings.Contains(header, "name") || strings.Contains(header, "title")) {
+		return CSV, nil
+	}
+	if strings.HasSuffix(name, ".txt") {
+		return TodoTxt, nil
+	}
+	return "", ErrUnknownFormat
+}
+
+// Parse reads data in format. Rows that cannot be read are reported in
+// Result.Failed; an error means the file as a whole is unreadable.
+func Parse(format Format, data []byte) (Result, error) {
+	switch format {
+	case JSON:
+		return parseJSON(data)
+	case CSV:
+		return parseCSV(data)
+	case TodoTxt:
+		return parseTodoTxt(data), nil
+	}
+	return Result{}, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+}
+
+func parseJSON(data []byte) (Result, error) {
+	var records []json.RawMessage
+	var document struct {
+		Tasks []json.RawMessage `json:"tasks"`
+	}
+	err := json.Unmarshal(data, &document)
+	if err == nil {
+		records = document.Tasks
+	} else if err := json.Unmarshal(data, &records); err != nil {
+		return Result{}, fmt.Errorf("json: %w", err)
+	}
+	result := Result{Format: JSON}
+	for i, raw := range records {
+		var record export.Task
+		err := json.Unmarshal(raw, &record)
+		if err == nil {
+			var t task.Task
+			t, err = fromRecord(record)
+			if err == nil {
+				result.Tasks = append(result.Tasks, t)
+				continue
+			}
+		}
+		result.Failed = append(result.Failed, Failure{Line: i + 1, Text: string(raw), Err: err})
+	}
+	return result, nil
+}
+
+// csvColumns maps the header names other tools use to export.Task fields.
+var csvColumns = map[string]string{
+	"name": "name", "title": "name", "task": "name", "summary": "name", "content": "name",
+	"description": "description", "notes": "description", "note": "description", "details": "description",
+	"status": "status", "done": "status", "completed": "status", "state": "status",
+	"priority": "priority", "importance": "priority",
+	"repeat": "repeat", "recurrence": "repeat", "recurring": "repeat",
+	"list": "list", "project": "list", "category": "list",
+	"tags": "tags", "labels": "tags",
+	"due": "due", "due date": "due", "deadline": "due", "due_date": "due",
+	"created": "created", "created at": "created", "created_at": "created",
+}
+
+func parseCSV(data []byte) (Result, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return Result{}, fmt.Errorf("csv header: %w", err)
+	}
+	fields := make(map[string]int)
+	for i, column := range header {
+		if field, ok := csvColumns[strings.ToLower(strings.TrimSpace(column))]; ok {
+			if _, seen := fields[field]; !seen {
+				fields[field] = i
+			}
+		}
+	}
+	if _, ok := fields["name"]; !ok {
+		return Result{}, errors.New("csv header: no name or title column")
+	}
+
+	result := Result{Format: CSV}
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			result.Failed = append(result.Failed, Failure{Line: parseErr.StartLine, Err: parseErr.Err})
+			continue
+		}
+		if err != nil {
+			return Result{}, fmt.Errorf("csv: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		value := func(field string) string {
+			if i, ok := fields[field]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+		record := export.Task{
+			Name:        value("name"),
+			Description: value("description"),
+			Status:      value("status"),
+			Priority:    value("priority"),
+			List:        value("list"),
+			Tags:        strings.FieldsFunc(value("tags"), func(r rune) bool { return r == ' ' || r == ',' }),
+			Due:         value("due"),
+			Repeat:      value("repeat"),
+			Created:     value("created"),
+		}
+		t, err := fromRecord(record)
+		if err != nil {
+			result.Failed = append(result.Failed, Failure{Line: line, Text: strings.Join(row, ","), Err: err})
+			continue
+		}
+		result.Tasks = append(result.Tasks, t)
+	}
+	return result, nil
+}
+
+func fromRecord(record export.Task) (task.Task, error) {
+	t := task.Task{
+		TaskName:        strings.TrimSpace(record.Name),
+		TaskDescription: strings.TrimSpace(record.Description),
+		List:            strings.ToLower(strings.TrimSpace(record.List)),
+		Status:          status.Created,
+	}
+	if t.TaskName == "" {
+		return task.Task{}, errors.New("no name")
+	}
+	for _, tag := range record.Tags {
+		tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+		if tag != "" {
+			t.Tags = append(t.Tags, tag)
+		}
+	}
+	switch strings.ToLower(strings.TrimSpace(record.Status)) {
+	case "", "open", "todo", "active", "false", "no", "0":
+	case "done", "completed", "complete", "closed", "x", "true", "yes", "1":
+		t.Status = status.Done
+	default:
+		return task.Task{}, fmt.Errorf("unknown status %q", record.Status)
+	}
+	if record.Priority != "" {
+		var ok bool
+		t.Priority, ok = priority.Parse(record.Priority)
+		if !ok {
+			return task.Task{}, fmt.Errorf("unknown priority %q", record.Priority)
+		}
+	}
+	if record.Repeat != "" {
+		var ok bool
+		t.Repeat, ok = repeat.Parse(record.Repeat)
+		if !ok {
+			return task.Task{}, fmt.Errorf("unknown repeat %q", record.Repeat)
+		}
+	}
+	if record.Due != "" {
+		due, err := time.Parse(task.DateLayout, strings.TrimSpace(record.Due))
+		if err != nil {
+			return task.Task{}, fmt.Errorf("due date %q is not YYYY-MM-DD", record.Due)
+		}
+		t.Due = due
+	}
+	if record.Created != "" {
+		created, err := time.Parse(time.RFC3339, strings.TrimSpace(record.Created))
+		if err != nil {
+			created, err = time.Parse(task.DateLayout, strings.TrimSpace(record.Created))
+		}
+		if err != nil {
+			return task.Task{}, fmt.Errorf("creation date %q is not RFC 3339", record.Created)
+		}
+		t.CreatedAt = created
+	}
+	return t, nil
+}
+
+// parseTodoTxt reads the todo.txt format, https://github.com/todotxt/todo.txt.
+// The first +project becomes the list, @contexts and further projects become
+// tags, the priorities (A), (B) and anything lower become high, medium and
+// low, and rec:d, rec:w, rec:m or rec:y sets the repeat.
+func parseTodoTxt(data []byte) Result {
+	result := Result{Format: TodoTxt}
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		t, err := parseTodoTxtLine(line)
+		if err != nil {
+			result.Failed = append(result.Failed, Failure{Line: i + 1, Text: line, Err: err})
+			continue
+		}
+		result.Tasks = append(result.Tasks, t)
+	}
+	return result
+}
+
+func parseTodoTxtLine(line string) (task.Task, error) {
+	t := task.Task{Status: status.Created}
+	words := strings.Fields(line)
+	if len(words) > 0 && words[0] == "x" {
+		t.Status = status.Done
+		words = words[1:]
+		// A completed task may carry its completion date first.
+		if len(words) > 0 && isDate(words[0]) {
+			words = words[1:]
+		}
+	}
+	if len(words) > 0 && len(words[0]) == 3 && words[0][0] == '(' && words[0][2] == ')' &&
+		words[0][1] >= 'A' && words[0][1] <= 'Z' {
+		switch words[0][1] {
+		case 'A':
+			t.Priority = priority.High
+		case 'B':
+			t.Priority = priority.Medium
+		default:
+			t.Priority = priority.Low
+		}
+		words = words[1:]
+	}
+	if len(words) > 0 && isDate(words[0]) {
+		t.CreatedAt, _ = time.Parse(task.DateLayout, words[0])
+		words = words[1:]
+	}
+
+	var name []string
+	for _, word := range words {
+		switch {
+		case len(word) > 1 && word[0] == '+':
+			project := strings.ToLower(word[1:])
+			if t.List == "" {
+				t.List = project
+			} else {
+				t.Tags = append(t.Tags, project)
+			}
+		case len(word) > 1 && word[0] == '@':
+			t.Tags = append(t.Tags, strings.ToLower(word[1:]))
+		case strings.HasPrefix(word, "due:"):
+			due, err := time.Parse(task.DateLayout, strings.TrimPrefix(word, "due:"))
+			if err != nil {
+				return task.Task{}, fmt.Errorf("due date %q is not YYYY-MM-DD", word)
+			}
+			t.Due = due
+		case strings.HasPrefix(word, "rec:"):
+			frequency, ok := todoTxtRepeat[strings.TrimPrefix(strings.TrimPrefix(word, "rec:"), "+")]
+			if !ok {
+				return task.Task{}, fmt.Errorf("unsupported recurrence %q", word)
+			}
+			t.Repeat = frequency
+		default:
+			name = append(name, word)
+		}
+	}
+	t.TaskName = strings.Join(name, " ")
+	if t.TaskName == "" {
+		return task.Task{}, errors.New("no name")
+	}
+	return t, nil
+}
+
+// todoTxtRepeat maps the rec: values of the todo.txt recurrence extension
+// that have a repeat frequency.
+var todoTxtRepeat = map[string]string{
+	"d": repeat.Daily, "1d": repeat.Daily,
+	"w": repeat.Weekly, "1w": repeat.Weekly,
+	"m": repeat.Monthly, "1m": repeat.Monthly,
+	"y": repeat.Yearly, "1y": repeat.Yearly,
+}
+
+func isDate(word string) bool {
+	_, err := time.Parse(task.DateLayout, word)
+	return err == nil
+}
@@ -0,0 +1,157 @@
+package importer
+
+import (
+	"errors"
+	"reflect"
+	"telegramBot/pkg/model/task"
+	"telegramBot/pkg/model/task/priority"
+	"telegramBot/pkg/model/task/repeat"
+	"telegramBot/pkg/model/task/status"
+	"testing"
+	"time"
+)
+
+func date(s string) time.Time {
+	d, err := time.Parse(task.DateLayout, s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		data string
+		want Format
+		err  error
+	}{
+		{"json by name", "tasks.JSON", "", JSON, nil},
+		{"csv by name", "tasks.csv", "", CSV, nil},
+		{"todo.txt by name", "backup/todo.txt", "", TodoTxt, nil},
+		{"done.txt by name", "done.txt", "", TodoTxt, nil},
+		{"json object", "export", ` {"tasks": []}`, JSON, nil},
+		{"json array", "export", `[{"name": "a"}]`, JSON, nil},
+		{"csv header", "export", "Title,Due\nPay rent,2026-01-05", CSV, nil},
+		{"other txt", "notes.txt", "Pay rent", TodoTxt, nil},
+		{"unknown", "notes", "Pay rent", "", ErrUnknownFormat},
+		{"binary", "photo", "\xff\xd8\xff", "", ErrUnknownFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Detect(tt.file, []byte(tt.data))
+			if got != tt.want || !errors.Is(err, tt.err) {
+				t.Errorf("Detect() = %q, %v, want %q, %v", got, err, tt.want, tt.err)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		data   string
+		want   []task.Task
+		failed []int
+	}{
+		{
+			name:   "json export",
+			format: JSON,
+			data: `{"tasks": [
+				{"name": "Pay rent", "status": "open", "priority": "high", "list": "Home", "tags": ["#bills"],
+					"due": "2026-01-05", "repeat": "monthly", "created": "2025-12-01T10:00:00Z"},
+				{"name": "Old", "status": "done"},
+				{"name": "", "status": "open"},
+				{"name": "Bad", "status": "maybe"}
+			]}`,
+			want: []task.Task{
+				{TaskName: "Pay rent", Status: status.Created, Priority: priority.High, List: "home",
+					Tags: []string{"bills"}, Due: date("2026-01-05"), Repeat: repeat.Monthly,
+					CreatedAt: time.Date(2025, 12, 1, 10, 0, 0, 0, time.UTC)},
+				{TaskName: "Old", Status: status.Done},
+			},
+			failed: []int{3, 4},
+		},
+		{
+			name:   "json array",
+			format: JSON,
+			data:   `[{"name": "Call mum", "description": "Sunday"}]`,
+			want:   []task.Task{{TaskName: "Call mum", TaskDescription: "Sunday", Status: status.Created}},
+		},
+		{
+			name:   "csv from another tool",
+			format: CSV,
+			data: "\xef\xbb\xbfTitle,Notes,Completed,Labels,Deadline\n" +
+				"Pay rent,Before the 5th,no,\"bills, home\",2026-01-05\n" +
+				"Old,,yes,,\n" +
+				"Bad date,,,,05/01/2026\n",
+			want: []task.Task{
+				{TaskName: "Pay rent", TaskDescription: "Before the 5th", Status: status.Created,
+					Tags: []string{"bills", "home"}, Due: date("2026-01-05")},
+				{TaskName: "Old", Status: status.Done},
+			},
+			failed: []int{4},
+		},
+		{
+			name:   "todo.txt",
+			format: TodoTxt,
+			data: "(A) 2026-01-01 Pay rent +home +bills @phone due:2026-01-05 rec:1m\n" +
+				"\n" +
+				"x 2026-01-02 2025-12-30 Old task\n" +
+				"(C) Someday\n" +
+				"Broken due:soon\n" +
+				"Weird rec:2w\n",
+			want: []task.Task{
+				{TaskName: "Pay rent", Status: status.Created, Priority: priority.High, List: "home",
+					Tags: []string{"bills", "phone"}, Due: date("2026-01-05"), Repeat: repeat.Monthly,
+					CreatedAt: date("2026-01-01")},
+				{TaskName: "Old task", Status: status.Done, CreatedAt: date("2025-12-30")},
+				{TaskName: "Someday", Status: status.Created, Priority: priority.Low},
+			},
+			failed: []int{5, 6},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.format, []byte(tt.data))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got.Format != tt.format {
+				t.Errorf("Format = %q, want %q", got.Format, tt.format)
+			}
+			if !reflect.DeepEqual(got.Tasks, tt.want) {
+				t.Errorf("Tasks =\n%+v\nwant\n%+v", got.Tasks, tt.want)
+			}
+			var failed []int
+			for _, failure := range got.Failed {
+				failed = append(failed, failure.Line)
+			}
+			if !reflect.DeepEqual(failed, tt.failed) {
+				t.Errorf("failed lines = %v, want %v", failed, tt.failed)
+			}
+		})
+	}
+}
+
+func TestParseUnreadable(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		data   string
+	}{
+		{"json", JSON, `{"tasks": [`},
+		{"csv without a name column", CSV, "due,notes\n2026-01-05,x\n"},
+		{"unknown format", "xml", "<tasks/>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.format, []byte(tt.data))
+			if err == nil {
+				t.Error("Parse() error = nil, want one")
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package profile
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultTimezone   = "UTC"
+	DefaultDateFormat = "2006-01-02"
+	DefaultPageSize   = 5
+	DefaultDigestAt   = 8 * 60
+)
+
+// DateFormats are the layouts a user can pick, in time.Format notation.
+var DateFormats = []string{DefaultDateFormat, "02.01.2006", "01/02/2006", "2 Jan 2006"}
+
+// QuietHours is a daily window, in minutes after local midnight, in which the
+// bot sends nothing on its own. It may wrap past midnight; From == To turns it
+// off.
+type QuietHours struct {
+	From int
+	To   int
+}
+
+func (q QuietHours) Enabled() bool {
+	return q.From != q.To
+}
+
+func (q QuietHours) Contains(minute int) bool {
+	if !q.Enabled() {
+		return false
+	}
+	if q.From < q.To {
+		return minute >= q.From && minute < q.To
+	}
+	return minute >= q.From || minute < q.To
+}
+
+func (q QuietHours) String() string {
+	return FormatClock(q.From) + "–" + FormatClock(q.To)
+}
+
+// ParseQuietHours reads a window such as "22:00-07:00".
+func ParseQuietHours(s string) (QuietHours, error) {
+	s = strings.ReplaceAll(s, "–", "-")
+	from, to, found := strings.Cut(s, "-")
+	if !found {
+		return QuietHours{}, errors.New("expected from-to")
+	}
+	var q QuietHours
+	var err error
+	q.From, err = ParseClock(from)
+	if err != nil {
+		return QuietHours{}, err
+	}
+	q.To, err = ParseClock(to)
+	if err != nil {
+		return QuietHours{}, err
+	}
+	return q, nil
+}
+
+// ParseClock reads a time of day such as "08:30" as minutes after midnight.
+func ParseClock(s string) (int, error) {
+	clock, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+	return clock.Hour()*60 + clock.Minute(), nil
+}
+
+type Profile struct {
+	UserID   int64
+	Timezone string
+	// Language is empty until the user picks one; the client's language is
+	// used meanwhile.
+	Language    string
+	DateFormat  string
+	DefaultList string
+	QuietHours  QuietHours
+	PageSize    int
+	// DigestEnabled opts the user into a daily digest at DigestAt, in minutes
+	// after local midnight.
+	DigestEnabled bool
+	DigestAt      int
+	// DigestSent is the local day, as 2006-01-02, the last digest went
+	// out. Only the digest scheduler writes it.
+	DigestSent string
+	// ArchiveAfter is how many days after completion a done task moves to
+	// the archive; 0 keeps it in the list.
+	ArchiveAfter int
+}
+
+func Default(userID int64) Profile {
+	return Profile{
+		UserID:     userID,
+		Timezone:   DefaultTimezone,
+		DateFormat: DefaultDateFormat,
+		PageSize:   DefaultPageSize,
+		DigestAt:   DefaultDigestAt,
+	}
+}
+
+// Location falls back to UTC for a timezone the system no longer knows.
+func (p Profile) Location() *time.Location {
+	location, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+func (p Profile) Now(now time.Time) time.Time {
+	return now.In(p.Location())
+}
+
+// Quiet reports whether t falls in the user's quiet hours.
+func (p Profile) Quiet(t time.Time) bool {
+	local := p.Now(t)
+	return p.QuietHours.Contains(local.Hour()*60 + local.Minute())
+}
+
+func (p Profile) FormatDate(t time.Time) string {
+	return p.Now(t).Format(p.DateFormat)
+}
+
+func FormatClock(minute int) string {
+	return fmt.Sprintf("%02d:%02d", minute/60, minute%60)
+}
+
+// DigestDue reports whether the daily digest should go out at now: the chosen
+// local time has passed, no digest has been sent for today or a later day,
+// and the user is not in quiet hours. A digest held back by quiet hours goes
+// out when they end, as long as that is still the same day. Later days matter
+// after a move west, which can make a day the user already had come again.
+func (p Profile) DigestDue(now time.Time) bool {
+	if !p.DigestEnabled || p.Quiet(now) {
+		return false
+	}
+	local := p.Now(now)
+	// DefaultDateFormat dates sort as strings do.
+	return local.Hour()*60+local.Minute() >= p.DigestAt && local.Format(DefaultDateFormat) > p.DigestSent
+}
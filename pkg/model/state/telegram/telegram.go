@@ -1,9 +1,66 @@
 package telegram
 
+// Commands are lowercase so Telegram accepts them in the bot command menu.
 const (
 	StartState            = "/start"
-	NewTaskState          = "/newTask"
-	DeleteTaskState       = "/deleteTask"
-	ListOfTasksState      = "/listOfTasks"
-	CancelLastActionState = "/cancelLastAction"
+	NewTaskState          = "/newtask"
+	AddTaskState          = "/add"
+	DeleteTaskState       = "/deletetask"
+	ListOfTasksState      = "/list"
+	CancelLastActionState = "/cancel"
+	SearchState           = "/search"
+	PageSizeState         = "/pagesize"
+	LanguageState         = "/language"
+	SettingsState         = "/settings"
+	ExportState           = "/export"
+	ImportState           = "/import"
+	BulkState             = "/bulk"
+	CalendarState         = "/ics"
+	CalDAVState           = "/caldav"
+	TrashState            = "/trash"
+	ArchiveState          = "/archive"
+)
+
+// Aliases maps lowercased former command names, still present in old chats
+// and callback buttons, to their current names.
+var Aliases = map[string]string{
+	"/listoftasks":      ListOfTasksState,
+	"/cancellastaction": CancelLastActionState,
+}
+
+const (
+	DeleteTaskButton         = "/buttonDeleteTask"
+	SearchPageButton         = "/searchPage"
+	ListPageButton           = "/listPage"
+	OpenTaskButton           = "/openTask"
+	PageSizeButton           = "/setPageSize"
+	LanguageButton           = "/setLanguage"
+	RetryButton              = "/retry"
+	SettingButton            = "/editSetting"
+	DateFormatButton         = "/setDateFormat"
+	TimezoneButton           = "/setTimezone"
+	NoListButton             = "/setNoList"
+	QuietOffButton           = "/setQuietOff"
+	DigestOffButton          = "/setDigestOff"
+	DoneTaskButton           = "/doneTask"
+	CompleteTaskButton       = "/completeTask"
+	ReopenTaskButton         = "/reopenTask"
+	ExportButton             = "/exportAs"
+	ImportButton             = "/importConfirm"
+	BulkButton               = "/bulkConfirm"
+	ResetCalendarButton      = "/resetCalendar"
+	NewAppPasswordButton     = "/newAppPassword"
+	RevokeAppPasswordsButton = "/revokeAppPasswords"
+	ForwardButton            = "/forwardConfirm"
+	AttachmentsButton        = "/attachments"
+	CommentsButton           = "/comments"
+	AddCommentButton         = "/addComment"
+	UndoDeleteButton         = "/undoDelete"
+	RestoreTaskButton        = "/restoreTask"
+	PurgeTaskButton          = "/purgeTask"
+	EmptyTrashButton         = "/emptyTrash"
+	ConfirmEmptyTrashButton  = "/confirmEmptyTrash"
+	ArchivePageButton        = "/archivePage"
+	UnarchiveButton          = "/unarchive"
+	ArchiveAfterButton       = "/setArchiveAfter"
 )
@@ -5,4 +5,12 @@ const (
 	WaitingForNewTaskName
 	WaitingForNewTaskDescription
 	WaitingForTaskNameToBeDeleted
+	WaitingForTimezone
+	WaitingForDefaultList
+	WaitingForQuietHours
+	WaitingForDigestTime
+	WaitingForImportFile
+	WaitingForBulkTasks
+	WaitingForComment
+	WaitingForArchiveAfter
 )
@@ -0,0 +1,23 @@
+package task
+
+import "time"
+
+// Attachment kinds, named after the Telegram media they were sent as.
+const (
+	Photo    = "photo"
+	Document = "document"
+	Voice    = "voice"
+)
+
+// Attachment is a file sent to the bot. Only Telegram's file ID is kept, so
+// the file can be sent again but not downloaded outside Telegram.
+type Attachment struct {
+	ID        int64
+	TaskID    int64
+	Kind      string
+	FileID    string
+	FileName  string
+	MimeType  string
+	Size      int64
+	CreatedAt time.Time
+}
@@ -0,0 +1,39 @@
+package task
+
+import "time"
+
+// Event kinds in a task's history.
+const (
+	EventCreated   = "created"
+	EventRenamed   = "renamed"
+	EventCompleted = "completed"
+	EventReopened  = "reopened"
+	// EventAssigned is a move to another list; Detail names the list.
+	EventAssigned = "assigned"
+	EventDeleted  = "deleted"
+	EventRestored = "restored"
+	EventArchived = "archived"
+	// EventUnarchived is a move from the archive back to the list.
+	EventUnarchived = "unarchived"
+)
+
+// Event is something that happened to a task. UserID owns the task and
+// ActorID did it. Detail holds the new name of a rename or the list of an
+// assignment.
+type Event struct {
+	ID        int64
+	TaskID    int64
+	UserID    int64
+	ActorID   int64
+	Kind      string
+	Detail    string
+	CreatedAt time.Time
+}
+
+type Comment struct {
+	ID        int64
+	TaskID    int64
+	AuthorID  int64
+	Text      string
+	CreatedAt time.Time
+}
@@ -1,8 +1,43 @@
 package task
 
+import "time"
+
+// DateLayout is how due dates, which are calendar days without a time of day
+// or a timezone, are stored and exchanged.
+const DateLayout = "2006-01-02"
+
 type Task struct {
 	ID              int
 	TaskName        string
 	TaskDescription string
 	ChatId          int64
+	Tags            []string
+	List            string
+	Status          int
+	// Priority is one of the priority package constants.
+	Priority int
+	// Repeat is one of the repeat package frequencies, counted from Due.
+	Repeat string
+	// UID and Resource keep the names a CalDAV client gave a task it
+	// created. Tasks created anywhere else leave them empty.
+	UID      string
+	Resource string
+	// Due is a calendar day at midnight UTC, zero when the task has none.
+	Due       time.Time
+	CreatedAt time.Time
+	// CompletedAt is when the task was last marked done, zero while it is
+	// open.
+	CompletedAt time.Time
+	// DeletedAt is when the task went to the trash, zero for live tasks.
+	DeletedAt time.Time
+	// Attachments are saved along with a new task. Tasks read from storage
+	// leave them out.
+	Attachments []Attachment
+}
+
+// Day truncates t to its calendar day in t's location, expressed the way Due
+// is.
+func Day(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
 }
@@ -0,0 +1,29 @@
+package task
+
+type Page struct {
+	Tasks  []Task
+	Number int
+	Size   int
+	Total  int
+	// Attachments counts the attachments of the tasks on the page by ID.
+	Attachments map[int]int
+}
+
+func (p Page) Pages() int {
+	if p.Size <= 0 || p.Total == 0 {
+		return 1
+	}
+	return (p.Total + p.Size - 1) / p.Size
+}
+
+func (p Page) First() int {
+	return p.Number*p.Size + 1
+}
+
+func (p Page) HasPrevious() bool {
+	return p.Number > 0
+}
+
+func (p Page) HasNext() bool {
+	return p.Number+1 < p.Pages()
+}
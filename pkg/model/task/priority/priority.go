@@ -0,0 +1,32 @@
+package priority
+
+import "strings"
+
+const (
+	None = iota
+	Low
+	Medium
+	High
+)
+
+var names = map[int]string{Low: "low", Medium: "medium", High: "high"}
+
+// Name returns the name a priority is written with in markers and exports,
+// empty for None.
+func Name(p int) string {
+	return names[p]
+}
+
+// Parse reads a priority name as Name writes it, or a short form such as
+// "med". ok is false for anything else.
+func Parse(name string) (p int, ok bool) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "low", "lo":
+		return Low, true
+	case "medium", "med", "normal":
+		return Medium, true
+	case "high", "hi", "urgent":
+		return High, true
+	}
+	return None, false
+}
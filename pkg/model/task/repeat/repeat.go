@@ -0,0 +1,25 @@
+package repeat
+
+import "strings"
+
+// A task repeats from its due date at one of these frequencies, or not at
+// all.
+const (
+	None    = ""
+	Daily   = "daily"
+	Weekly  = "weekly"
+	Monthly = "monthly"
+	Yearly  = "yearly"
+)
+
+// Parse reads a frequency as the constants write it, or "annually". ok is
+// false for anything else, None included.
+func Parse(name string) (frequency string, ok bool) {
+	switch name = strings.ToLower(strings.TrimSpace(name)); name {
+	case Daily, Weekly, Monthly, Yearly:
+		return name, true
+	case "annually":
+		return Yearly, true
+	}
+	return None, false
+}
@@ -0,0 +1,18 @@
+package task
+
+// Snippets mark matched terms with these control characters so every
+// frontend can render highlighting in its own markup.
+const (
+	HighlightStart = "\x02"
+	HighlightEnd   = "\x03"
+)
+
+type Match struct {
+	Task    Task
+	Snippet string
+}
+
+type SearchResult struct {
+	Matches []Match
+	Total   int
+}
@@ -4,4 +4,7 @@ const (
 	Creating = iota
 	Created
 	Done
+	// Archived tasks are done tasks moved out of the list, see
+	// profile.Profile.ArchiveAfter.
+	Archived
 )